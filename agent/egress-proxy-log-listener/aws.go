@@ -0,0 +1,28 @@
+package egress_proxy_log_listener
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/viper"
+)
+
+// NewAWSConfig loads the Amazon Web Services configuration used by agent components (aws.region, aws.endpoint).
+func NewAWSConfig() (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(viper.GetString("aws.region")),
+	}
+	if endpoint := viper.GetString("aws.endpoint"); endpoint != "" {
+		// enables local stand-ins such as LocalStack
+		opts = append(opts, config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(
+			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+				return aws.Endpoint{
+					URL:           endpoint,
+					PartitionID:   "aws",
+					SigningRegion: region,
+				}, nil
+			})))
+	}
+	return config.LoadDefaultConfig(context.Background(), opts...)
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"os"
 	"os/signal"
 	"strings"
@@ -11,15 +13,33 @@ import (
 
 	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
 	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/forwarder"
+	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/health"
 	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/listener"
+	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/notifier"
+	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/resync"
+	"github.com/alexandria-oss/streams/proxy/egress"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/spf13/viper"
 )
 
+// resyncCommand re-forwards every pending batch from the egress storage and exits (proxy-agent resync).
+const resyncCommand = "resync"
+
 func main() {
 	viper.SetEnvPrefix("streams")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	viper.SetDefault("agent.health.enabled", true)
+	viper.SetDefault("agent.drain_timeout", time.Second*30)
+	viper.SetDefault("agent.resync.enabled", true)
+
+	var command string
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	if command != "" && command != resyncCommand {
+		agent.DefaultLogger.Fatal().Str("command", command).Msg("unknown command, stopping agent")
+	}
 
 	db, err := sql.Open("pgx", viper.GetString("postgres.connection_string"))
 	if err != nil {
@@ -31,8 +51,18 @@ func main() {
 		}
 	}()
 
+	storage, cleanStorage, err := forwarder.NewStorage(db)
+	if err != nil {
+		agent.DefaultLogger.Fatal().Err(err).Str("process", "storage").Msg("fatal failure detected, stopping agent")
+	}
+	defer func() {
+		if errClean := cleanStorage(); errClean != nil {
+			agent.DefaultLogger.Err(errClean).Str("process", "storage").Msg("failure detected during cleanup")
+		}
+	}()
+
 	writerDriver := viper.GetString("agent.writer.driver")
-	fwd, cleanFwd, err := forwarder.NewForwarder(writerDriver, db)
+	fwd, cleanFwd, err := forwarder.NewForwarder(writerDriver, storage)
 	if err != nil {
 		agent.DefaultLogger.Fatal().Err(err).Str("process", "forwarder").Msg("fatal failure detected, stopping agent")
 	}
@@ -45,8 +75,55 @@ func main() {
 		Str("writer_driver", writerDriver).
 		Msg("driver detected")
 
+	if command == resyncCommand {
+		runResync(fwd, storage, os.Args[2:])
+		return
+	}
+	runAgent(fwd, storage, db)
+}
+
+// runResync re-forwards every pending batch from storage. Flags override resync.* configuration.
+func runResync(fwd egress.Forwarder, storage egress.Storage, args []string) {
+	flags := flag.NewFlagSet(resyncCommand, flag.ExitOnError)
+	flags.Duration("min_age", 0, "skip batches inserted within this time duration (resync.min_age)")
+	flags.Int("rate_limit", 0, "maximum count of batches forwarded per second, unlimited if 0 (resync.rate_limit)")
+	flags.Int("page_size", 0, "maximum count of batches listed per storage call (resync.page_size)")
+	_ = flags.Parse(args)
+	flags.Visit(func(f *flag.Flag) {
+		viper.Set("resync."+f.Name, f.Value.String())
+	})
+
+	sysChan := make(chan os.Signal, 4)
+	signal.Notify(sysChan, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := fwd.Start(); err != nil {
+			agent.DefaultLogger.Err(err).Str("process", "forwarder").Msg("fatal failure detected, stopping resync")
+			cancel()
+		}
+	}()
+	go func() {
+		select {
+		case <-sysChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := resync.Run(ctx, fwd, storage); err != nil {
+		agent.DefaultLogger.Err(err).Str("process", "resync").Msg("resync failed")
+	}
+	if ctx.Err() != nil {
+		drainForwarder(fwd)
+		return
+	}
+	fwd.Shutdown() // waits for every scheduled batch to be forwarded.
+}
+
+func runAgent(fwd egress.Forwarder, storage egress.Storage, db *sql.DB) {
 	listenDriver := viper.GetString("agent.listener.driver")
-	l, err := listener.NewListener(listenDriver, fwd)
+	l, err := listener.NewListener(listenDriver, fwd, db)
 	if err != nil {
 		agent.DefaultLogger.Fatal().Err(err).Str("process", "log_listener").Msg("fatal failure detected, stopping agent")
 	}
@@ -59,25 +136,76 @@ func main() {
 	signal.Notify(sysChan, os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGKILL)
 
 	go func() {
-		if err = fwd.Start(); err != nil {
+		if err := fwd.Start(); err != nil {
 			agent.DefaultLogger.Err(err).Str("process", "forwarder").Msg("fatal failure detected, stopping agent")
 			sysChan <- os.Interrupt
 		}
 	}()
 	go func() {
-		if err = l.Start(); err != nil {
+		if err := l.Start(); err != nil {
 			agent.DefaultLogger.Err(err).Str("process", "log_listener").Msg("fatal failure detected, stopping agent")
 			sysChan <- os.Interrupt
 		}
 	}()
+	var notifServer *notifier.Server
+	if viper.GetBool("agent.notifier.enabled") {
+		notifServer = notifier.NewServer(fwd)
+		go func() {
+			if err := notifServer.Start(); err != nil {
+				agent.DefaultLogger.Err(err).Str("process", "notification_server").Msg("fatal failure detected, stopping agent")
+				sysChan <- os.Interrupt
+			}
+		}()
+	}
+	var healthServer *health.Server
+	if viper.GetBool("agent.health.enabled") {
+		healthServer = health.NewServer(fwd, l, listenDriver)
+		go func() {
+			if err := healthServer.Start(); err != nil {
+				agent.DefaultLogger.Err(err).Str("process", "health_server").Msg("fatal failure detected, stopping agent")
+				sysChan <- os.Interrupt
+			}
+		}()
+	}
+	// batches missed while the agent was down (e.g. skipped by a drain, lost replication slot) are re-forwarded.
+	resyncCtx, cancelResync := context.WithCancel(context.Background())
+	resyncDone := make(chan struct{})
+	go func() {
+		defer close(resyncDone)
+		if !viper.GetBool("agent.resync.enabled") {
+			return
+		}
+		if err := resync.Run(resyncCtx, fwd, storage); err != nil && !errors.Is(err, context.Canceled) {
+			agent.DefaultLogger.Err(err).Str("process", "resync").Msg("startup resync failed")
+		}
+	}()
 	<-sysChan
 
-	fwd.Shutdown()
+	// stop traffic intake first, so the forwarder drains in-flight jobs only.
+	cancelResync()
+	<-resyncDone
+	if notifServer != nil {
+		shutdownNotificationServer(notifServer)
+	}
 	shutdownListener(l)
+	drainForwarder(fwd)
+	if healthServer != nil {
+		shutdownHealthServer(healthServer)
+	}
 
 	agent.DefaultLogger.Info().Msg("agent shutdown succeeded")
 }
 
+func drainForwarder(fwd egress.Forwarder) {
+	drainCtx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("agent.drain_timeout"))
+	defer cancel()
+	if err := fwd.Drain(drainCtx); err != nil {
+		agent.DefaultLogger.Err(err).
+			Uint64("skipped_jobs", fwd.Stats().SkippedJobs).
+			Msg("forwarder drain deadline exceeded, in-flight jobs were cancelled")
+	}
+}
+
 func shutdownListener(l listener.Listener) {
 	shutdownListenerCtx, listenerCancel := context.WithTimeout(context.Background(), time.Second*15)
 	defer listenerCancel()
@@ -85,3 +213,19 @@ func shutdownListener(l listener.Listener) {
 		agent.DefaultLogger.Err(err).Msg("listener graceful shutdown process failed")
 	}
 }
+
+func shutdownHealthServer(s *health.Server) {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+	if err := s.Close(shutdownCtx); err != nil {
+		agent.DefaultLogger.Err(err).Msg("health server graceful shutdown process failed")
+	}
+}
+
+func shutdownNotificationServer(s *notifier.Server) {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+	if err := s.Close(shutdownCtx); err != nil {
+		agent.DefaultLogger.Err(err).Msg("notification server graceful shutdown process failed")
+	}
+}
@@ -0,0 +1,39 @@
+package forwarder
+
+import (
+	"github.com/alexandria-oss/streams"
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	"github.com/alexandria-oss/streams/driver/amazon"
+	streamsns "github.com/alexandria-oss/streams/driver/amazon/sns"
+	streamsqs "github.com/alexandria-oss/streams/driver/amazon/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/spf13/viper"
+)
+
+func newAmazonConfig() amazon.Config {
+	return amazon.Config{
+		AccountID: viper.GetString("aws.account_id"),
+		Region:    viper.GetString("aws.region"),
+	}
+}
+
+func NewSNS() (streams.Writer, func() error, error) {
+	awsCfg, err := agent.NewAWSConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return streamsns.NewWriter(newAmazonConfig(), sns.NewFromConfig(awsCfg)), noopCleanup, nil
+}
+
+func NewSQS() (streams.Writer, func() error, error) {
+	awsCfg, err := agent.NewAWSConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := streamsqs.WriterConfig{
+		Config:       newAmazonConfig(),
+		DelaySeconds: viper.GetInt32("aws.sqs.delay_seconds"),
+	}
+	return streamsqs.NewWriter(cfg, awsCfg, sqs.NewFromConfig(awsCfg)), noopCleanup, nil
+}
@@ -1,37 +1,65 @@
 package forwarder
 
 import (
-	"database/sql"
 	"errors"
 	stdlog "log"
 
+	"github.com/alexandria-oss/streams"
 	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
 	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/typeutils"
 	"github.com/alexandria-oss/streams/codec"
-	streamsql "github.com/alexandria-oss/streams/driver/sql"
 	"github.com/alexandria-oss/streams/proxy/egress"
 	"github.com/spf13/viper"
 )
 
 const (
 	KafkaDriver = "kafka"
+	SNSDriver   = "sns"
+	SQSDriver   = "sqs"
+	HTTPDriver  = "http"
+	NATSDriver  = "nats"
 )
 
-var ErrUnknownDriver = errors.New("forwarder: unknown driver")
+const (
+	UnorderedOrdering  = "none"
+	InsertTimeOrdering = "insert_time"
+	StreamKeyOrdering  = "stream_key"
+)
+
+var (
+	ErrUnknownDriver   = errors.New("forwarder: unknown driver")
+	ErrUnknownOrdering = errors.New("forwarder: unknown ordering")
+)
 
 func newDefaultConfig() {
 	viper.SetDefault("forwarder.egress_table", egress.DefaultEgressTableName)
+	viper.SetDefault("forwarder.ordering", UnorderedOrdering)
 }
 
-func newConfig(db *sql.DB) egress.ForwarderConfig {
+func newOrdering(ordering string) (egress.ForwardOrdering, error) {
+	switch ordering {
+	case UnorderedOrdering:
+		return egress.UnorderedForwarding, nil
+	case InsertTimeOrdering:
+		return egress.InsertTimeForwarding, nil
+	case StreamKeyOrdering:
+		return egress.StreamKeyForwarding, nil
+	default:
+		return 0, ErrUnknownOrdering
+	}
+}
+
+func newConfig() (egress.ForwarderConfig, error) {
 	defCfg := egress.NewForwarderDefaultConfig()
 	newDefaultConfig()
+	ordering, err := newOrdering(viper.GetString("forwarder.ordering"))
+	if err != nil {
+		return egress.ForwarderConfig{}, err
+	}
 	return egress.ForwarderConfig{
-		Storage: streamsql.NewEgressStorageWithConfig(db, egress.StorageConfig{
-			TableName: viper.GetString("forwarder.egress_table"),
-		}),
-		Writer: nil,
-		Codec:  codec.ProtocolBuffers{},
+		Storage: nil,
+		Writer:  nil,
+		Codec:   codec.ProtocolBuffers{},
 		Logger: stdlog.New(agent.DefaultLogger.With().Str("level", "info").
 			Str("process", "forwarder").Logger(), "", 0),
 		TableName:                 viper.GetString("forwarder.egress_table"),
@@ -39,18 +67,59 @@ func newConfig(db *sql.DB) egress.ForwarderConfig {
 		ForwardJobTotalRetries:    typeutils.Coalesce(viper.GetInt("forwarder.job_total_retries"), defCfg.ForwardJobTotalRetries),
 		ForwardJobRetryBackoff:    typeutils.Coalesce(viper.GetDuration("forwarder.job_retry_backoff"), defCfg.ForwardJobRetryBackoff),
 		ForwardJobRetryBackoffMax: typeutils.Coalesce(viper.GetDuration("forwarder.job_retry_backoff_max"), defCfg.ForwardJobRetryBackoffMax),
-	}
+		MaxConcurrentJobs:         viper.GetInt("forwarder.max_concurrent_jobs"),
+		MaxQueuedJobs:             typeutils.Coalesce(viper.GetInt("forwarder.max_queued_jobs"), defCfg.MaxQueuedJobs),
+		Ordering:                  ordering,
+		MaxCoalescedBatches:       typeutils.Coalesce(viper.GetInt("forwarder.max_coalesced_batches"), defCfg.MaxCoalescedBatches),
+		CoalesceWindow:            viper.GetDuration("forwarder.coalesce_window"),
+	}, nil
 }
 
-func NewForwarder(driver string, db *sql.DB) (egress.Forwarder, func() error, error) {
-	fwdCfg := newConfig(db)
-	var cleanup func() error
+func noopCleanup() error {
+	return nil
+}
 
+func newWriter(driver string) (streams.Writer, func() error, error) {
 	switch driver {
 	case KafkaDriver:
-		fwdCfg.Writer, cleanup = NewKafka()
+		w, cleanup := NewKafka()
+		return w, cleanup, nil
+	case SNSDriver:
+		return NewSNS()
+	case SQSDriver:
+		return NewSQS()
+	case HTTPDriver:
+		return NewHTTP()
+	case NATSDriver:
+		return NewNATS()
 	default:
-		return egress.Forwarder{}, nil, ErrUnknownDriver
+		return nil, nil, ErrUnknownDriver
+	}
+}
+
+// NewForwarder allocates an egress.Forwarder writing to the specified driver.
+//
+// If routing rules are set (forwarder.routes), messages will be routed to different drivers depending on their
+// stream name or headers. Routing rules have the format <rule>=<driver> where rule is either a stream glob pattern
+// (e.g. org.alexandria.payments.*=sns), a stream regular expression (e.g. regex:^org\.alexandria\..+=sqs) or a header
+// (e.g. header:tenant:acme=http) whereas driver is used as fallback for unmatched messages.
+//
+// Message batches are fetched from storage (see NewStorage).
+func NewForwarder(driver string, storage egress.Storage) (egress.Forwarder, func() error, error) {
+	fwdCfg, err := newConfig()
+	if err != nil {
+		return egress.Forwarder{}, nil, err
+	}
+	fwdCfg.Storage = storage
+
+	var cleanupWriter func() error
+	if routes := viper.GetStringSlice("forwarder.routes"); len(routes) > 0 {
+		fwdCfg.Writer, cleanupWriter, err = newRoutingWriter(routes, driver)
+	} else {
+		fwdCfg.Writer, cleanupWriter, err = newWriter(driver)
+	}
+	if err != nil {
+		return egress.Forwarder{}, nil, err
 	}
-	return egress.NewForwarder(fwdCfg), cleanup, nil
+	return egress.NewForwarder(fwdCfg), cleanupWriter, nil
 }
@@ -0,0 +1,130 @@
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/typeutils"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spf13/viper"
+)
+
+// HeaderStreamName is the HTTP header used by the HTTP webhook writer to specify the stream of a message batch.
+const HeaderStreamName = "X-Streams-Stream-Name"
+
+type httpConfig struct {
+	Endpoint     string
+	Headers      map[string]string
+	Timeout      time.Duration
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+func newHTTPConfig() httpConfig {
+	viper.SetDefault("http.max_retries", 3)
+	return httpConfig{
+		Endpoint:     viper.GetString("http.endpoint"),
+		Headers:      viper.GetStringMapString("http.headers"),
+		Timeout:      typeutils.Coalesce(viper.GetDuration("http.timeout"), time.Second*30),
+		MaxRetries:   viper.GetInt("http.max_retries"),
+		RetryBackoff: typeutils.Coalesce(viper.GetDuration("http.retry_backoff"), time.Millisecond*100),
+	}
+}
+
+// httpWriter is a generic HTTP webhook streams.Writer. Message batches are grouped by stream and
+// sent as JSON arrays to the configured endpoint through POST requests.
+//
+// Requests failing with a network error, 429 or 5xx status codes are retried. Any other non-2xx status code is
+// considered unrecoverable (see streams.ErrUnrecoverable).
+type httpWriter struct {
+	client *http.Client
+	cfg    httpConfig
+}
+
+var _ streams.Writer = httpWriter{}
+
+func NewHTTP() (streams.Writer, func() error, error) {
+	w := newHTTPWriter(newHTTPConfig())
+	return w, func() error {
+		w.client.CloseIdleConnections()
+		return nil
+	}, nil
+}
+
+func newHTTPWriter(cfg httpConfig) httpWriter {
+	return httpWriter{
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		cfg: cfg,
+	}
+}
+
+func (w httpWriter) Write(ctx context.Context, msgBatch []streams.Message) error {
+	batchBuf := make(map[string][]streams.Message)
+	for _, msg := range msgBatch {
+		batchBuf[msg.StreamName] = append(batchBuf[msg.StreamName], msg)
+	}
+
+	for stream, msgs := range batchBuf {
+		if err := w.write(ctx, stream, msgs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w httpWriter) write(ctx context.Context, stream string, msgBatch []streams.Message) error {
+	body, err := jsoniter.Marshal(msgBatch)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		var isRetryable bool
+		isRetryable, err = w.send(ctx, stream, body)
+		if err == nil || !isRetryable || attempt >= w.cfg.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.cfg.RetryBackoff * time.Duration(attempt+1)):
+		}
+	}
+}
+
+// send posts body to the webhook, indicating if the request might be retried on failure.
+func (w httpWriter) send(ctx context.Context, stream string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderStreamName, stream)
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body) // allows connection reuse
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		return false, nil
+	}
+
+	err = fmt.Errorf("forwarder: webhook <%s> responded with status code <%d>", w.cfg.Endpoint, res.StatusCode)
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+		return true, err
+	}
+	return false, streams.ErrUnrecoverableWrap{ParentErr: err}
+}
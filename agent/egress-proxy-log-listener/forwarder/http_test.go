@@ -0,0 +1,129 @@
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPWriter(t *testing.T) {
+	tests := []struct {
+		name          string
+		inStatusCodes []int // responded in order, last status code is repeated
+		wantRequests  int32
+		wantErr       bool
+		wantUnrecover bool
+	}{
+		{
+			name:          "ok",
+			inStatusCodes: []int{http.StatusAccepted},
+			wantRequests:  1,
+		},
+		{
+			name:          "retried server error",
+			inStatusCodes: []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			wantRequests:  3,
+		},
+		{
+			name:          "retries exhausted",
+			inStatusCodes: []int{http.StatusServiceUnavailable},
+			wantRequests:  3,
+			wantErr:       true,
+		},
+		{
+			name:          "client error",
+			inStatusCodes: []int{http.StatusBadRequest},
+			wantRequests:  1,
+			wantErr:       true,
+			wantUnrecover: true,
+		},
+		{
+			name:          "redirect",
+			inStatusCodes: []int{http.StatusNotModified},
+			wantRequests:  1,
+			wantErr:       true,
+			wantUnrecover: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var totalReqs atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				statusIdx := int(totalReqs.Add(1)) - 1
+				if statusIdx >= len(tt.inStatusCodes) {
+					statusIdx = len(tt.inStatusCodes) - 1
+				}
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "foo", r.Header.Get(HeaderStreamName))
+				assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				var msgs []streams.Message
+				assert.NoError(t, jsoniter.Unmarshal(body, &msgs))
+				assert.Len(t, msgs, 2)
+				w.WriteHeader(tt.inStatusCodes[statusIdx])
+			}))
+			defer srv.Close()
+
+			writer := newHTTPWriter(httpConfig{
+				Endpoint:     srv.URL,
+				Headers:      map[string]string{"Authorization": "Bearer abc"},
+				Timeout:      time.Second,
+				MaxRetries:   2,
+				RetryBackoff: time.Millisecond,
+			})
+			err := writer.Write(context.TODO(), []streams.Message{
+				{ID: "1", StreamName: "foo", Data: []byte("bar")},
+				{ID: "2", StreamName: "foo", Data: []byte("baz")},
+			})
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantUnrecover, errors.Is(err, streams.ErrUnrecoverable))
+			assert.Equal(t, tt.wantRequests, totalReqs.Load())
+		})
+	}
+}
+
+func TestHTTPWriter_GroupByStream(t *testing.T) {
+	streamReqs := make(chan string, 3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		streamReqs <- r.Header.Get(HeaderStreamName)
+	}))
+	defer srv.Close()
+
+	writer := newHTTPWriter(httpConfig{Endpoint: srv.URL, Timeout: time.Second})
+	require.NoError(t, writer.Write(context.TODO(), []streams.Message{
+		{ID: "1", StreamName: "foo"},
+		{ID: "2", StreamName: "bar"},
+		{ID: "3", StreamName: "foo"},
+	}))
+	close(streamReqs)
+	streamNames := make([]string, 0, 2)
+	for stream := range streamReqs {
+		streamNames = append(streamNames, stream)
+	}
+	assert.ElementsMatch(t, []string{"foo", "bar"}, streamNames)
+}
+
+func TestHTTPWriter_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	writer := newHTTPWriter(httpConfig{Endpoint: srv.URL, Timeout: time.Second, MaxRetries: 1,
+		RetryBackoff: time.Millisecond})
+	err := writer.Write(context.TODO(), []streams.Message{{ID: "1", StreamName: "foo"}})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, streams.ErrUnrecoverable)
+}
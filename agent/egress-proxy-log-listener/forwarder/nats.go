@@ -0,0 +1,33 @@
+package forwarder
+
+import (
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/typeutils"
+	streamsnats "github.com/alexandria-oss/streams/driver/nats"
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/viper"
+)
+
+// NewNATS allocates a NATS JetStream streams.Writer. Subjects named after message streams must be bound to a
+// JetStream stream.
+func NewNATS() (streams.Writer, func() error, error) {
+	conn, err := nats.Connect(typeutils.Coalesce(viper.GetString("nats.url"), nats.DefaultURL),
+		nats.Name(typeutils.Coalesce(viper.GetString("nats.client_name"), "streams-egress-proxy-agent")),
+		nats.MaxReconnects(typeutils.Coalesce(viper.GetInt("nats.max_reconnects"), nats.DefaultMaxReconnect)),
+		nats.ReconnectWait(typeutils.Coalesce(viper.GetDuration("nats.reconnect_wait"), nats.DefaultReconnectWait)),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jsOpts := make([]nats.JSOpt, 0, 1)
+	if maxPending := viper.GetInt("nats.publish_async_max_pending"); maxPending > 0 {
+		jsOpts = append(jsOpts, nats.PublishAsyncMaxPending(maxPending))
+	}
+	js, err := conn.JetStream(jsOpts...)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return streamsnats.NewWriter(js), conn.Drain, nil
+}
@@ -0,0 +1,54 @@
+package forwarder
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveFakeNATS accepts a single NATS client connection, answering the minimal protocol handshake.
+func serveFakeNATS(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+	go func() {
+		conn, errAccept := l.Accept()
+		if errAccept != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("INFO {\"server_id\":\"fake\",\"headers\":true,\"max_payload\":1048576}\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, errRead := reader.ReadString('\n')
+			if errRead != nil {
+				return
+			}
+			if strings.HasPrefix(line, "PING") {
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+	return "nats://" + l.Addr().String()
+}
+
+func TestNewNATS(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("nats.url", "nats://127.0.0.1:1")
+	_, _, err := newWriter(NATSDriver)
+	assert.Error(t, err)
+
+	viper.Set("nats.url", serveFakeNATS(t))
+	writer, cleanup, err := newWriter(NATSDriver)
+	require.NoError(t, err)
+	assert.NotNil(t, writer)
+	assert.NoError(t, cleanup())
+}
@@ -0,0 +1,103 @@
+package forwarder
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	regexRoutePrefix  = "regex:"
+	headerRoutePrefix = "header:"
+)
+
+var ErrInvalidRoute = errors.New("forwarder: invalid route, expected format <rule>=<driver>")
+
+// parseRoute parses a route with format <rule>=<driver>. Rules are either a stream glob pattern
+// (e.g. org.alexandria.payments.*), a stream regular expression (regex:<expression>) or a message header
+// (header:<key>:<value>).
+func parseRoute(rawRoute string) (streams.RouteMatcher, string, error) {
+	sep := strings.LastIndex(rawRoute, "=")
+	if sep <= 0 || sep == len(rawRoute)-1 {
+		return nil, "", fmt.Errorf("%w, got <%s>", ErrInvalidRoute, rawRoute)
+	}
+	rule, driver := rawRoute[:sep], rawRoute[sep+1:]
+
+	switch {
+	case strings.HasPrefix(rule, regexRoutePrefix):
+		expr, err := regexp.Compile(strings.TrimPrefix(rule, regexRoutePrefix))
+		if err != nil {
+			return nil, "", err
+		}
+		return streams.MatchStreamRegex(expr), driver, nil
+	case strings.HasPrefix(rule, headerRoutePrefix):
+		key, value, ok := strings.Cut(strings.TrimPrefix(rule, headerRoutePrefix), ":")
+		if !ok || key == "" {
+			return nil, "", fmt.Errorf("%w, got <%s>", ErrInvalidRoute, rawRoute)
+		}
+		return streams.MatchHeader(key, value), driver, nil
+	}
+
+	if _, err := path.Match(rule, ""); err != nil {
+		return nil, "", err
+	}
+	return streams.MatchStreamGlob(rule), driver, nil
+}
+
+// newRoutingWriter allocates a streams.RoutingWriter from a set of routes with format <rule>=<driver> (see parseRoute).
+// Routes are evaluated in order.
+// Writers are allocated once per driver; fallbackDriver is used for unmatched streams.
+func newRoutingWriter(rawRoutes []string, fallbackDriver string) (streams.Writer, func() error, error) {
+	cleanups := make([]func() error, 0, len(rawRoutes)+1)
+	cleanup := func() error {
+		errs := &multierror.Error{}
+		for _, c := range cleanups {
+			if err := c(); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		return errs.ErrorOrNil()
+	}
+
+	driverWriters := make(map[string]streams.Writer, len(rawRoutes)+1)
+	getWriter := func(driver string) (streams.Writer, error) {
+		if w, ok := driverWriters[driver]; ok {
+			return w, nil
+		}
+		w, c, err := newWriter(driver)
+		if err != nil {
+			return nil, err
+		}
+		driverWriters[driver] = w
+		cleanups = append(cleanups, c)
+		return w, nil
+	}
+
+	fallback, err := getWriter(fallbackDriver)
+	if err != nil {
+		return nil, nil, err
+	}
+	routes := make([]streams.Route, 0, len(rawRoutes))
+	for _, rawRoute := range rawRoutes {
+		match, driver, errRoute := parseRoute(rawRoute)
+		if errRoute != nil {
+			_ = cleanup()
+			return nil, nil, errRoute
+		}
+		writer, errWriter := getWriter(driver)
+		if errWriter != nil {
+			_ = cleanup()
+			return nil, nil, errWriter
+		}
+		routes = append(routes, streams.Route{
+			Match:  match,
+			Writer: writer,
+		})
+	}
+	return streams.NewRoutingWriter(fallback, routes...), cleanup, nil
+}
@@ -0,0 +1,157 @@
+package forwarder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp/syntax"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		name       string
+		inRoute    string
+		inMsg      streams.Message
+		wantDriver string
+		wantMatch  bool
+		wantErr    error
+	}{
+		{
+			name:       "glob",
+			inRoute:    "org.alexandria.payments.*=sns",
+			inMsg:      streams.Message{StreamName: "org.alexandria.payments.created"},
+			wantDriver: SNSDriver,
+			wantMatch:  true,
+		},
+		{
+			name:       "glob mismatch",
+			inRoute:    "org.alexandria.payments.*=sns",
+			inMsg:      streams.Message{StreamName: "org.alexandria.orders.created"},
+			wantDriver: SNSDriver,
+		},
+		{
+			name:       "regex",
+			inRoute:    `regex:^org\.alexandria\.(payments|orders)\..+=sqs`,
+			inMsg:      streams.Message{StreamName: "org.alexandria.orders.created"},
+			wantDriver: SQSDriver,
+			wantMatch:  true,
+		},
+		{
+			name:       "regex with separator",
+			inRoute:    `regex:^a={2}$=http`,
+			inMsg:      streams.Message{StreamName: "a=="},
+			wantDriver: HTTPDriver,
+			wantMatch:  true,
+		},
+		{
+			name:       "regex mismatch",
+			inRoute:    `regex:^org\.alexandria\.payments\..+=sqs`,
+			inMsg:      streams.Message{StreamName: "org.alexandria.orders.created"},
+			wantDriver: SQSDriver,
+		},
+		{
+			name:       "header",
+			inRoute:    "header:tenant:acme=http",
+			inMsg:      streams.Message{StreamName: "foo", Headers: map[string]string{"tenant": "acme"}},
+			wantDriver: HTTPDriver,
+			wantMatch:  true,
+		},
+		{
+			name:       "header mismatch",
+			inRoute:    "header:tenant:acme=http",
+			inMsg:      streams.Message{StreamName: "foo", Headers: map[string]string{"tenant": "globex"}},
+			wantDriver: HTTPDriver,
+		},
+		{
+			name:    "missing separator",
+			inRoute: "org.alexandria.payments.*",
+			wantErr: ErrInvalidRoute,
+		},
+		{
+			name:    "missing rule",
+			inRoute: "=sns",
+			wantErr: ErrInvalidRoute,
+		},
+		{
+			name:    "missing driver",
+			inRoute: "org.alexandria.payments.*=",
+			wantErr: ErrInvalidRoute,
+		},
+		{
+			name:    "malformed glob",
+			inRoute: "org.alexandria.[payments=sns",
+			wantErr: path.ErrBadPattern,
+		},
+		{
+			name:    "malformed regex",
+			inRoute: "regex:(org=sns",
+			wantErr: &syntax.Error{Code: syntax.ErrMissingParen, Expr: "(org"},
+		},
+		{
+			name:    "malformed header",
+			inRoute: "header:tenant=http",
+			wantErr: ErrInvalidRoute,
+		},
+		{
+			name:    "missing header key",
+			inRoute: "header::acme=http",
+			wantErr: ErrInvalidRoute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, driver, err := parseRoute(tt.inRoute)
+			if tt.wantErr != nil {
+				if _, ok := tt.wantErr.(*syntax.Error); ok {
+					assert.Equal(t, tt.wantErr, err)
+				} else {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				assert.Nil(t, match)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDriver, driver)
+			assert.Equal(t, tt.wantMatch, match(tt.inMsg))
+		})
+	}
+}
+
+func TestNewRoutingWriter(t *testing.T) {
+	var totalReqs atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		totalReqs.Add(1)
+	}))
+	defer srv.Close()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("http.endpoint", srv.URL)
+
+	_, _, err := newRoutingWriter([]string{"foo.*=http"}, "unknown")
+	assert.ErrorIs(t, err, ErrUnknownDriver)
+	_, _, err = newRoutingWriter([]string{"foo.*=unknown"}, HTTPDriver)
+	assert.ErrorIs(t, err, ErrUnknownDriver)
+	_, _, err = newRoutingWriter([]string{"foo.*"}, HTTPDriver)
+	assert.ErrorIs(t, err, ErrInvalidRoute)
+
+	// unmatched messages are written by the fallback writer
+	writer, cleanup, err := newRoutingWriter([]string{"header:tenant:acme=http"}, HTTPDriver)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cleanup())
+	}()
+	require.NoError(t, writer.Write(context.TODO(), []streams.Message{
+		{ID: "1", StreamName: "foo", Headers: map[string]string{"tenant": "acme"}},
+		{ID: "2", StreamName: "bar"},
+	}))
+	assert.Equal(t, int32(2), totalReqs.Load())
+}
@@ -0,0 +1,64 @@
+package forwarder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	streamsdynamo "github.com/alexandria-oss/streams/driver/dynamodb"
+	streamsmongo "github.com/alexandria-oss/streams/driver/mongodb"
+	streamsql "github.com/alexandria-oss/streams/driver/sql"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	PostgresStorage = "postgres"
+	DynamoDBStorage = "dynamodb"
+	MongoDBStorage  = "mongodb"
+)
+
+var ErrUnknownStorage = errors.New("forwarder: unknown storage")
+
+func newStorageConfig() egress.StorageConfig {
+	// storages are allocated before forwarders, thus defaults are registered here too
+	newDefaultConfig()
+	viper.SetDefault("forwarder.storage", PostgresStorage)
+	return egress.StorageConfig{
+		TableName:       viper.GetString("forwarder.egress_table"),
+		MaxAttempts:     viper.GetInt("forwarder.max_attempts"),
+		RetainPublished: viper.GetBool("forwarder.retain_published"),
+	}
+}
+
+// NewStorage allocates the egress.Storage (forwarder.storage) a forwarder fetches message batches from. db is used by
+// the postgres storage.
+func NewStorage(db *sql.DB) (egress.Storage, func() error, error) {
+	cfg := newStorageConfig()
+	switch viper.GetString("forwarder.storage") {
+	case PostgresStorage:
+		return streamsql.NewEgressStorageWithConfig(db, cfg), noopCleanup, nil
+	case DynamoDBStorage:
+		awsCfg, err := agent.NewAWSConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		return streamsdynamo.NewEgressStorageWithConfig(dynamodb.NewFromConfig(awsCfg), cfg), noopCleanup, nil
+	case MongoDBStorage:
+		client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(viper.GetString("mongodb.uri")))
+		if err != nil {
+			return nil, nil, err
+		}
+		cleanup := func() error {
+			return client.Disconnect(context.Background())
+		}
+		return streamsmongo.NewEgressStorageWithConfig(client.Database(viper.GetString("mongodb.database")), cfg),
+			cleanup, nil
+	default:
+		return nil, nil, ErrUnknownStorage
+	}
+}
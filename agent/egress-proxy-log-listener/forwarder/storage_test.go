@@ -0,0 +1,20 @@
+package forwarder
+
+import (
+	"testing"
+
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStorageConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	cfg := newStorageConfig()
+	assert.Equal(t, egress.DefaultEgressTableName, cfg.TableName)
+	assert.Equal(t, PostgresStorage, viper.GetString("forwarder.storage"))
+
+	viper.Set("forwarder.egress_table", "outbox")
+	assert.Equal(t, "outbox", newStorageConfig().TableName)
+}
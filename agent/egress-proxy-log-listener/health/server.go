@@ -0,0 +1,203 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/listener"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/spf13/viper"
+)
+
+var defaultLoggerServer = agent.DefaultLogger.With().
+	Str("process", "health_server").
+	Logger()
+
+type serverConfig struct {
+	Address           string
+	ReadHeaderTimeout time.Duration
+}
+
+func newServerConfig() serverConfig {
+	viper.SetDefault("health.address", ":8081")
+	viper.SetDefault("health.read_header_timeout", time.Second*5)
+	return serverConfig{
+		Address:           viper.GetString("health.address"),
+		ReadHeaderTimeout: viper.GetDuration("health.read_header_timeout"),
+	}
+}
+
+// A Server exposes the agent liveness (/healthz), readiness (/readyz) and metrics (/metrics) HTTP endpoints.
+//
+// The agent is ready if both forwarder and listener are running and the forwarder is not draining. Metrics are
+// written using the Prometheus text exposition format.
+type Server struct {
+	cfg            serverConfig
+	fwd            egress.Forwarder
+	listener       listener.Listener
+	listenerDriver string
+	srv            *http.Server
+}
+
+// NewServer allocates a Server instance.
+func NewServer(fwd egress.Forwarder, l listener.Listener, listenerDriver string) *Server {
+	s := &Server{
+		cfg:            newServerConfig(),
+		fwd:            fwd,
+		listener:       l,
+		listenerDriver: listenerDriver,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleLiveness)
+	mux.HandleFunc("/readyz", s.handleReadiness)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	s.srv = &http.Server{
+		Addr:              s.cfg.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: s.cfg.ReadHeaderTimeout,
+	}
+	return s
+}
+
+// Start listens to the configured address, blocking the I/O until Server.Close is called.
+func (s *Server) Start() error {
+	defaultLoggerServer.Info().
+		Str("address", s.cfg.Address).
+		Msg("serving health endpoints")
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close gracefully shuts down the server.
+func (s *Server) Close(ctx context.Context) error {
+	defer defaultLoggerServer.Info().Msg("server successfully shut down")
+	return s.srv.Shutdown(ctx)
+}
+
+type readinessResponse struct {
+	Status    string         `json:"status"`
+	Forwarder forwarderStats `json:"forwarder"`
+	Listener  listenerStats  `json:"listener"`
+}
+
+type forwarderStats struct {
+	IsRunning        bool      `json:"is_running"`
+	IsDraining       bool      `json:"is_draining"`
+	QueuedJobs       int64     `json:"queued_jobs"`
+	InFlightJobs     int64     `json:"in_flight_jobs"`
+	ForwardedBatches uint64    `json:"forwarded_batches"`
+	LastBatchID      string    `json:"last_batch_id,omitempty"`
+	LastForwardTime  time.Time `json:"last_forward_time,omitempty"`
+}
+
+type listenerStats struct {
+	Driver     string `json:"driver"`
+	IsRunning  bool   `json:"is_running"`
+	TotalReads uint64 `json:"total_reads"`
+	Position   string `json:"position,omitempty"`
+}
+
+func (s *Server) handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (s *Server) handleReadiness(w http.ResponseWriter, _ *http.Request) {
+	fwdStats := s.fwd.Stats()
+	lStats := s.listener.Stats()
+	res := readinessResponse{
+		Status: "ready",
+		Forwarder: forwarderStats{
+			IsRunning:        fwdStats.IsRunning,
+			IsDraining:       fwdStats.IsDraining,
+			QueuedJobs:       fwdStats.QueuedJobs,
+			InFlightJobs:     fwdStats.InFlightJobs,
+			ForwardedBatches: fwdStats.ForwardedBatches,
+			LastBatchID:      fwdStats.LastBatchID,
+			LastForwardTime:  fwdStats.LastForwardTime,
+		},
+		Listener: listenerStats{
+			Driver:     s.listenerDriver,
+			IsRunning:  lStats.IsRunning,
+			TotalReads: lStats.TotalReads,
+			Position:   lStats.Position,
+		},
+	}
+	status := http.StatusOK
+	if !fwdStats.IsRunning || fwdStats.IsDraining || !lStats.IsRunning {
+		res.Status = "not_ready"
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		defaultLoggerServer.Err(err).Msg("failed to write readiness response")
+	}
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	fwdStats := s.fwd.Stats()
+	lStats := s.listener.Stats()
+	lastForward := 0.0
+	if !fwdStats.LastForwardTime.IsZero() {
+		lastForward = float64(fwdStats.LastForwardTime.UnixNano()) / float64(time.Second)
+	}
+
+	buf := &strings.Builder{}
+	writeMetric(buf, "streams_agent_forwarder_up", "gauge",
+		"Indicates if the forwarder is accepting forward jobs.", "", boolToFloat(fwdStats.IsRunning))
+	writeMetric(buf, "streams_agent_forwarder_draining", "gauge",
+		"Indicates if the forwarder is draining.", "", boolToFloat(fwdStats.IsDraining))
+	writeMetric(buf, "streams_agent_forwarder_queued_jobs", "gauge",
+		"Forward jobs waiting for a job slot or job lane.", "", float64(fwdStats.QueuedJobs))
+	writeMetric(buf, "streams_agent_forwarder_in_flight_jobs", "gauge",
+		"Forward jobs running.", "", float64(fwdStats.InFlightJobs))
+	writeMetric(buf, "streams_agent_forwarder_forwarded_batches_total", "counter",
+		"Total count of forwarded batches.", "", float64(fwdStats.ForwardedBatches))
+	writeMetric(buf, "streams_agent_forwarder_failed_jobs_total", "counter",
+		"Total count of failed forward jobs.", "", float64(fwdStats.FailedJobs))
+	writeMetric(buf, "streams_agent_forwarder_skipped_jobs_total", "counter",
+		"Total count of forward jobs skipped during a drain.", "", float64(fwdStats.SkippedJobs))
+	writeMetric(buf, "streams_agent_forwarder_last_forward_timestamp_seconds", "gauge",
+		"Timestamp of the latest forwarded batch.", "", lastForward)
+
+	driverLabel := fmt.Sprintf("driver=%q", s.listenerDriver)
+	writeMetric(buf, "streams_agent_listener_up", "gauge",
+		"Indicates if the listener is connected to the log.", driverLabel, boolToFloat(lStats.IsRunning))
+	writeMetric(buf, "streams_agent_listener_reads_total", "counter",
+		"Total count of batches read from the log.", driverLabel, float64(lStats.TotalReads))
+	if lStats.Offset != 0 {
+		// non-numeric positions (e.g. batch identifiers) are only exposed by /readyz to keep label cardinality bounded
+		writeMetric(buf, "streams_agent_listener_position", "gauge",
+			"Numeric position of the latest batch forwarded from the log (e.g. WAL LSN, stream sequence number).",
+			driverLabel, lStats.Offset)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write([]byte(buf.String()))
+}
+
+func writeMetric(buf *strings.Builder, name, metricType, help, labels string, value float64) {
+	_, _ = fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	if labels != "" {
+		_, _ = fmt.Fprintf(buf, "%s{%s} %g\n", name, labels, value)
+		return
+	}
+	_, _ = fmt.Fprintf(buf, "%s %g\n", name, value)
+}
+
+func boolToFloat(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener/listener"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/assert"
+)
+
+type listenerStub struct {
+	stats listener.Stats
+}
+
+func (l listenerStub) Start() error { return nil }
+
+func (l listenerStub) Close(_ context.Context) error { return nil }
+
+func (l listenerStub) Stats() listener.Stats { return l.stats }
+
+func TestServer_Metrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		inStats    listener.Stats
+		wantMetric string
+	}{
+		{
+			name: "numeric position",
+			inStats: listener.Stats{
+				IsRunning: true,
+				Position:  "0/16B3748",
+				Offset:    23803720,
+			},
+			wantMetric: "streams_agent_listener_position{driver=\"postgres_wal\"} 2.380372e+07\n",
+		},
+		{
+			name: "non-numeric position",
+			inStats: listener.Stats{
+				IsRunning: true,
+				Position:  "7bd5a7b4-5c1e-4c1e-9a4b-6f1c7b1e2f3a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fwd := egress.NewForwarder(egress.NewForwarderDefaultConfig())
+			srv := NewServer(fwd, listenerStub{stats: tt.inStats}, "postgres_wal")
+			rec := httptest.NewRecorder()
+			srv.handleMetrics(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+			body := rec.Body.String()
+			assert.NotContains(t, body, "position=")
+			assert.NotContains(t, body, tt.inStats.Position)
+			if tt.wantMetric == "" {
+				assert.NotContains(t, body, "streams_agent_listener_position")
+				return
+			}
+			assert.Contains(t, body, tt.wantMetric)
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package listener
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/spf13/viper"
+)
+
+const DynamoDBStreamsDriver = "dynamodb_streams"
+
+var defaultLoggerDynamoDB = agent.DefaultLogger.With().
+	Str("listener_driver", DynamoDBStreamsDriver).
+	Logger()
+
+type dynamoDBStreamsConfig struct {
+	EgressTable          string
+	StreamARN            string
+	IteratorType         types.ShardIteratorType
+	PollingInterval      time.Duration
+	ShardRefreshInterval time.Duration
+	RetryBackoff         time.Duration
+}
+
+func newDynamoDBStreamsConfig() dynamoDBStreamsConfig {
+	viper.SetDefault("dynamodb.egress_table", egress.DefaultEgressTableName)
+	viper.SetDefault("dynamodb.streams.iterator_type", string(types.ShardIteratorTypeLatest))
+	viper.SetDefault("dynamodb.streams.polling_interval", time.Second)
+	viper.SetDefault("dynamodb.streams.shard_refresh_interval", time.Second*30)
+	viper.SetDefault("dynamodb.streams.retry_backoff", time.Second*5)
+	return dynamoDBStreamsConfig{
+		EgressTable:          viper.GetString("dynamodb.egress_table"),
+		StreamARN:            viper.GetString("dynamodb.streams.stream_arn"),
+		IteratorType:         types.ShardIteratorType(viper.GetString("dynamodb.streams.iterator_type")),
+		PollingInterval:      viper.GetDuration("dynamodb.streams.polling_interval"),
+		ShardRefreshInterval: viper.GetDuration("dynamodb.streams.shard_refresh_interval"),
+		RetryBackoff:         viper.GetDuration("dynamodb.streams.retry_backoff"),
+	}
+}
+
+// A DynamoDBStreams listener reads the Amazon DynamoDB Stream (change-data-capture) of the egress table. Each
+// shard is read by a separate worker; new shards (e.g. after a stream resharding) are discovered periodically.
+//
+// Inserted batches are forwarded using their key, thus KEYS_ONLY stream view type is enough.
+type DynamoDBStreams struct {
+	cfg               dynamoDBStreamsConfig
+	fwd               egress.Forwarder
+	client            *dynamodbstreams.Client
+	baseCtx           context.Context
+	baseCtxCancel     context.CancelFunc
+	inFlightProcesses sync.WaitGroup
+	shards            map[string]struct{}
+	totalReads        atomic.Uint64
+	isRunning         atomic.Bool
+	lastSequence      position
+}
+
+var _ Listener = &DynamoDBStreams{}
+
+func NewDynamoDBStreams(fwd egress.Forwarder) *DynamoDBStreams {
+	return &DynamoDBStreams{
+		cfg:    newDynamoDBStreamsConfig(),
+		fwd:    fwd,
+		shards: map[string]struct{}{},
+	}
+}
+
+func (d *DynamoDBStreams) Start() error {
+	d.baseCtx, d.baseCtxCancel = context.WithCancel(context.Background())
+	d.inFlightProcesses.Add(1) // shard discovery loop
+	defer d.inFlightProcesses.Done()
+	awsCfg, err := agent.NewAWSConfig()
+	if err != nil {
+		return err
+	}
+	d.client = dynamodbstreams.NewFromConfig(awsCfg)
+	if d.cfg.StreamARN == "" {
+		if d.cfg.StreamARN, err = d.fetchStreamARN(dynamodb.NewFromConfig(awsCfg)); err != nil {
+			return err
+		}
+	}
+	defaultLoggerDynamoDB.Info().
+		Str("stream_arn", d.cfg.StreamARN).
+		Msg("listening to table stream")
+
+	// shards found at startup use the configured iterator type whereas shards discovered later are read from
+	// their beginning, so no records are lost.
+	iteratorType := d.cfg.IteratorType
+	ticker := time.NewTicker(d.cfg.ShardRefreshInterval)
+	defer ticker.Stop()
+	defer d.isRunning.Store(false)
+	for {
+		if errRefresh := d.refreshShards(iteratorType); errRefresh != nil {
+			defaultLoggerDynamoDB.Err(errRefresh).Msg("failed to refresh stream shards")
+		} else {
+			iteratorType = types.ShardIteratorTypeTrimHorizon
+			d.isRunning.Store(true)
+		}
+
+		select {
+		case <-d.baseCtx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *DynamoDBStreams) fetchStreamARN(client *dynamodb.Client) (string, error) {
+	out, err := client.DescribeTable(d.baseCtx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(d.cfg.EgressTable),
+	})
+	if err != nil {
+		return "", err
+	} else if out.Table.LatestStreamArn == nil {
+		return "", ErrDynamoDBStreamDisabled
+	}
+	return *out.Table.LatestStreamArn, nil
+}
+
+func (d *DynamoDBStreams) refreshShards(iteratorType types.ShardIteratorType) error {
+	var lastShardID *string
+	for {
+		out, err := d.client.DescribeStream(d.baseCtx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             aws.String(d.cfg.StreamARN),
+			ExclusiveStartShardId: lastShardID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, shard := range out.StreamDescription.Shards {
+			shardID := aws.ToString(shard.ShardId)
+			if _, ok := d.shards[shardID]; ok {
+				continue
+			}
+			d.shards[shardID] = struct{}{}
+			d.inFlightProcesses.Add(1)
+			go d.readShard(shardID, iteratorType)
+		}
+
+		lastShardID = out.StreamDescription.LastEvaluatedShardId
+		if lastShardID == nil {
+			return nil
+		}
+	}
+}
+
+func (d *DynamoDBStreams) getShardIterator(shardID string, iteratorType types.ShardIteratorType,
+	lastSequenceNumber *string) (*string, error) {
+	in := &dynamodbstreams.GetShardIteratorInput{
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: iteratorType,
+		StreamArn:         aws.String(d.cfg.StreamARN),
+	}
+	if lastSequenceNumber != nil {
+		in.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		in.SequenceNumber = lastSequenceNumber
+	}
+	out, err := d.client.GetShardIterator(d.baseCtx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out.ShardIterator, nil
+}
+
+func (d *DynamoDBStreams) readShard(shardID string, iteratorType types.ShardIteratorType) {
+	defer d.inFlightProcesses.Done()
+	logger := defaultLoggerDynamoDB.With().Str("shard_id", shardID).Logger()
+	logger.Info().Msg("starting shard worker")
+
+	var (
+		iterator           *string
+		lastSequenceNumber *string
+		err                error
+	)
+	for {
+		if iterator == nil {
+			if iterator, err = d.getShardIterator(shardID, iteratorType, lastSequenceNumber); err != nil {
+				if d.baseCtx.Err() != nil {
+					return
+				}
+				logger.Err(err).Msg("failed to get shard iterator")
+				if !d.wait(d.cfg.RetryBackoff) {
+					return
+				}
+				continue
+			}
+		}
+
+		out, errRecords := d.client.GetRecords(d.baseCtx, &dynamodbstreams.GetRecordsInput{
+			ShardIterator: iterator,
+		})
+		if errRecords != nil {
+			if d.baseCtx.Err() != nil {
+				return
+			}
+			// iterators expire after 15 minutes, a new one is obtained from the last read record.
+			logger.Err(errRecords).Msg("failed to get shard records")
+			iterator = nil
+			if !d.wait(d.cfg.RetryBackoff) {
+				return
+			}
+			continue
+		}
+
+		for _, record := range out.Records {
+			lastSequenceNumber = record.Dynamodb.SequenceNumber
+			if record.EventName != types.OperationTypeInsert {
+				continue
+			}
+			if d.forward(record.Dynamodb.Keys) {
+				d.lastSequence.Store(aws.ToString(lastSequenceNumber))
+			}
+		}
+
+		if out.NextShardIterator == nil {
+			logger.Info().Msg("shard was closed, stopping shard worker")
+			return
+		}
+		iterator = out.NextShardIterator
+		if len(out.Records) == 0 && !d.wait(d.cfg.PollingInterval) {
+			return
+		}
+	}
+}
+
+// forward schedules a forward job for the batch identified by keys. Returns true if the job was scheduled.
+func (d *DynamoDBStreams) forward(keys map[string]types.AttributeValue) bool {
+	batchID, ok := keys["batch_id"].(*types.AttributeValueMemberS)
+	if !ok {
+		defaultLoggerDynamoDB.Warn().Msg("received record without batch identifier")
+		return false
+	}
+	d.totalReads.Add(1)
+	if errFwd := d.fwd.Forward(batchID.Value); errFwd != nil {
+		defaultLoggerDynamoDB.Err(errFwd).
+			Str("batch_id", batchID.Value).
+			Msg("forwarder failed to proxy message")
+		return false
+	}
+	return true
+}
+
+func (d *DynamoDBStreams) Stats() Stats {
+	pos := d.lastSequence.Load()
+	// sequence numbers might exceed 64-bit integers, thus precision loss is accepted
+	offset, _ := strconv.ParseFloat(pos, 64)
+	return Stats{
+		IsRunning:  d.isRunning.Load(),
+		TotalReads: d.totalReads.Load(),
+		Position:   pos,
+		Offset:     offset,
+	}
+}
+
+// wait blocks the I/O for the specified duration. Returns false if the listener was shut down meanwhile.
+func (d *DynamoDBStreams) wait(duration time.Duration) bool {
+	select {
+	case <-d.baseCtx.Done():
+		return false
+	case <-time.After(duration):
+		return true
+	}
+}
+
+func (d *DynamoDBStreams) Close(ctx context.Context) error {
+	defer func() {
+		defaultLoggerDynamoDB.Info().
+			Uint64("total_reads", d.totalReads.Load()).
+			Msg("listener successfully shut down")
+	}()
+	defaultLoggerDynamoDB.Info().Msg("shutting down")
+	if d.baseCtxCancel != nil {
+		d.baseCtxCancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.inFlightProcesses.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -2,22 +2,59 @@ package listener
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"sync/atomic"
 
 	"github.com/alexandria-oss/streams/proxy/egress"
 )
 
-var ErrUnknownDriver = errors.New("streams_egress_proxy_agent: unknown listener driver")
+var (
+	ErrUnknownDriver          = errors.New("streams_egress_proxy_agent: unknown listener driver")
+	ErrDynamoDBStreamDisabled = errors.New("streams_egress_proxy_agent: egress table stream is disabled")
+)
 
 type Listener interface {
 	Start() error
 	Close(ctx context.Context) error
+	// Stats retrieves a snapshot of the Listener runtime statistics.
+	Stats() Stats
+}
+
+// Stats is a snapshot of Listener runtime statistics.
+type Stats struct {
+	IsRunning  bool   // Indicates if the Listener is connected to the log.
+	TotalReads uint64 // Total count of batches read from the log.
+	// Position of the latest batch forwarded from the log (e.g. WAL LSN, stream sequence number, batch identifier).
+	Position string
+	// Numeric form of Position if the log position is numeric (e.g. WAL LSN, stream sequence number). Zero otherwise.
+	Offset float64
+}
+
+// position is a concurrent-safe log position.
+type position struct {
+	value atomic.Value
+}
+
+func (p *position) Store(pos string) {
+	p.value.Store(pos)
+}
+
+func (p *position) Load() string {
+	pos, _ := p.value.Load().(string)
+	return pos
 }
 
-func NewListener(driver string, fwd egress.Forwarder) (Listener, error) {
+func NewListener(driver string, fwd egress.Forwarder, db *sql.DB) (Listener, error) {
 	switch driver {
 	case WALDriver:
 		return NewWAL(fwd), nil
+	case NotifyDriver:
+		return NewNotify(fwd, db), nil
+	case DynamoDBStreamsDriver:
+		return NewDynamoDBStreams(fwd), nil
+	case MongoDBChangeStreamDriver:
+		return NewMongoDBChangeStream(fwd), nil
 	default:
 		return nil, ErrUnknownDriver
 	}
@@ -0,0 +1,172 @@
+package listener
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const MongoDBChangeStreamDriver = "mongodb_change_stream"
+
+var defaultLoggerMongoDB = agent.DefaultLogger.With().
+	Str("listener_driver", MongoDBChangeStreamDriver).
+	Logger()
+
+type mongoDBChangeStreamConfig struct {
+	URI              string
+	Database         string
+	EgressCollection string
+	ReconnectBackoff time.Duration
+}
+
+func newMongoDBChangeStreamConfig() mongoDBChangeStreamConfig {
+	viper.SetDefault("mongodb.egress_collection", egress.DefaultEgressTableName)
+	viper.SetDefault("mongodb.change_stream.reconnect_backoff", time.Second*5)
+	return mongoDBChangeStreamConfig{
+		URI:              viper.GetString("mongodb.uri"),
+		Database:         viper.GetString("mongodb.database"),
+		EgressCollection: viper.GetString("mongodb.egress_collection"),
+		ReconnectBackoff: viper.GetDuration("mongodb.change_stream.reconnect_backoff"),
+	}
+}
+
+// changeEvent is the subset of a MongoDB change event used by MongoDBChangeStream.
+type changeEvent struct {
+	DocumentKey struct {
+		ID string `bson:"_id"`
+	} `bson:"documentKey"`
+}
+
+// A MongoDBChangeStream listener watches insert operations of the egress collection through MongoDB change streams.
+// The stream is resumed from the last received event after a failure.
+type MongoDBChangeStream struct {
+	cfg               mongoDBChangeStreamConfig
+	fwd               egress.Forwarder
+	client            *mongo.Client
+	baseCtx           context.Context
+	baseCtxCancel     context.CancelFunc
+	inFlightProcesses sync.WaitGroup
+	resumeToken       bson.Raw
+	totalReads        atomic.Uint64
+	isRunning         atomic.Bool
+	lastBatchID       position
+}
+
+var _ Listener = &MongoDBChangeStream{}
+
+func NewMongoDBChangeStream(fwd egress.Forwarder) *MongoDBChangeStream {
+	return &MongoDBChangeStream{
+		cfg: newMongoDBChangeStreamConfig(),
+		fwd: fwd,
+	}
+}
+
+func (m *MongoDBChangeStream) Start() (err error) {
+	m.baseCtx, m.baseCtxCancel = context.WithCancel(context.Background())
+	m.inFlightProcesses.Add(1)
+	defer m.inFlightProcesses.Done()
+	m.client, err = mongo.Connect(m.baseCtx, options.Client().ApplyURI(m.cfg.URI))
+	if err != nil {
+		return err
+	}
+
+	collection := m.client.Database(m.cfg.Database).Collection(m.cfg.EgressCollection)
+	for {
+		errWatch := m.watch(collection)
+		if m.baseCtx.Err() != nil {
+			return nil
+		}
+		defaultLoggerMongoDB.Err(errWatch).
+			Dur("reconnect_backoff", m.cfg.ReconnectBackoff).
+			Msg("change stream failed, resuming")
+		select {
+		case <-m.baseCtx.Done():
+			return nil
+		case <-time.After(m.cfg.ReconnectBackoff):
+		}
+	}
+}
+
+func (m *MongoDBChangeStream) watch(collection *mongo.Collection) error {
+	opts := options.ChangeStream()
+	if m.resumeToken != nil {
+		opts.SetResumeAfter(m.resumeToken)
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": "insert"}}},
+	}
+	stream, err := collection.Watch(m.baseCtx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = stream.Close(context.Background())
+	}()
+	defaultLoggerMongoDB.Info().
+		Str("database", m.cfg.Database).
+		Str("collection", m.cfg.EgressCollection).
+		Msg("watching egress collection")
+	m.isRunning.Store(true)
+	defer m.isRunning.Store(false)
+
+	for stream.Next(m.baseCtx) {
+		event := changeEvent{}
+		if errDecode := stream.Decode(&event); errDecode != nil {
+			defaultLoggerMongoDB.Err(errDecode).Msg("failed to decode change event")
+		} else {
+			m.totalReads.Add(1)
+			if errFwd := m.fwd.Forward(event.DocumentKey.ID); errFwd != nil {
+				defaultLoggerMongoDB.Err(errFwd).
+					Str("batch_id", event.DocumentKey.ID).
+					Msg("forwarder failed to proxy message")
+			} else {
+				m.lastBatchID.Store(event.DocumentKey.ID)
+			}
+		}
+		m.resumeToken = stream.ResumeToken()
+	}
+	return stream.Err()
+}
+
+func (m *MongoDBChangeStream) Stats() Stats {
+	return Stats{
+		IsRunning:  m.isRunning.Load(),
+		TotalReads: m.totalReads.Load(),
+		Position:   m.lastBatchID.Load(),
+	}
+}
+
+func (m *MongoDBChangeStream) Close(ctx context.Context) error {
+	defer func() {
+		defaultLoggerMongoDB.Info().
+			Uint64("total_reads", m.totalReads.Load()).
+			Msg("listener successfully shut down")
+	}()
+	defaultLoggerMongoDB.Info().Msg("shutting down")
+	if m.baseCtxCancel != nil {
+		m.baseCtxCancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.inFlightProcesses.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if m.client == nil {
+		return nil
+	}
+	return m.client.Disconnect(ctx)
+}
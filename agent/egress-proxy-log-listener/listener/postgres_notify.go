@@ -0,0 +1,266 @@
+package listener
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	streamsql "github.com/alexandria-oss/streams/driver/sql"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/jackc/pgx/v5"
+	"github.com/spf13/viper"
+)
+
+const NotifyDriver = "postgres_notify"
+
+var defaultLoggerNotify = agent.DefaultLogger.With().
+	Str("listener_driver", NotifyDriver).
+	Logger()
+
+type notifyConfig struct {
+	ConnectionString string
+	Channel          string
+	EgressTable      string
+	WorkerTimeout    time.Duration
+	ReconnectBackoff time.Duration
+	SweepInterval    time.Duration
+	SweepMinAge      time.Duration
+	SweepBatchSize   int
+}
+
+func newNotifyConfig() notifyConfig {
+	viper.SetDefault("postgres.egress_table", "streams_egress")
+	viper.SetDefault("postgres.notify.channel", "streams_egress")
+	viper.SetDefault("postgres.notify.worker_timeout", time.Second*5)
+	viper.SetDefault("postgres.notify.reconnect_backoff", time.Second*5)
+	viper.SetDefault("postgres.notify.sweep_interval", time.Minute)
+	viper.SetDefault("postgres.notify.sweep_min_age", time.Minute*2)
+	viper.SetDefault("postgres.notify.sweep_batch_size", 100)
+	return notifyConfig{
+		ConnectionString: viper.GetString("postgres.connection_string"),
+		Channel:          viper.GetString("postgres.notify.channel"),
+		EgressTable:      viper.GetString("postgres.egress_table"),
+		WorkerTimeout:    viper.GetDuration("postgres.notify.worker_timeout"),
+		ReconnectBackoff: viper.GetDuration("postgres.notify.reconnect_backoff"),
+		SweepInterval:    viper.GetDuration("postgres.notify.sweep_interval"),
+		SweepMinAge:      viper.GetDuration("postgres.notify.sweep_min_age"),
+		SweepBatchSize:   viper.GetInt("postgres.notify.sweep_batch_size"),
+	}
+}
+
+// A Notify listener subscribes to a Postgres channel (LISTEN/NOTIFY) fed by either a trigger on the egress table
+// or by writers (e.g. driver/pgx.Writer). Notification payloads are batch identifiers.
+//
+// Either the trigger or writer notifications must be enabled, never both. Otherwise, batches are forwarded twice.
+//
+// Unlike WAL, Notify requires no replication privileges. As notifications are not persisted by Postgres, Notify
+// periodically sweeps the egress table for batches missed (e.g. notified during a connection failure). Each sweep
+// fetches a page of pending batches, resuming from the previous page until the egress table is fully scanned.
+type Notify struct {
+	cfg               notifyConfig
+	fwd               egress.Forwarder
+	storage           egress.Storage
+	baseCtx           context.Context
+	baseCtxCancel     context.CancelFunc
+	inFlightProcesses sync.WaitGroup
+	totalReads        atomic.Uint64
+	totalSweeps       atomic.Uint64
+	isRunning         atomic.Bool
+	lastBatchID       position
+	sweepMu           sync.Mutex
+	sweepCursor       string
+}
+
+var _ Listener = &Notify{}
+
+func NewNotify(fwd egress.Forwarder, db *sql.DB) *Notify {
+	cfg := newNotifyConfig()
+	return newNotify(cfg, fwd, streamsql.NewEgressStorageWithConfig(db, egress.StorageConfig{
+		TableName: cfg.EgressTable,
+	}))
+}
+
+func newNotify(cfg notifyConfig, fwd egress.Forwarder, storage egress.Storage) *Notify {
+	baseCtx, cancel := context.WithCancel(context.Background())
+	return &Notify{
+		cfg:           cfg,
+		fwd:           fwd,
+		storage:       storage,
+		baseCtx:       baseCtx,
+		baseCtxCancel: cancel,
+	}
+}
+
+func (n *Notify) Start() error {
+	if n.baseCtx.Err() != nil {
+		return nil // listener was closed before starting
+	}
+	n.inFlightProcesses.Add(2) // listener loop and sweeper
+	defer n.inFlightProcesses.Done()
+	go n.startSweeper()
+
+	for {
+		err := n.listen()
+		if n.baseCtx.Err() != nil {
+			return nil
+		}
+		defaultLoggerNotify.Err(err).
+			Dur("reconnect_backoff", n.cfg.ReconnectBackoff).
+			Msg("listener connection failed, reconnecting")
+		select {
+		case <-n.baseCtx.Done():
+			return nil
+		case <-time.After(n.cfg.ReconnectBackoff):
+		}
+	}
+}
+
+func (n *Notify) listen() error {
+	conn, err := pgx.Connect(n.baseCtx, n.cfg.ConnectionString)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), n.cfg.WorkerTimeout)
+		defer cancel()
+		if errClose := conn.Close(closeCtx); errClose != nil {
+			defaultLoggerNotify.Err(errClose).Msg("failed to close listener connection")
+		}
+	}()
+
+	if _, err = conn.Exec(n.baseCtx, fmt.Sprintf("LISTEN %s", pgx.Identifier{n.cfg.Channel}.Sanitize())); err != nil {
+		return err
+	}
+	defaultLoggerNotify.Info().
+		Str("channel", n.cfg.Channel).
+		Msg("listening for notifications")
+	n.isRunning.Store(true)
+	defer n.isRunning.Store(false)
+
+	// sweep right after (re)connecting as notifications might have been missed while disconnected.
+	n.inFlightProcesses.Add(1)
+	go func() {
+		defer n.inFlightProcesses.Done()
+		n.sweep()
+	}()
+	for {
+		notification, errWait := conn.WaitForNotification(n.baseCtx)
+		if errWait != nil {
+			return errWait
+		}
+		n.totalReads.Add(1)
+		n.forward(notification.Payload)
+	}
+}
+
+func (n *Notify) forward(batchID string) {
+	// using background ctx to avoid in-flight process early stopping, thus not gracefully shutting down.
+	scopedCtx, cancel := context.WithTimeout(context.Background(), n.cfg.WorkerTimeout)
+	defer cancel()
+	batch, err := n.storage.GetBatch(scopedCtx, batchID)
+	if errors.Is(err, sql.ErrNoRows) {
+		defaultLoggerNotify.Warn().
+			Str("batch_id", batchID).
+			Msg("batch not found, it might have been forwarded already")
+		return
+	} else if err != nil {
+		defaultLoggerNotify.Err(err).
+			Str("batch_id", batchID).
+			Msg("failed to fetch batch")
+		return
+	}
+
+	if errFwd := n.fwd.ForwardBatch(batch); errFwd != nil {
+		defaultLoggerNotify.Err(errFwd).
+			Str("batch_id", batchID).
+			Msg("forwarder failed to proxy message")
+		return
+	}
+	n.lastBatchID.Store(batchID)
+}
+
+func (n *Notify) startSweeper() {
+	defer n.inFlightProcesses.Done()
+	if n.cfg.SweepInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(n.cfg.SweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.baseCtx.Done():
+			return
+		case <-ticker.C:
+			n.sweep()
+		}
+	}
+}
+
+// sweep schedules forward jobs for a page of pending batches older than notifyConfig.SweepMinAge.
+func (n *Notify) sweep() {
+	n.sweepMu.Lock()
+	defer n.sweepMu.Unlock()
+	scopedCtx, cancel := context.WithTimeout(context.Background(), n.cfg.WorkerTimeout)
+	defer cancel()
+	batches, cursor, err := n.storage.ListPending(scopedCtx, time.Now().UTC().Add(-n.cfg.SweepMinAge),
+		n.sweepCursor, n.cfg.SweepBatchSize)
+	if err != nil {
+		defaultLoggerNotify.Err(err).Msg("failed to sweep egress table")
+		return
+	}
+	n.sweepCursor = cursor // an empty cursor restarts the scan in the next sweep
+
+	total := 0
+	for _, batch := range batches {
+		if errFwd := n.fwd.ForwardBatch(batch); errFwd != nil {
+			defaultLoggerNotify.Err(errFwd).
+				Str("batch_id", batch.BatchID).
+				Msg("forwarder failed to proxy message")
+			continue
+		}
+		total++
+	}
+	n.totalSweeps.Add(1)
+	if total > 0 {
+		defaultLoggerNotify.Info().
+			Int("total_batches", total).
+			Msg("swept missed batches")
+	}
+}
+
+func (n *Notify) Stats() Stats {
+	return Stats{
+		IsRunning:  n.isRunning.Load(),
+		TotalReads: n.totalReads.Load(),
+		Position:   n.lastBatchID.Load(),
+	}
+}
+
+func (n *Notify) Close(ctx context.Context) error {
+	defer func() {
+		defaultLoggerNotify.Info().
+			Uint64("total_reads", n.totalReads.Load()).
+			Uint64("total_sweeps", n.totalSweeps.Load()).
+			Msg("listener successfully shut down")
+	}()
+	defaultLoggerNotify.Info().Msg("shutting down")
+	n.baseCtxCancel()
+
+	done := make(chan struct{})
+	go func() {
+		n.inFlightProcesses.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,114 @@
+package listener
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedStorageStub lists one pending batch per page, page cursors are batch identifiers.
+type pagedStorageStub struct {
+	egress.NoopStorage
+	mu       sync.Mutex
+	batchIDs []string
+	cursors  []string
+}
+
+func (s *pagedStorageStub) ListPending(_ context.Context, _ time.Time, cursor string,
+	_ int) ([]egress.Batch, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors = append(s.cursors, cursor)
+	next := 0
+	for i, batchID := range s.batchIDs {
+		if batchID == cursor {
+			next = i + 1
+		}
+	}
+	batchID := s.batchIDs[next]
+	if next == len(s.batchIDs)-1 {
+		return []egress.Batch{{BatchID: batchID}}, "", nil
+	}
+	return []egress.Batch{{BatchID: batchID}}, batchID, nil
+}
+
+func TestNotify_Sweep(t *testing.T) {
+	fwdCfg := egress.NewForwarderDefaultConfig()
+	fwdCfg.Storage = egress.NoopStorage{}
+	fwdCfg.Writer = streams.NoopWriter{}
+	fwd := egress.NewForwarder(fwdCfg)
+	go fwd.Start()
+	defer fwd.Shutdown()
+
+	storage := &pagedStorageStub{batchIDs: []string{"a", "b", "c"}}
+	n := &Notify{
+		cfg: notifyConfig{
+			WorkerTimeout:  time.Second,
+			SweepBatchSize: 1,
+		},
+		fwd:     fwd,
+		storage: storage,
+	}
+	for i := 0; i < 4; i++ {
+		n.sweep()
+	}
+	// scan restarts once the egress table was fully swept
+	assert.Equal(t, []string{"", "a", "b", ""}, storage.cursors)
+	require.Eventually(t, func() bool {
+		return fwd.Stats().ForwardedBatches == 4
+	}, time.Second, time.Millisecond)
+}
+
+func TestNotify_Close(t *testing.T) {
+	cfg := notifyConfig{
+		ConnectionString: "postgres://postgres@127.0.0.1:1/postgres",
+		WorkerTimeout:    time.Second,
+		ReconnectBackoff: time.Hour,
+		SweepInterval:    time.Hour,
+	}
+	tests := []struct {
+		name         string
+		inCloseFirst bool
+	}{
+		{
+			name:         "close before start",
+			inCloseFirst: true,
+		},
+		{
+			name: "close while reconnecting",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newNotify(cfg, egress.Forwarder{}, egress.NoopStorage{})
+			if tt.inCloseFirst {
+				require.NoError(t, n.Close(context.Background()))
+			}
+			errStart := make(chan error, 1)
+			go func() {
+				errStart <- n.Start()
+			}()
+			if !tt.inCloseFirst {
+				time.Sleep(time.Millisecond * 50) // let the listener fail to connect and wait for reconnection
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				require.NoError(t, n.Close(ctx))
+			}
+
+			select {
+			case err := <-errStart:
+				assert.NoError(t, err)
+			case <-time.After(time.Second):
+				t.Fatal("expected listener to stop")
+			}
+			assert.False(t, n.Stats().IsRunning)
+		})
+	}
+}
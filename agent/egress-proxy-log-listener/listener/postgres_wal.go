@@ -66,6 +66,9 @@ type WAL struct {
 	baseCtxCancel     context.CancelFunc
 	inFlightProcesses sync.WaitGroup
 	totalReads        atomic.Uint64
+	isRunning         atomic.Bool
+	lastForwardedLSN  position
+	lastForwardedOff  atomic.Uint64
 
 	conn                  *pgconn.PgConn
 	slotConfirmedFlushLSN pglogrepl.LSN
@@ -221,6 +224,8 @@ func (w *WAL) startLogListener() error {
 
 	// start reader node from last confirmed read offset
 	//lastSlotReaderOffset := w.sysInfo.XLogPos
+	w.isRunning.Store(true)
+	defer w.isRunning.Store(false)
 	w.walReaderOffset = w.slotConfirmedFlushLSN
 	w.msgTypeMap = pgtype.NewMap()
 	w.relationMsgMap = map[uint32]*pglogrepl.RelationMessage{}
@@ -343,7 +348,7 @@ func (w *WAL) processCopyData(cpData *pgproto3.CopyData) error {
 			return errLogParse
 		}
 
-		if err := w.processLogicMessage(logicMsg); err != nil {
+		if err := w.processLogicMessage(xld.WALStart, logicMsg); err != nil {
 			return err
 		}
 
@@ -361,7 +366,7 @@ func decodeWALTextColumnData(mi *pgtype.Map, data []byte, dataType uint32) (inte
 	return string(data), nil
 }
 
-func (w *WAL) processLogicMessage(msg pglogrepl.Message) error {
+func (w *WAL) processLogicMessage(lsn pglogrepl.LSN, msg pglogrepl.Message) error {
 	switch logMsg := msg.(type) {
 	case *pglogrepl.RelationMessage:
 		w.relationMsgMap[logMsg.RelationID] = logMsg
@@ -402,6 +407,9 @@ func (w *WAL) processLogicMessage(msg pglogrepl.Message) error {
 		}
 		if fwdErr := w.fwd.ForwardBatch(batch); fwdErr != nil {
 			defaultLoggerWAL.Err(fwdErr).Msg("forwarder failed to proxy message")
+		} else {
+			w.lastForwardedLSN.Store(lsn.String())
+			w.lastForwardedOff.Store(uint64(lsn))
 		}
 	default:
 		defaultLoggerWAL.Warn().
@@ -412,6 +420,15 @@ func (w *WAL) processLogicMessage(msg pglogrepl.Message) error {
 	return nil
 }
 
+func (w *WAL) Stats() Stats {
+	return Stats{
+		IsRunning:  w.isRunning.Load(),
+		TotalReads: w.totalReads.Load(),
+		Position:   w.lastForwardedLSN.Load(),
+		Offset:     float64(w.lastForwardedOff.Load()),
+	}
+}
+
 func (w *WAL) Close(ctx context.Context) error {
 	defer func() {
 		defaultLoggerWAL.Info().
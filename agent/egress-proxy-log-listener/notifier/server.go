@@ -0,0 +1,198 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/spf13/viper"
+)
+
+var defaultLoggerServer = agent.DefaultLogger.With().
+	Str("process", "notification_server").
+	Logger()
+
+type serverConfig struct {
+	Network      string
+	Address      string
+	IdleTimeout  time.Duration
+	MaxFrameSize uint32
+}
+
+func newServerConfig() serverConfig {
+	viper.SetDefault("notifier.network", "tcp")
+	viper.SetDefault("notifier.address", ":52450")
+	viper.SetDefault("notifier.idle_timeout", time.Minute*5)
+	viper.SetDefault("notifier.max_frame_size", egress.DefaultNotificationMaxFrameSize)
+	return serverConfig{
+		Network:      viper.GetString("notifier.network"),
+		Address:      viper.GetString("notifier.address"),
+		IdleTimeout:  viper.GetDuration("notifier.idle_timeout"),
+		MaxFrameSize: viper.GetUint32("notifier.max_frame_size"),
+	}
+}
+
+// A Server receives notifications from systems (egress.NetworkNotifier) using the egress notification protocol
+// and triggers forward jobs for each notified batch.
+type Server struct {
+	cfg                serverConfig
+	fwd                egress.Forwarder
+	listenerMu         sync.Mutex
+	listener           net.Listener
+	isShutdown         atomic.Bool
+	inFlightConns      sync.WaitGroup
+	conns              sync.Map
+	totalNotifications atomic.Uint64
+}
+
+// NewServer allocates a Server instance.
+func NewServer(fwd egress.Forwarder) *Server {
+	return &Server{
+		cfg: newServerConfig(),
+		fwd: fwd,
+	}
+}
+
+// Start listens to the configured address, blocking the I/O until Server.Close is called.
+//
+// Temporary accept failures (e.g. file descriptors exhausted) are retried with a backoff.
+func (s *Server) Start() error {
+	listener, err := net.Listen(s.cfg.Network, s.cfg.Address)
+	if err != nil {
+		return err
+	}
+	s.listenerMu.Lock()
+	if s.isShutdown.Load() {
+		s.listenerMu.Unlock()
+		return listener.Close()
+	}
+	s.listener = listener
+	s.listenerMu.Unlock()
+	defaultLoggerServer.Info().
+		Str("network", s.cfg.Network).
+		Str("address", listener.Addr().String()).
+		Msg("listening for notifications")
+
+	var acceptBackoff time.Duration
+	for {
+		conn, errAccept := listener.Accept()
+		if errAccept != nil {
+			if s.isShutdown.Load() || errors.Is(errAccept, net.ErrClosed) {
+				return nil
+			}
+			var netErr net.Error
+			if !errors.As(errAccept, &netErr) || !netErr.Temporary() {
+				return errAccept
+			}
+			acceptBackoff = nextAcceptBackoff(acceptBackoff)
+			defaultLoggerServer.Err(errAccept).
+				Dur("backoff", acceptBackoff).
+				Msg("failed to accept connection, retrying")
+			time.Sleep(acceptBackoff)
+			continue
+		}
+		acceptBackoff = 0
+
+		s.inFlightConns.Add(1)
+		s.conns.Store(conn, struct{}{})
+		go s.serveConn(conn)
+	}
+}
+
+// nextAcceptBackoff doubles the accept retry backoff, from 5ms up to 1s.
+func nextAcceptBackoff(backoff time.Duration) time.Duration {
+	if backoff == 0 {
+		return time.Millisecond * 5
+	} else if backoff *= 2; backoff > time.Second {
+		return time.Second
+	}
+	return backoff
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.conns.Delete(conn)
+		if errClose := conn.Close(); errClose != nil && !s.isShutdown.Load() {
+			defaultLoggerServer.Err(errClose).Msg("failed to close connection")
+		}
+		s.inFlightConns.Done()
+	}()
+
+	remoteAddr := conn.RemoteAddr().String()
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(s.cfg.IdleTimeout)); err != nil {
+			return
+		}
+		payload, err := egress.ReadNotificationFrame(conn, s.cfg.MaxFrameSize)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !s.isShutdown.Load() {
+				defaultLoggerServer.Warn().
+					Err(err).
+					Str("remote_address", remoteAddr).
+					Msg("dropping connection")
+			}
+			return
+		}
+
+		var ack []byte
+		batchID := string(payload)
+		if errFwd := s.fwd.Forward(batchID); errFwd != nil {
+			defaultLoggerServer.Err(errFwd).
+				Str("batch_id", batchID).
+				Msg("forwarder failed to schedule batch")
+			ack = []byte(errFwd.Error())
+		} else {
+			s.totalNotifications.Add(1)
+		}
+
+		if err = conn.SetWriteDeadline(time.Now().Add(s.cfg.IdleTimeout)); err != nil {
+			return
+		}
+		if err = egress.WriteNotificationFrame(conn, ack); err != nil {
+			defaultLoggerServer.Err(err).
+				Str("remote_address", remoteAddr).
+				Msg("failed to acknowledge notification")
+			return
+		}
+	}
+}
+
+// Close stops accepting connections and closes every active connection.
+func (s *Server) Close(ctx context.Context) error {
+	defer func() {
+		defaultLoggerServer.Info().
+			Uint64("total_notifications", s.totalNotifications.Load()).
+			Msg("server successfully shut down")
+	}()
+	s.listenerMu.Lock()
+	s.isShutdown.Store(true)
+	listener := s.listener
+	s.listenerMu.Unlock()
+	if listener == nil {
+		return nil
+	}
+
+	err := listener.Close()
+	s.conns.Range(func(key, _ any) bool {
+		_ = key.(net.Conn).Close()
+		return true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlightConns.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
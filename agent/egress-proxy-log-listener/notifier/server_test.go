@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer() *Server {
+	return &Server{
+		cfg: serverConfig{
+			Network:     "tcp",
+			Address:     "127.0.0.1:0",
+			IdleTimeout: time.Second,
+		},
+	}
+}
+
+// run with -race flag.
+func TestServer_StartClose(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		srv := newTestServer()
+		errs := make(chan error, 1)
+		go func() {
+			errs <- srv.Start()
+		}()
+		if i%2 == 0 {
+			require.Eventually(t, func() bool {
+				srv.listenerMu.Lock()
+				defer srv.listenerMu.Unlock()
+				return srv.listener != nil
+			}, time.Second, time.Millisecond)
+		}
+		require.NoError(t, srv.Close(context.TODO()))
+		select {
+		case err := <-errs:
+			assert.NoError(t, err)
+		case <-time.After(time.Second * 5):
+			t.Fatal("server did not stop after close")
+		}
+	}
+}
+
+func TestServer_CloseBeforeStart(t *testing.T) {
+	srv := newTestServer()
+	require.NoError(t, srv.Close(context.TODO()))
+	assert.NoError(t, srv.Start())
+	assert.Nil(t, srv.listener)
+}
+
+func TestServer_ListenerClosed(t *testing.T) {
+	srv := newTestServer()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Start()
+	}()
+	var listener net.Listener
+	require.Eventually(t, func() bool {
+		srv.listenerMu.Lock()
+		defer srv.listenerMu.Unlock()
+		listener = srv.listener
+		return listener != nil
+	}, time.Second, time.Millisecond)
+
+	// listener closed by a third party, Start must not spin on Accept
+	require.NoError(t, listener.Close())
+	select {
+	case err := <-errs:
+		assert.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("server did not stop after listener closure")
+	}
+}
+
+func TestNextAcceptBackoff(t *testing.T) {
+	backoff := nextAcceptBackoff(0)
+	assert.Equal(t, time.Millisecond*5, backoff)
+	assert.Equal(t, time.Millisecond*10, nextAcceptBackoff(backoff))
+	for i := 0; i < 10; i++ {
+		backoff = nextAcceptBackoff(backoff)
+	}
+	assert.Equal(t, time.Second, backoff)
+}
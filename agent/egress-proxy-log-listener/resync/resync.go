@@ -0,0 +1,53 @@
+package resync
+
+import (
+	"context"
+	stdlog "log"
+	"time"
+
+	agent "github.com/alexandria-oss/streams/agent/egress-proxy-wal-listener"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/spf13/viper"
+)
+
+var defaultLoggerResync = agent.DefaultLogger.With().
+	Str("process", "resync").
+	Logger()
+
+func newDefaultConfig() {
+	defCfg := egress.NewResyncerDefaultConfig()
+	viper.SetDefault("resync.min_age", defCfg.MinAge)
+	viper.SetDefault("resync.page_size", defCfg.PageSize)
+	viper.SetDefault("resync.rate_limit", defCfg.RateLimit)
+	viper.SetDefault("resync.list_timeout", defCfg.ListTimeout)
+	viper.SetDefault("resync.progress_interval", defCfg.ProgressInterval)
+}
+
+// NewResyncer allocates an egress.Resyncer scheduling pending batches from storage into fwd.
+func NewResyncer(fwd egress.Forwarder, storage egress.Storage) egress.Resyncer {
+	newDefaultConfig()
+	return egress.NewResyncer(egress.ResyncerConfig{
+		Storage:   storage,
+		Forwarder: fwd,
+		Logger: stdlog.New(agent.DefaultLogger.With().Str("level", "info").
+			Str("process", "resync").Logger(), "", 0),
+		MinAge:           viper.GetDuration("resync.min_age"),
+		PageSize:         viper.GetInt("resync.page_size"),
+		RateLimit:        viper.GetInt("resync.rate_limit"),
+		ListTimeout:      viper.GetDuration("resync.list_timeout"),
+		ProgressInterval: viper.GetDuration("resync.progress_interval"),
+	})
+}
+
+// Run re-forwards every pending batch from storage, blocking the I/O until every batch was scheduled or ctx is done.
+func Run(ctx context.Context, fwd egress.Forwarder, storage egress.Storage) error {
+	progress, err := NewResyncer(fwd, storage).Run(ctx)
+	defaultLoggerResync.Info().
+		Uint64("listed_batches", progress.ListedBatches).
+		Uint64("scheduled_batches", progress.ScheduledBatches).
+		Uint64("failed_batches", progress.FailedBatches).
+		Bool("is_done", progress.IsDone).
+		Dur("elapsed", time.Since(progress.StartTime)).
+		Msg("resync finished")
+	return err
+}
@@ -3,7 +3,11 @@ CREATE TABLE IF NOT EXISTS streams_egress(
      batch_id VARCHAR(27) PRIMARY KEY,
      message_count INTEGER DEFAULT 0,
      raw_data BYTEA NOT NULL,
-     insert_time TIMESTAMP DEFAULT NOW()
+     insert_time TIMESTAMP DEFAULT NOW(),
+     write_retries INTEGER DEFAULT 0,
+     last_write_error TEXT,
+     parked BOOLEAN DEFAULT FALSE,
+     publish_time TIMESTAMP
 );
 
 -- SELECT pg_create_logical_replication_slot('streams_egress_proxy', 'pgoutput');
@@ -14,5 +18,6 @@ GRANT ALL ON SCHEMA public TO streams_egress_proxy_replicator;
 
 GRANT SELECT ON public.streams_egress TO streams_egress_proxy_replicator;
 GRANT DELETE ON public.streams_egress TO streams_egress_proxy_replicator;
+GRANT UPDATE ON public.streams_egress TO streams_egress_proxy_replicator;
 
 CREATE PUBLICATION streams_egress_proxy FOR TABLE public.streams_egress ;
@@ -0,0 +1,170 @@
+package amazon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Batch request limits shared by Amazon SNS (PublishBatch) and Amazon SQS (SendMessageBatch).
+const (
+	MaxBatchEntries = 10
+	MaxBatchBytes   = 256 << 10
+)
+
+// A BatchEntry is a message entry of a batch request (e.g. types.SendMessageBatchRequestEntry).
+type BatchEntry[T any] struct {
+	MessageID string // Identifier of the message, used as entry identifier within batch requests.
+	Size      int    // Size of the entry payload (i.e. body and attributes) in bytes.
+	Entry     T
+}
+
+// UnsentErrorCode is the MessageError code of entries not sent as a previous chunk of an ordered batch failed.
+const UnsentErrorCode = "Unsent"
+
+// A MessageError is a message rejected by an Amazon service within a batch request.
+type MessageError struct {
+	MessageID string
+	Code      string
+	Message   string
+	// The message was rejected because of the request (e.g. invalid attributes), thus it is not retried.
+	SenderFault bool
+}
+
+var _ error = MessageError{}
+
+func (e MessageError) Error() string {
+	return fmt.Sprintf("streams.amazon: message <%s> not written, %s: %s", e.MessageID, e.Code, e.Message)
+}
+
+// BatchWriteFunc writes entries in a single batch request, returning rejected entries as MessageError(s).
+type BatchWriteFunc[T any] func(ctx context.Context, entries []BatchEntry[T]) ([]MessageError, error)
+
+// RetryConfig is the configuration schema for retries of entries rejected within batch requests.
+type RetryConfig struct {
+	// Maximum count of retries for entries rejected by the service (e.g. throttled entries). Disabled if < 0
+	// (default 3).
+	MaxRetries int
+	// Time duration to wait before the first retry, doubled on each retry (default 100ms).
+	RetryBackoff time.Duration
+}
+
+// SplitBatch splits entries into chunks holding up to MaxBatchEntries entries and MaxBatchBytes. Entries exceeding
+// MaxBatchBytes are placed in chunks of their own, so services reject them individually.
+func SplitBatch[T any](entries []BatchEntry[T]) [][]BatchEntry[T] {
+	chunks := make([][]BatchEntry[T], 0, len(entries)/MaxBatchEntries+1)
+	chunk := make([]BatchEntry[T], 0, MaxBatchEntries)
+	chunkBytes := 0
+	for _, entry := range entries {
+		if len(chunk) == MaxBatchEntries || (len(chunk) > 0 && chunkBytes+entry.Size > MaxBatchBytes) {
+			chunks = append(chunks, chunk)
+			chunk = make([]BatchEntry[T], 0, MaxBatchEntries)
+			chunkBytes = 0
+		}
+		chunk = append(chunk, entry)
+		chunkBytes += entry.Size
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// WriteBatch splits entries into chunks (SplitBatch) and writes them through writeFunc. Entries rejected by the
+// service are retried with an exponential backoff, unless they were rejected because of the request
+// (MessageError.SenderFault). Entries rejected once every retry was exhausted are returned as MessageError(s).
+//
+// Chunks are written concurrently, unless ordered is set (e.g. FIFO queues and topics) so chunks are written
+// sequentially. Ordered writes stop at the first failed chunk to preserve ordering, entries of the remaining chunks
+// are returned as MessageError(s) with UnsentErrorCode.
+func WriteBatch[T any](ctx context.Context, cfg RetryConfig, entries []BatchEntry[T], ordered bool,
+	writeFunc BatchWriteFunc[T]) error {
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Millisecond * 100
+	}
+
+	chunks := SplitBatch(entries)
+	errs := &multierror.Error{}
+	if ordered {
+		for i, chunk := range chunks {
+			if err := writeChunk(ctx, cfg, chunk, writeFunc); err != nil {
+				errs = multierror.Append(errs, err)
+				errs = appendUnsent(errs, chunks[i+1:])
+				break
+			}
+		}
+		return errs.ErrorOrNil()
+	}
+
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	wg.Add(len(chunks))
+	for _, chunk := range chunks {
+		go func(chunkCopy []BatchEntry[T]) {
+			defer wg.Done()
+			if err := writeChunk(ctx, cfg, chunkCopy, writeFunc); err != nil {
+				mu.Lock() // multi error is not concurrent safe
+				errs = multierror.Append(errs, err)
+				mu.Unlock()
+			}
+		}(chunk)
+	}
+	wg.Wait()
+	return errs.ErrorOrNil()
+}
+
+func appendUnsent[T any](errs *multierror.Error, chunks [][]BatchEntry[T]) *multierror.Error {
+	for _, chunk := range chunks {
+		for _, entry := range chunk {
+			errs = multierror.Append(errs, MessageError{
+				MessageID: entry.MessageID,
+				Code:      UnsentErrorCode,
+				Message:   "a previous message of the ordered batch was not written",
+			})
+		}
+	}
+	return errs
+}
+
+func writeChunk[T any](ctx context.Context, cfg RetryConfig, chunk []BatchEntry[T],
+	writeFunc BatchWriteFunc[T]) error {
+	backoff := cfg.RetryBackoff
+	errs := &multierror.Error{}
+	for attempt := 0; ; attempt++ {
+		failed, err := writeFunc(ctx, chunk)
+		if err != nil {
+			return multierror.Append(errs, err)
+		}
+
+		chunkIndex := make(map[string]BatchEntry[T], len(chunk))
+		for _, entry := range chunk {
+			chunkIndex[entry.MessageID] = entry
+		}
+		retryChunk := make([]BatchEntry[T], 0, len(failed))
+		for _, errMsg := range failed {
+			entry, ok := chunkIndex[errMsg.MessageID]
+			if ok && !errMsg.SenderFault && attempt < cfg.MaxRetries {
+				retryChunk = append(retryChunk, entry)
+				continue
+			}
+			errs = multierror.Append(errs, errMsg)
+		}
+		if len(retryChunk) == 0 {
+			return errs.ErrorOrNil()
+		}
+
+		select {
+		case <-ctx.Done():
+			return multierror.Append(errs, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		chunk = retryChunk
+	}
+}
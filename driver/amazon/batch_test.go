@@ -0,0 +1,216 @@
+package amazon_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams/driver/amazon"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEntries(count, size int) []amazon.BatchEntry[string] {
+	entries := make([]amazon.BatchEntry[string], 0, count)
+	for i := 0; i < count; i++ {
+		entries = append(entries, amazon.BatchEntry[string]{
+			MessageID: strconv.Itoa(i),
+			Size:      size,
+			Entry:     "entry-" + strconv.Itoa(i),
+		})
+	}
+	return entries
+}
+
+func TestSplitBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		inCount  int
+		inSize   int
+		expSizes []int
+	}{
+		{
+			name:     "empty",
+			expSizes: []int{},
+		},
+		{
+			name:     "single chunk",
+			inCount:  10,
+			inSize:   1,
+			expSizes: []int{10},
+		},
+		{
+			name:     "entry count limit",
+			inCount:  21,
+			inSize:   1,
+			expSizes: []int{10, 10, 1},
+		},
+		{
+			name:     "byte limit",
+			inCount:  5,
+			inSize:   100 << 10,
+			expSizes: []int{2, 2, 1},
+		},
+		{
+			name:     "oversized entries",
+			inCount:  2,
+			inSize:   amazon.MaxBatchBytes + 1,
+			expSizes: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := amazon.SplitBatch(newTestEntries(tt.inCount, tt.inSize))
+			sizes := make([]int, 0, len(chunks))
+			for _, chunk := range chunks {
+				sizes = append(sizes, len(chunk))
+			}
+			assert.Equal(t, tt.expSizes, sizes)
+		})
+	}
+}
+
+type fakeBatchService struct {
+	mu sync.Mutex
+	// failures count of requests each message is rejected on.
+	failures    map[string]int
+	senderFault map[string]bool
+	requests    [][]string
+}
+
+func (s *fakeBatchService) write(_ context.Context, entries []amazon.BatchEntry[string]) ([]amazon.MessageError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(entries))
+	failed := make([]amazon.MessageError, 0)
+	for _, entry := range entries {
+		ids = append(ids, entry.MessageID)
+		if s.failures[entry.MessageID] > 0 {
+			s.failures[entry.MessageID]--
+			failed = append(failed, amazon.MessageError{
+				MessageID:   entry.MessageID,
+				Code:        "InternalError",
+				Message:     "internal error",
+				SenderFault: s.senderFault[entry.MessageID],
+			})
+		}
+	}
+	s.requests = append(s.requests, ids)
+	return failed, nil
+}
+
+func TestWriteBatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		inCount       int
+		inFailures    map[string]int
+		inSenderFault map[string]bool
+		inRetries     int
+		expRequests   int
+		expFailedIDs  []string
+	}{
+		{
+			name:        "chunked",
+			inCount:     11,
+			expRequests: 2,
+		},
+		{
+			name:        "retried",
+			inCount:     11,
+			inFailures:  map[string]int{"3": 2, "10": 1},
+			expRequests: 5,
+		},
+		{
+			name:          "sender fault",
+			inCount:       3,
+			inFailures:    map[string]int{"1": 1},
+			inSenderFault: map[string]bool{"1": true},
+			expRequests:   1,
+			expFailedIDs:  []string{"1"},
+		},
+		{
+			name:         "retries exhausted",
+			inCount:      3,
+			inFailures:   map[string]int{"0": 5, "2": 1},
+			inRetries:    2,
+			expRequests:  3,
+			expFailedIDs: []string{"0"},
+		},
+		{
+			name:         "retries disabled",
+			inCount:      3,
+			inFailures:   map[string]int{"2": 1},
+			inRetries:    -1,
+			expRequests:  1,
+			expFailedIDs: []string{"2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeBatchService{
+				failures:    tt.inFailures,
+				senderFault: tt.inSenderFault,
+			}
+			err := amazon.WriteBatch(context.Background(), amazon.RetryConfig{
+				MaxRetries:   tt.inRetries,
+				RetryBackoff: time.Millisecond,
+			}, newTestEntries(tt.inCount, 1), false, svc.write)
+			assert.Len(t, svc.requests, tt.expRequests)
+			if len(tt.expFailedIDs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var errMsg amazon.MessageError
+			require.True(t, errors.As(err, &errMsg))
+			assert.Equal(t, tt.expFailedIDs[0], errMsg.MessageID)
+			assert.Equal(t, "InternalError", errMsg.Code)
+		})
+	}
+}
+
+func TestWriteBatch_Ordered(t *testing.T) {
+	svc := &fakeBatchService{}
+	err := amazon.WriteBatch(context.Background(), amazon.RetryConfig{}, newTestEntries(25, 1), true, svc.write)
+	require.NoError(t, err)
+	require.Len(t, svc.requests, 3)
+	assert.Equal(t, "0", svc.requests[0][0])
+	assert.Equal(t, "10", svc.requests[1][0])
+	assert.Equal(t, "20", svc.requests[2][0])
+}
+
+func TestWriteBatch_OrderedFailure(t *testing.T) {
+	svc := &fakeBatchService{
+		failures:    map[string]int{"12": 1},
+		senderFault: map[string]bool{"12": true},
+	}
+	err := amazon.WriteBatch(context.Background(), amazon.RetryConfig{}, newTestEntries(25, 1), true, svc.write)
+	require.Len(t, svc.requests, 2) // stops at the failing chunk
+
+	merr := &multierror.Error{}
+	require.True(t, errors.As(err, &merr))
+	require.Len(t, merr.Errors, 6)
+	var errMsg amazon.MessageError
+	require.True(t, errors.As(merr.Errors[0], &errMsg))
+	assert.Equal(t, "12", errMsg.MessageID)
+	assert.Equal(t, "InternalError", errMsg.Code)
+	for i, errUnsent := range merr.Errors[1:] {
+		require.True(t, errors.As(errUnsent, &errMsg))
+		assert.Equal(t, strconv.Itoa(20+i), errMsg.MessageID)
+		assert.Equal(t, amazon.UnsentErrorCode, errMsg.Code)
+	}
+}
+
+func TestWriteBatch_RequestFailure(t *testing.T) {
+	err := amazon.WriteBatch(context.Background(), amazon.RetryConfig{}, newTestEntries(1, 1), false,
+		func(_ context.Context, _ []amazon.BatchEntry[string]) ([]amazon.MessageError, error) {
+			return nil, errors.New("generic error")
+		})
+	assert.ErrorContains(t, err, "generic error")
+}
@@ -0,0 +1,64 @@
+package eventbridge
+
+import (
+	"strings"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// eventTimeSize size of the time field of an event entry, as calculated by Amazon EventBridge.
+//
+// Reference docs: https://docs.aws.amazon.com/eventbridge/latest/userguide/eb-putevent-size.html
+const eventTimeSize = 14
+
+func newEventEntry(cfg WriterConfig, stream string, msg streams.Message) (types.PutEventsRequestEntry, error) {
+	if !jsoniter.Valid(msg.Data) || !strings.HasPrefix(strings.TrimSpace(string(msg.Data)), "{") {
+		return types.PutEventsRequestEntry{}, ErrInvalidDetail
+	}
+
+	source := cfg.Source
+	if headerSource := msg.Headers[HeaderSource]; headerSource != "" {
+		source = headerSource
+	}
+	if source == "" {
+		return types.PutEventsRequestEntry{}, ErrMissingSource
+	}
+
+	entry := types.PutEventsRequestEntry{
+		Detail:       aws.String(string(msg.Data)),
+		DetailType:   aws.String(stream),
+		EventBusName: aws.String(cfg.EventBusName),
+		Source:       aws.String(source),
+	}
+	if cfg.DetailType != "" {
+		entry.DetailType = aws.String(cfg.DetailType)
+	}
+	if cfg.EventBusName == "" {
+		entry.EventBusName = aws.String(stream)
+	}
+	if !msg.Time.IsZero() {
+		entry.Time = aws.Time(msg.Time)
+	}
+	if resources := msg.Headers[HeaderResources]; resources != "" {
+		entry.Resources = strings.Split(resources, ",")
+	}
+	if traceHeader := msg.Headers[HeaderTraceHeader]; traceHeader != "" {
+		entry.TraceHeader = aws.String(traceHeader)
+	}
+	return entry, nil
+}
+
+// entrySize calculates the size of entry as Amazon EventBridge does to enforce PutEvents limits.
+func entrySize(entry types.PutEventsRequestEntry) int {
+	size := len(aws.ToString(entry.Source)) + len(aws.ToString(entry.DetailType)) + len(aws.ToString(entry.Detail))
+	if entry.Time != nil {
+		size += eventTimeSize
+	}
+	for _, resource := range entry.Resources {
+		size += len(resource)
+	}
+	return size
+}
@@ -0,0 +1,92 @@
+package eventbridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEventEntry(t *testing.T) {
+	msgTime := time.Now()
+	tests := []struct {
+		name   string
+		inCfg  WriterConfig
+		inMsg  streams.Message
+		exp    types.PutEventsRequestEntry
+		expErr error
+	}{
+		{
+			name:  "stream as event bus",
+			inCfg: WriterConfig{Source: "com.example.orders", DetailType: "OrderPlaced"},
+			inMsg: streams.Message{Data: []byte(`{"id":"1"}`)},
+			exp: types.PutEventsRequestEntry{
+				Detail:       aws.String(`{"id":"1"}`),
+				DetailType:   aws.String("OrderPlaced"),
+				EventBusName: aws.String("orders"),
+				Source:       aws.String("com.example.orders"),
+			},
+		},
+		{
+			name:  "stream as detail type",
+			inCfg: WriterConfig{Source: "com.example.orders", EventBusName: "domain-events"},
+			inMsg: streams.Message{
+				Headers: map[string]string{
+					HeaderSource:      "com.example.billing",
+					HeaderResources:   "arn:aws:s3:::foo,arn:aws:s3:::bar",
+					HeaderTraceHeader: "Root=1-5759e988-bd862e3fe1be46a994272793",
+					"foo":             "bar",
+				},
+				Data: []byte(`{"id":"1"}`),
+				Time: msgTime,
+			},
+			exp: types.PutEventsRequestEntry{
+				Detail:       aws.String(`{"id":"1"}`),
+				DetailType:   aws.String("orders"),
+				EventBusName: aws.String("domain-events"),
+				Resources:    []string{"arn:aws:s3:::foo", "arn:aws:s3:::bar"},
+				Source:       aws.String("com.example.billing"),
+				Time:         aws.Time(msgTime),
+				TraceHeader:  aws.String("Root=1-5759e988-bd862e3fe1be46a994272793"),
+			},
+		},
+		{
+			name:   "missing source",
+			inCfg:  WriterConfig{},
+			inMsg:  streams.Message{Data: []byte(`{"id":"1"}`)},
+			expErr: ErrMissingSource,
+		},
+		{
+			name:   "invalid detail",
+			inCfg:  WriterConfig{Source: "com.example.orders"},
+			inMsg:  streams.Message{Data: []byte(`"foo"`)},
+			expErr: ErrInvalidDetail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := newEventEntry(tt.inCfg, "orders", tt.inMsg)
+			assert.ErrorIs(t, err, tt.expErr)
+			assert.Equal(t, tt.exp, entry)
+		})
+	}
+}
+
+func TestEntrySize(t *testing.T) {
+	assert.Equal(t, 3+3+3, entrySize(types.PutEventsRequestEntry{
+		Detail:     aws.String("{a}"),
+		DetailType: aws.String("foo"),
+		Source:     aws.String("bar"),
+	}))
+	assert.Equal(t, 3+3+3+eventTimeSize+len("arn:foo"), entrySize(types.PutEventsRequestEntry{
+		Detail:     aws.String("{a}"),
+		DetailType: aws.String("foo"),
+		Source:     aws.String("bar"),
+		Time:       aws.Time(time.Now()),
+		Resources:  []string{"arn:foo"},
+	}))
+}
@@ -0,0 +1,12 @@
+package eventbridge
+
+// Amazon EventBridge events have no attributes, thus the following message headers are mapped to event fields.
+// Any other header is not written.
+const (
+	// HeaderSource Source of an event (e.g. com.example.orders), overriding WriterConfig.Source.
+	HeaderSource = "eventbridge-source"
+	// HeaderResources Comma-separated list of AWS resources (ARNs) an event primarily concerns.
+	HeaderResources = "eventbridge-resources"
+	// HeaderTraceHeader AWS X-Ray trace header of an event.
+	HeaderTraceHeader = "eventbridge-trace-header"
+)
@@ -0,0 +1,140 @@
+package eventbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/amazon"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Retryable PutEvents entry error codes. Any other code is caused by the entry itself.
+const (
+	internalFailureCode = "InternalFailure"
+	throttlingCode      = "ThrottlingException"
+)
+
+var (
+	// ErrInvalidDetail the message data is not a JSON object, thus it cannot be used as event detail.
+	ErrInvalidDetail = errors.New("streams.amazon.eventbridge: message data is not a JSON object")
+	// ErrMissingSource the message has no event source.
+	ErrMissingSource = errors.New("streams.amazon.eventbridge: missing event source")
+	// ErrEventTooLarge the event exceeds the PutEvents size limit.
+	ErrEventTooLarge = errors.New("streams.amazon.eventbridge: event exceeds maximum size")
+)
+
+// putEventsClient is the subset of eventbridge.Client used by Writer.
+type putEventsClient interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput,
+		optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// WriterConfig is the configuration schema for Amazon EventBridge streams.Writer implementation.
+type WriterConfig struct {
+	// Name or ARN of the event bus events are sent to. If empty, events are sent to the event bus named after
+	// Message.StreamName.
+	EventBusName string
+	// Detail type of events. If empty, Message.StreamName is used as detail type.
+	DetailType string
+	// Source of events (e.g. com.example.orders). Overridden by the HeaderSource message header.
+	Source string
+	// Retries of events rejected within PutEvents requests (e.g. throttled events).
+	amazon.RetryConfig
+}
+
+// eventEntry is an event entry of a batch request.
+type eventEntry = amazon.BatchEntry[types.PutEventsRequestEntry]
+
+// Writer is the Amazon EventBridge streams.Writer implementation.
+//
+// Message.Data is sent as event detail, thus it MUST be a JSON object. Message.Time and the headers HeaderSource,
+// HeaderResources and HeaderTraceHeader are mapped to their event fields. Batches are split to fit PutEvents
+// limits (10 entries, 256 KB) and entries rejected by EventBridge are retried (see amazon.WriteBatch). Invalid
+// messages (e.g. ErrInvalidDetail, ErrEventTooLarge) are reported as errors without preventing the rest of the batch
+// from being written.
+type Writer struct {
+	amazon.Writer
+	cfg    WriterConfig
+	client putEventsClient
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates an Amazon EventBridge concrete implementation of streams.Writer.
+func NewWriter(cfg WriterConfig, client *eventbridge.Client) Writer {
+	return newWriter(cfg, client)
+}
+
+func newWriter(cfg WriterConfig, client putEventsClient) Writer {
+	w := Writer{
+		cfg:    cfg,
+		client: client,
+	}
+	w.WriteFunc = w.write
+	return w
+}
+
+func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Message) error {
+	batchBuf := make([]eventEntry, 0, len(msgBatch))
+	errs := &multierror.Error{}
+	for _, msg := range msgBatch {
+		// invalid messages are skipped, so the rest of the batch is still written
+		entry, err := newEventEntry(w.cfg, stream, msg)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%w: message <%s>", err, msg.ID))
+			continue
+		}
+		size := entrySize(entry)
+		if size > amazon.MaxBatchBytes {
+			errs = multierror.Append(errs, fmt.Errorf("%w: message <%s>", ErrEventTooLarge, msg.ID))
+			continue
+		}
+		batchBuf = append(batchBuf, eventEntry{
+			MessageID: msg.ID,
+			Size:      size,
+			Entry:     entry,
+		})
+	}
+
+	if err := amazon.WriteBatch(ctx, w.cfg.RetryConfig, batchBuf, false, w.putEvents); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs.ErrorOrNil()
+}
+
+// putEvents sends entries in a single PutEvents call.
+func (w Writer) putEvents(ctx context.Context, entries []eventEntry) ([]amazon.MessageError, error) {
+	reqEntries := make([]types.PutEventsRequestEntry, len(entries))
+	for i, entry := range entries {
+		reqEntries[i] = entry.Entry
+	}
+	out, err := w.client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: reqEntries,
+	})
+	if err != nil {
+		return nil, err
+	} else if out.FailedEntryCount == 0 {
+		return nil, nil
+	}
+
+	// result entries keep the order of request entries
+	failed := make([]amazon.MessageError, 0, out.FailedEntryCount)
+	for i, entry := range out.Entries {
+		if entry.ErrorCode == nil {
+			continue
+		}
+		code := aws.ToString(entry.ErrorCode)
+		failed = append(failed, amazon.MessageError{
+			MessageID:   entries[i].MessageID,
+			Code:        code,
+			Message:     aws.ToString(entry.ErrorMessage),
+			SenderFault: code != internalFailureCode && code != throttlingCode,
+		})
+	}
+	return failed, nil
+}
@@ -0,0 +1,80 @@
+//go:build integration
+
+package eventbridge_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsevents "github.com/alexandria-oss/streams/driver/amazon/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/stretchr/testify/suite"
+)
+
+type writerSuite struct {
+	suite.Suite
+	client *eventbridge.Client
+	bus    string
+}
+
+func TestWriter(t *testing.T) {
+	suite.Run(t, &writerSuite{})
+}
+
+func (s *writerSuite) SetupSuite() {
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("fake", "fake", "")),
+		config.WithRegion("us-east-1"),
+		config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(
+			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+				return aws.Endpoint{
+					URL:           "http://localhost:4566",
+					PartitionID:   "aws",
+					SigningRegion: "us-east-1",
+				}, nil
+			})),
+	)
+	s.Require().NoError(err)
+	s.client = eventbridge.NewFromConfig(cfg)
+	s.bus = "alexandria-bus-write"
+	_, err = s.client.CreateEventBus(context.Background(), &eventbridge.CreateEventBusInput{
+		Name: aws.String(s.bus),
+	})
+	if err != nil && !strings.Contains(err.Error(), "ResourceAlreadyExistsException") {
+		s.Fail(err.Error())
+	}
+}
+
+func (s *writerSuite) TearDownSuite() {
+	_, err := s.client.DeleteEventBus(context.Background(), &eventbridge.DeleteEventBusInput{
+		Name: aws.String(s.bus),
+	})
+	s.Assert().NoError(err)
+}
+
+func (s *writerSuite) TestWrite() {
+	w := streamsevents.NewWriter(streamsevents.WriterConfig{
+		EventBusName: s.bus,
+		Source:       "com.example.orders",
+	}, s.client)
+	msgs := make([]streams.Message, 0, 15)
+	for i := 0; i < 15; i++ {
+		msgs = append(msgs, streams.Message{
+			ID:          strconv.Itoa(i),
+			StreamName:  "OrderPlaced",
+			StreamKey:   "test_route_key",
+			Headers:     map[string]string{streamsevents.HeaderResources: "arn:aws:s3:::foo"},
+			ContentType: "application/json",
+			Data:        []byte("{\"message\":\"foo example\"}"),
+			Time:        time.Now(),
+		})
+	}
+	s.Assert().NoError(w.Write(context.TODO(), msgs))
+}
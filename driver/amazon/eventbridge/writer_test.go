@@ -0,0 +1,212 @@
+package eventbridge
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/amazon"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePutEventsClient struct {
+	mu sync.Mutex
+	// failedDetails count of calls each event detail is rejected on.
+	failedDetails map[string]int
+	failedCode    string
+	callErr       error
+	calls         [][]types.PutEventsRequestEntry
+}
+
+var _ putEventsClient = &fakePutEventsClient{}
+
+func (c *fakePutEventsClient) PutEvents(_ context.Context, params *eventbridge.PutEventsInput,
+	_ ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, params.Entries)
+	if c.callErr != nil {
+		return nil, c.callErr
+	}
+	out := &eventbridge.PutEventsOutput{
+		Entries: make([]types.PutEventsResultEntry, 0, len(params.Entries)),
+	}
+	for i, entry := range params.Entries {
+		if c.failedDetails[aws.ToString(entry.Detail)] > 0 {
+			c.failedDetails[aws.ToString(entry.Detail)]--
+			out.FailedEntryCount++
+			out.Entries = append(out.Entries, types.PutEventsResultEntry{
+				ErrorCode:    aws.String(c.failedCode),
+				ErrorMessage: aws.String("entry failed"),
+			})
+			continue
+		}
+		out.Entries = append(out.Entries, types.PutEventsResultEntry{
+			EventId: aws.String(strconv.Itoa(i)),
+		})
+	}
+	return out, nil
+}
+
+func newTestMessages(count, detailSize int) []streams.Message {
+	msgs := make([]streams.Message, 0, count)
+	for i := 0; i < count; i++ {
+		detail := `{"id":"` + strconv.Itoa(i) + `","data":"` + strings.Repeat("a", detailSize) + `"}`
+		msgs = append(msgs, streams.Message{
+			ID:         strconv.Itoa(i),
+			StreamName: "orders",
+			Data:       []byte(detail),
+		})
+	}
+	return msgs
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name          string
+		inMsgs        []streams.Message
+		inFailedMsgs  []int
+		inFailures    int
+		inFailedCode  string
+		inRetries     int
+		inCallErr     error
+		expCalls      []int
+		expErrMessage []string
+		expErr        error
+	}{
+		{
+			name:     "single call",
+			inMsgs:   newTestMessages(3, 0),
+			expCalls: []int{3},
+		},
+		{
+			name:     "chunked by entry count",
+			inMsgs:   newTestMessages(21, 0),
+			expCalls: []int{10, 10, 1},
+		},
+		{
+			name:     "chunked by size",
+			inMsgs:   newTestMessages(5, 100<<10),
+			expCalls: []int{2, 2, 1},
+		},
+		{
+			name:          "failed entries",
+			inMsgs:        newTestMessages(12, 0),
+			inFailedMsgs:  []int{1, 11},
+			inRetries:     -1,
+			expCalls:      []int{10, 2},
+			expErrMessage: []string{"message <1> not written", "message <11> not written"},
+		},
+		{
+			name:         "retried entries",
+			inMsgs:       newTestMessages(12, 0),
+			inFailedMsgs: []int{1, 11},
+			inFailedCode: "ThrottlingException",
+			expCalls:     []int{10, 2, 1, 1},
+		},
+		{
+			name:          "retries exhausted",
+			inMsgs:        newTestMessages(3, 0),
+			inFailedMsgs:  []int{2},
+			inFailures:    5,
+			inRetries:     2,
+			expCalls:      []int{3, 1, 1},
+			expErrMessage: []string{"message <2> not written, InternalFailure"},
+		},
+		{
+			name:          "sender fault",
+			inMsgs:        newTestMessages(3, 0),
+			inFailedMsgs:  []int{0},
+			inFailedCode:  "MalformedDetail",
+			expCalls:      []int{3},
+			expErrMessage: []string{"message <0> not written, MalformedDetail"},
+		},
+		{
+			name:      "call failure",
+			inMsgs:    newTestMessages(1, 0),
+			inCallErr: errors.New("generic error"),
+			expCalls:  []int{1},
+			expErr:    errors.New("generic error"),
+		},
+		{
+			name:   "too large",
+			inMsgs: newTestMessages(1, 256<<10),
+			expErr: ErrEventTooLarge,
+		},
+		{
+			name: "too large within batch",
+			inMsgs: append(newTestMessages(2, 0), streams.Message{
+				ID:         "large",
+				StreamName: "orders",
+				Data:       []byte(`{"data":"` + strings.Repeat("a", 256<<10) + `"}`),
+			}),
+			inFailedMsgs:  []int{1},
+			inRetries:     -1,
+			expCalls:      []int{2},
+			expErr:        ErrEventTooLarge,
+			expErrMessage: []string{"message <1> not written", "message <large>"},
+		},
+		{
+			name: "invalid detail within batch",
+			inMsgs: append([]streams.Message{
+				{ID: "invalid", StreamName: "orders", Data: []byte("not a json object")},
+			}, newTestMessages(2, 0)...),
+			expCalls:      []int{2},
+			expErr:        ErrInvalidDetail,
+			expErrMessage: []string{"message <invalid>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakePutEventsClient{
+				failedDetails: make(map[string]int, len(tt.inFailedMsgs)),
+				failedCode:    tt.inFailedCode,
+				callErr:       tt.inCallErr,
+			}
+			if client.failedCode == "" {
+				client.failedCode = "InternalFailure"
+			}
+			failures := tt.inFailures
+			if failures == 0 {
+				failures = 1
+			}
+			for _, i := range tt.inFailedMsgs {
+				client.failedDetails[string(tt.inMsgs[i].Data)] = failures
+			}
+			w := newWriter(WriterConfig{
+				Source: "com.example.orders",
+				RetryConfig: amazon.RetryConfig{
+					MaxRetries:   tt.inRetries,
+					RetryBackoff: time.Millisecond,
+				},
+			}, client)
+			err := w.Write(context.Background(), tt.inMsgs)
+			switch {
+			case errors.Is(tt.expErr, ErrEventTooLarge) || errors.Is(tt.expErr, ErrInvalidDetail):
+				assert.ErrorIs(t, err, tt.expErr)
+			case tt.expErr != nil:
+				assert.ErrorContains(t, err, tt.expErr.Error())
+			case len(tt.expErrMessage) == 0:
+				assert.NoError(t, err)
+			}
+			for _, msg := range tt.expErrMessage {
+				assert.ErrorContains(t, err, msg)
+			}
+			// chunks are written concurrently
+			calls := make([]int, 0, len(client.calls))
+			for _, call := range client.calls {
+				calls = append(calls, len(call))
+			}
+			assert.ElementsMatch(t, tt.expCalls, calls)
+		})
+	}
+}
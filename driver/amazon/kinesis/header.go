@@ -0,0 +1,10 @@
+package kinesis
+
+const (
+	// HeaderShardID The identifier of the shard a record was read from.
+	HeaderShardID = "kinesis-shard-id"
+	// HeaderSequenceNumber The unique identifier of a record within its shard.
+	HeaderSequenceNumber = "kinesis-sequence-number"
+	// HeaderArrivalTime Timestamp in Unix milliseconds when a record was inserted into the stream.
+	HeaderArrivalTime = "kinesis-approximate-arrival-time"
+)
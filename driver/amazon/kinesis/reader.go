@@ -0,0 +1,391 @@
+package kinesis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/driver/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// readerClient is the subset of kinesis.Client used by Reader.
+type readerClient interface {
+	ListShards(ctx context.Context, params *kinesis.ListShardsInput,
+		optFns ...func(*kinesis.Options)) (*kinesis.ListShardsOutput, error)
+	GetShardIterator(ctx context.Context, params *kinesis.GetShardIteratorInput,
+		optFns ...func(*kinesis.Options)) (*kinesis.GetShardIteratorOutput, error)
+	GetRecords(ctx context.Context, params *kinesis.GetRecordsInput,
+		optFns ...func(*kinesis.Options)) (*kinesis.GetRecordsOutput, error)
+}
+
+// leaseStorage is the subset of dynamodb.LeaseStorage used by Reader.
+type leaseStorage interface {
+	CreateLease(ctx context.Context, lease dynamodb.Lease) error
+	ListLeases(ctx context.Context, stream string) ([]dynamodb.Lease, error)
+	AcquireLease(ctx context.Context, stream, shardID, owner string, expirationTime time.Time) error
+	Checkpoint(ctx context.Context, stream, shardID, owner, sequenceNumber string) error
+	EndShard(ctx context.Context, stream, shardID, owner string) error
+	ReleaseLease(ctx context.Context, stream, shardID, owner string) error
+}
+
+var _ leaseStorage = dynamodb.LeaseStorage{}
+
+// ReaderConfig is the Amazon Kinesis reader configuration schema.
+type ReaderConfig struct {
+	Logger      *log.Logger // Logging instance preferably with log level at <<info>>.
+	ErrorLogger *log.Logger // Logging instance preferably with log level at <<error>>.
+	Codec       codec.Codec // used to decode records into messages (default codec.ProtocolBuffers).
+	// Unique identifier of the reader, used as owner of shard leases (default a random KSUID).
+	WorkerID string
+	// Maximum count of records for each GetRecords call, up to 10,000 (default 100).
+	MaxRecords int32
+	// Total time a shard reader will wait between GetRecords calls (default 1s). Amazon Kinesis supports up to five
+	// GetRecords calls per shard each second, shared between every consumer.
+	PollInterval time.Duration
+	// Total time the reader will wait between shard discoveries and lease acquisitions (default 10s).
+	SyncInterval time.Duration
+	// Total time a lease is held by the reader if not renewed (default 30s). Leases are renewed while shards are
+	// read, including between handled records.
+	LeaseDuration time.Duration
+	// Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	HandlerTimeout time.Duration
+	// Position to start reading shards with no checkpoint from, either types.ShardIteratorTypeTrimHorizon or
+	// types.ShardIteratorTypeLatest (default types.ShardIteratorTypeTrimHorizon). Child shards of a resharding are
+	// always read from types.ShardIteratorTypeTrimHorizon.
+	InitialPosition types.ShardIteratorType
+}
+
+// Reader is the Amazon Kinesis Data Streams streams.Reader implementation.
+//
+// Shards are coordinated between readers using a lease table (dynamodb.LeaseStorage). Each reader periodically
+// discovers the stream shards, acquires available leases (i.e. unowned or expired) and reads each leased shard
+// concurrently, storing the sequence number of the last handled record as checkpoint. Leases are not rebalanced,
+// thus readers started later only acquire leases released or expired.
+//
+// Resharding is handled by reading child shards once every parent shard was read entirely, keeping the order of
+// messages sharing a key. Handler failures do not stop shard reading, wrap handlers with middleware functions
+// (e.g. retries, dead-letter queues) instead.
+type Reader struct {
+	cfg    ReaderConfig
+	client readerClient
+	leases leaseStorage
+}
+
+var _ streams.Reader = Reader{}
+
+// NewReader allocates an Amazon Kinesis Data Streams concrete implementation of streams.Reader.
+func NewReader(cfg ReaderConfig, client *kinesis.Client, leases dynamodb.LeaseStorage) Reader {
+	return newReader(cfg, client, leases)
+}
+
+func newReader(cfg ReaderConfig, client readerClient, leases leaseStorage) Reader {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.amazon.kinesis: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = codec.ProtocolBuffers{}
+	}
+	if cfg.WorkerID == "" {
+		cfg.WorkerID, _ = streams.NewKSUID()
+	}
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 100
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.SyncInterval <= 0 {
+		cfg.SyncInterval = time.Second * 10
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = time.Second * 30
+	}
+	if cfg.HandlerTimeout == 0 {
+		cfg.HandlerTimeout = time.Second * 30
+	}
+	if cfg.InitialPosition == "" {
+		cfg.InitialPosition = types.ShardIteratorTypeTrimHorizon
+	}
+	return Reader{
+		cfg:    cfg,
+		client: client,
+		leases: leases,
+	}
+}
+
+func (r Reader) Read(ctx context.Context, task streams.ReadTask) error {
+	mu := sync.Mutex{}
+	shardReaders := make(map[string]struct{}) // shards being read by this reader
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+
+	ticker := time.NewTicker(r.cfg.SyncInterval)
+	defer ticker.Stop()
+	for {
+		leases, err := r.syncLeases(ctx, task.Stream)
+		if err != nil && ctx.Err() == nil {
+			r.cfg.ErrorLogger.Printf("failed to synchronize shard leases of stream <%s>, %s", task.Stream, err.Error())
+		}
+
+		for _, lease := range readableLeases(leases, r.cfg.WorkerID, time.Now()) {
+			mu.Lock()
+			_, isReading := shardReaders[lease.ShardID]
+			mu.Unlock()
+			if isReading {
+				continue
+			}
+
+			err = r.leases.AcquireLease(ctx, lease.StreamName, lease.ShardID, r.cfg.WorkerID,
+				time.Now().Add(r.cfg.LeaseDuration))
+			if errors.Is(err, dynamodb.ErrLeaseNotAcquired) {
+				continue
+			} else if err != nil {
+				r.cfg.ErrorLogger.Printf("failed to acquire lease of shard <%s>, %s", lease.ShardID, err.Error())
+				continue
+			}
+
+			mu.Lock()
+			shardReaders[lease.ShardID] = struct{}{}
+			mu.Unlock()
+			wg.Add(1)
+			go func(leaseCopy dynamodb.Lease) {
+				defer wg.Done()
+				r.readShard(ctx, task, leaseCopy)
+				mu.Lock()
+				delete(shardReaders, leaseCopy.ShardID)
+				mu.Unlock()
+			}(lease)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.cfg.Logger.Printf("stopping stream reading process")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncLeases creates a lease for each shard of stream with no lease, then it retrieves every lease of stream.
+func (r Reader) syncLeases(ctx context.Context, stream string) ([]dynamodb.Lease, error) {
+	input := &kinesis.ListShardsInput{
+		StreamName: aws.String(stream),
+	}
+	for {
+		out, err := r.client.ListShards(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, shard := range out.Shards {
+			lease := dynamodb.Lease{
+				StreamName: stream,
+				ShardID:    aws.ToString(shard.ShardId),
+			}
+			if shard.ParentShardId != nil {
+				lease.ParentShardIDs = append(lease.ParentShardIDs, *shard.ParentShardId)
+			}
+			if shard.AdjacentParentShardId != nil {
+				lease.ParentShardIDs = append(lease.ParentShardIDs, *shard.AdjacentParentShardId)
+			}
+			if err = r.leases.CreateLease(ctx, lease); err != nil {
+				return nil, err
+			}
+		}
+		if out.NextToken == nil {
+			break
+		}
+		// stream names cannot be set along pagination tokens
+		input = &kinesis.ListShardsInput{
+			NextToken: out.NextToken,
+		}
+	}
+	return r.leases.ListLeases(ctx, stream)
+}
+
+// readableLeases retrieves leases available to owner whose parent shards were read entirely. Parent shards with no
+// lease (i.e. shards trimmed after the retention period) are considered read.
+func readableLeases(leases []dynamodb.Lease, owner string, t time.Time) []dynamodb.Lease {
+	shardEnded := make(map[string]bool, len(leases))
+	for _, lease := range leases {
+		shardEnded[lease.ShardID] = lease.IsShardEnded
+	}
+
+	buf := make([]dynamodb.Lease, 0, len(leases))
+	for _, lease := range leases {
+		if !lease.IsAvailable(owner, t) {
+			continue
+		}
+		isParentReading := false
+		for _, parentID := range lease.ParentShardIDs {
+			if ended, ok := shardEnded[parentID]; ok && !ended {
+				isParentReading = true
+				break
+			}
+		}
+		if !isParentReading {
+			buf = append(buf, lease)
+		}
+	}
+	return buf
+}
+
+func (r Reader) shardIterator(ctx context.Context, lease dynamodb.Lease) (*string, error) {
+	input := &kinesis.GetShardIteratorInput{
+		ShardId:           aws.String(lease.ShardID),
+		StreamName:        aws.String(lease.StreamName),
+		ShardIteratorType: r.cfg.InitialPosition,
+	}
+	if lease.Checkpoint != "" {
+		input.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		input.StartingSequenceNumber = aws.String(lease.Checkpoint)
+	} else if len(lease.ParentShardIDs) > 0 {
+		input.ShardIteratorType = types.ShardIteratorTypeTrimHorizon
+	}
+	out, err := r.client.GetShardIterator(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return out.ShardIterator, nil
+}
+
+// readShard handles records of the lease shard until ctx is done, the lease is lost or the shard was read entirely.
+func (r Reader) readShard(ctx context.Context, task streams.ReadTask, lease dynamodb.Lease) {
+	r.cfg.Logger.Printf("reading shard <%s>", lease.ShardID)
+	iterator, err := r.shardIterator(ctx, lease)
+	if err != nil {
+		r.cfg.ErrorLogger.Printf("failed to get iterator of shard <%s>, %s", lease.ShardID, err.Error())
+		r.releaseLease(lease)
+		return
+	}
+
+	renewTime := time.Now()
+	for {
+		out, errGet := r.client.GetRecords(ctx, &kinesis.GetRecordsInput{
+			ShardIterator: iterator,
+			Limit:         aws.Int32(r.cfg.MaxRecords),
+		})
+		var errExpired *types.ExpiredIteratorException
+		if ctx.Err() != nil {
+			r.releaseLease(lease)
+			return
+		} else if errors.As(errGet, &errExpired) {
+			if iterator, err = r.shardIterator(ctx, lease); err != nil {
+				r.cfg.ErrorLogger.Printf("failed to get iterator of shard <%s>, %s", lease.ShardID, err.Error())
+				r.releaseLease(lease)
+				return
+			}
+			continue
+		} else if errGet != nil {
+			r.cfg.ErrorLogger.Printf("failed to get records of shard <%s>, %s", lease.ShardID, errGet.Error())
+		} else {
+			if len(out.Records) > 0 {
+				for _, rec := range out.Records {
+					// handling a batch might outlive the lease, so it is renewed between records as well
+					if !r.renewLease(ctx, lease, &renewTime) {
+						return
+					}
+					r.handleRecord(ctx, task, lease.ShardID, rec)
+				}
+				lease.Checkpoint = aws.ToString(out.Records[len(out.Records)-1].SequenceNumber)
+				if err = r.checkpoint(lease); errors.Is(err, dynamodb.ErrLeaseLost) {
+					r.cfg.Logger.Printf("lost lease of shard <%s>", lease.ShardID)
+					return
+				}
+			}
+			if out.NextShardIterator == nil {
+				// shard was closed by a resharding, and every record was read
+				r.endShard(lease)
+				return
+			}
+			iterator = out.NextShardIterator
+		}
+
+		if !r.renewLease(ctx, lease, &renewTime) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			r.releaseLease(lease)
+			return
+		case <-time.After(r.cfg.PollInterval):
+		}
+	}
+}
+
+// renewLease renews the lease of the shard if a third of the lease duration elapsed since renewTime, updating it.
+// Returns false if the lease was acquired by another reader.
+func (r Reader) renewLease(ctx context.Context, lease dynamodb.Lease, renewTime *time.Time) bool {
+	if time.Since(*renewTime) < r.cfg.LeaseDuration/3 {
+		return true
+	}
+	err := r.leases.AcquireLease(ctx, lease.StreamName, lease.ShardID, r.cfg.WorkerID,
+		time.Now().Add(r.cfg.LeaseDuration))
+	if errors.Is(err, dynamodb.ErrLeaseNotAcquired) {
+		r.cfg.Logger.Printf("lost lease of shard <%s>", lease.ShardID)
+		return false
+	} else if err != nil {
+		r.cfg.ErrorLogger.Printf("failed to renew lease of shard <%s>, %s", lease.ShardID, err.Error())
+		return true
+	}
+	*renewTime = time.Now()
+	return true
+}
+
+func (r Reader) handleRecord(ctx context.Context, task streams.ReadTask, shardID string, rec types.Record) {
+	msg, err := unmarshalRecord(r.cfg.Codec, shardID, rec)
+	if err != nil {
+		r.cfg.ErrorLogger.Printf("failed to decode record <%s>, %s", aws.ToString(rec.SequenceNumber), err.Error())
+		return
+	}
+
+	scopedCtx, cancel := context.WithTimeout(ctx, r.cfg.HandlerTimeout)
+	defer cancel()
+	if err = task.Handler(scopedCtx, msg); err != nil {
+		// do nothing as developers are able to wrap message handler with middleware functions.
+		r.cfg.ErrorLogger.Printf("failed to handle message <%s>, %s", msg.ID, err.Error())
+	}
+}
+
+func (r Reader) checkpoint(lease dynamodb.Lease) error {
+	scopedCtx, cancel := context.WithTimeout(context.Background(), r.cfg.HandlerTimeout)
+	defer cancel()
+	err := r.leases.Checkpoint(scopedCtx, lease.StreamName, lease.ShardID, r.cfg.WorkerID, lease.Checkpoint)
+	if err != nil && !errors.Is(err, dynamodb.ErrLeaseLost) {
+		r.cfg.ErrorLogger.Printf("failed to checkpoint shard <%s>, %s", lease.ShardID, err.Error())
+	}
+	return err
+}
+
+func (r Reader) endShard(lease dynamodb.Lease) {
+	scopedCtx, cancel := context.WithTimeout(context.Background(), r.cfg.HandlerTimeout)
+	defer cancel()
+	if err := r.leases.EndShard(scopedCtx, lease.StreamName, lease.ShardID, r.cfg.WorkerID); err != nil {
+		r.cfg.ErrorLogger.Printf("failed to end shard <%s>, %s", lease.ShardID, err.Error())
+		return
+	}
+	r.cfg.Logger.Printf("shard <%s> was read entirely", lease.ShardID)
+}
+
+func (r Reader) releaseLease(lease dynamodb.Lease) {
+	scopedCtx, cancel := context.WithTimeout(context.Background(), r.cfg.HandlerTimeout)
+	defer cancel()
+	err := r.leases.ReleaseLease(scopedCtx, lease.StreamName, lease.ShardID, r.cfg.WorkerID)
+	if err != nil && !errors.Is(err, dynamodb.ErrLeaseLost) {
+		r.cfg.ErrorLogger.Printf("failed to release lease of shard <%s>, %s", lease.ShardID, err.Error())
+	}
+}
@@ -0,0 +1,156 @@
+//go:build integration
+
+package kinesis_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamskinesis "github.com/alexandria-oss/streams/driver/amazon/kinesis"
+	streamsdynamo "github.com/alexandria-oss/streams/driver/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamotypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/stretchr/testify/suite"
+)
+
+type readerSuite struct {
+	suite.Suite
+	client       *kinesis.Client
+	dynamoClient *dynamodb.Client
+
+	stream    string
+	tableName string
+}
+
+func TestReader(t *testing.T) {
+	suite.Run(t, &readerSuite{})
+}
+
+func (s *readerSuite) SetupSuite() {
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("fake", "fake", "")),
+		config.WithRegion("us-east-1"),
+		config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(
+			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+				return aws.Endpoint{
+					URL:           "http://localhost:4566",
+					PartitionID:   "aws",
+					SigningRegion: "us-east-1",
+				}, nil
+			})),
+	)
+	s.Require().NoError(err)
+	s.client = kinesis.NewFromConfig(cfg)
+	s.dynamoClient = dynamodb.NewFromConfig(cfg)
+	s.stream = "alexandria-stream-read"
+	s.tableName = "streams-kinesis-leases"
+
+	_, err = s.client.CreateStream(context.Background(), &kinesis.CreateStreamInput{
+		StreamName: aws.String(s.stream),
+		ShardCount: aws.Int32(2),
+	})
+	if err != nil && !strings.Contains(err.Error(), "ResourceInUseException") {
+		s.Fail(err.Error())
+	}
+	s.Require().NoError(kinesis.NewStreamExistsWaiter(s.client).Wait(context.Background(),
+		&kinesis.DescribeStreamInput{StreamName: aws.String(s.stream)}, time.Second*30))
+
+	_, err = s.dynamoClient.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		AttributeDefinitions: []dynamotypes.AttributeDefinition{
+			{
+				AttributeName: aws.String("stream_name"),
+				AttributeType: dynamotypes.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("shard_id"),
+				AttributeType: dynamotypes.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []dynamotypes.KeySchemaElement{
+			{
+				AttributeName: aws.String("stream_name"),
+				KeyType:       dynamotypes.KeyTypeHash,
+			},
+			{
+				AttributeName: aws.String("shard_id"),
+				KeyType:       dynamotypes.KeyTypeRange,
+			},
+		},
+		TableName:   aws.String(s.tableName),
+		BillingMode: dynamotypes.BillingModePayPerRequest,
+	})
+	if err != nil && !strings.Contains(err.Error(), "ResourceInUseException") {
+		s.Fail(err.Error())
+	}
+}
+
+func (s *readerSuite) TearDownSuite() {
+	_, err := s.client.DeleteStream(context.Background(), &kinesis.DeleteStreamInput{
+		StreamName: aws.String(s.stream),
+	})
+	s.Assert().NoError(err)
+	_, err = s.dynamoClient.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{
+		TableName: aws.String(s.tableName),
+	})
+	s.Assert().NoError(err)
+}
+
+func (s *readerSuite) TestRead() {
+	w := streamskinesis.NewWriter(streamskinesis.WriterConfig{}, s.client)
+	err := w.Write(context.TODO(), []streams.Message{
+		{
+			ID:          "123",
+			StreamName:  s.stream,
+			StreamKey:   "test_route_key",
+			Headers:     map[string]string{"test_header": "foo"},
+			ContentType: "application/json",
+			Data:        []byte("{\"message\":\"foo example\"}"),
+			Time:        time.Now(),
+		},
+		{
+			ID:          "456",
+			StreamName:  s.stream,
+			StreamKey:   "test_route_key",
+			ContentType: "application/json",
+			Data:        []byte("{\"message\":\"bar example\"}"),
+			Time:        time.Now(),
+		},
+	})
+	s.Require().NoError(err)
+
+	leases := streamsdynamo.NewLeaseStorage(streamsdynamo.LeaseStorageConfig{TableName: s.tableName}, s.dynamoClient)
+	r := streamskinesis.NewReader(streamskinesis.ReaderConfig{
+		WorkerID:     "worker-0",
+		PollInterval: time.Millisecond * 200,
+	}, s.client, leases)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	handled := make(chan streams.Message, 2)
+	go func() {
+		_ = r.Read(ctx, streams.ReadTask{
+			Stream: s.stream,
+			Handler: func(_ context.Context, msg streams.Message) error {
+				handled <- msg
+				return nil
+			},
+		})
+	}()
+
+	for _, expID := range []string{"123", "456"} {
+		select {
+		case msg := <-handled:
+			s.Assert().Equal(expID, msg.ID)
+			s.Assert().Equal("test_route_key", msg.StreamKey)
+			s.Assert().NotEmpty(msg.Headers[streamskinesis.HeaderSequenceNumber])
+		case <-ctx.Done():
+			s.FailNow("message was not handled")
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/driver/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeShard struct {
+	parents  []string
+	records  []types.Record
+	isClosed bool
+}
+
+// fakeReaderClient is an in-memory Amazon Kinesis stream. Shard iterators are formatted as <shard_id>/<offset>.
+type fakeReaderClient struct {
+	shards map[string]*fakeShard
+}
+
+var _ readerClient = fakeReaderClient{}
+
+func newFakeReaderClient(t *testing.T, shards map[string]*fakeShard, msgs map[string][]streams.Message) fakeReaderClient {
+	for shardID, shardMsgs := range msgs {
+		for i, msg := range shardMsgs {
+			entry, err := marshalRecord(codec.ProtocolBuffers{}, msg)
+			require.NoError(t, err)
+			shards[shardID].records = append(shards[shardID].records, types.Record{
+				Data:           entry.Data,
+				PartitionKey:   entry.PartitionKey,
+				SequenceNumber: aws.String(strconv.Itoa(i)),
+			})
+		}
+	}
+	return fakeReaderClient{shards: shards}
+}
+
+func (c fakeReaderClient) ListShards(_ context.Context, _ *kinesis.ListShardsInput,
+	_ ...func(*kinesis.Options)) (*kinesis.ListShardsOutput, error) {
+	out := &kinesis.ListShardsOutput{}
+	for shardID, shard := range c.shards {
+		kinesisShard := types.Shard{ShardId: aws.String(shardID)}
+		if len(shard.parents) > 0 {
+			kinesisShard.ParentShardId = aws.String(shard.parents[0])
+		}
+		if len(shard.parents) > 1 {
+			kinesisShard.AdjacentParentShardId = aws.String(shard.parents[1])
+		}
+		out.Shards = append(out.Shards, kinesisShard)
+	}
+	return out, nil
+}
+
+func (c fakeReaderClient) GetShardIterator(_ context.Context, params *kinesis.GetShardIteratorInput,
+	_ ...func(*kinesis.Options)) (*kinesis.GetShardIteratorOutput, error) {
+	shardID := aws.ToString(params.ShardId)
+	offset := 0
+	switch params.ShardIteratorType {
+	case types.ShardIteratorTypeLatest:
+		offset = len(c.shards[shardID].records)
+	case types.ShardIteratorTypeAfterSequenceNumber:
+		seq, _ := strconv.Atoi(aws.ToString(params.StartingSequenceNumber))
+		offset = seq + 1
+	}
+	return &kinesis.GetShardIteratorOutput{
+		ShardIterator: aws.String(shardID + "/" + strconv.Itoa(offset)),
+	}, nil
+}
+
+func (c fakeReaderClient) GetRecords(_ context.Context, params *kinesis.GetRecordsInput,
+	_ ...func(*kinesis.Options)) (*kinesis.GetRecordsOutput, error) {
+	shardID, offsetStr, _ := strings.Cut(aws.ToString(params.ShardIterator), "/")
+	offset, _ := strconv.Atoi(offsetStr)
+	shard := c.shards[shardID]
+	end := offset + int(aws.ToInt32(params.Limit))
+	if end > len(shard.records) {
+		end = len(shard.records)
+	}
+	out := &kinesis.GetRecordsOutput{
+		Records: shard.records[offset:end],
+	}
+	if !shard.isClosed || end < len(shard.records) {
+		out.NextShardIterator = aws.String(shardID + "/" + strconv.Itoa(end))
+	}
+	return out, nil
+}
+
+type fakeLeaseStorage struct {
+	mu     sync.Mutex
+	leases map[string]dynamodb.Lease
+}
+
+var _ leaseStorage = &fakeLeaseStorage{}
+
+func (s *fakeLeaseStorage) CreateLease(_ context.Context, lease dynamodb.Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.leases[lease.ShardID]; !ok {
+		s.leases[lease.ShardID] = lease
+	}
+	return nil
+}
+
+func (s *fakeLeaseStorage) ListLeases(_ context.Context, _ string) ([]dynamodb.Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buf := make([]dynamodb.Lease, 0, len(s.leases))
+	for _, lease := range s.leases {
+		buf = append(buf, lease)
+	}
+	return buf, nil
+}
+
+func (s *fakeLeaseStorage) AcquireLease(_ context.Context, _, shardID, owner string, expirationTime time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lease, ok := s.leases[shardID]
+	if !ok || !lease.IsAvailable(owner, time.Now()) {
+		return dynamodb.ErrLeaseNotAcquired
+	}
+	lease.Owner = owner
+	lease.ExpirationTime = expirationTime
+	s.leases[shardID] = lease
+	return nil
+}
+
+func (s *fakeLeaseStorage) update(shardID, owner string, updateFunc func(lease *dynamodb.Lease)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lease := s.leases[shardID]
+	if lease.Owner != owner {
+		return dynamodb.ErrLeaseLost
+	}
+	updateFunc(&lease)
+	s.leases[shardID] = lease
+	return nil
+}
+
+func (s *fakeLeaseStorage) Checkpoint(_ context.Context, _, shardID, owner, sequenceNumber string) error {
+	return s.update(shardID, owner, func(lease *dynamodb.Lease) {
+		lease.Checkpoint = sequenceNumber
+	})
+}
+
+func (s *fakeLeaseStorage) EndShard(_ context.Context, _, shardID, owner string) error {
+	return s.update(shardID, owner, func(lease *dynamodb.Lease) {
+		lease.Checkpoint = "SHARD_END"
+		lease.IsShardEnded = true
+		lease.Owner = ""
+	})
+}
+
+func (s *fakeLeaseStorage) ReleaseLease(_ context.Context, _, shardID, owner string) error {
+	return s.update(shardID, owner, func(lease *dynamodb.Lease) {
+		lease.Owner = ""
+	})
+}
+
+func (s *fakeLeaseStorage) get(shardID string) dynamodb.Lease {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leases[shardID]
+}
+
+func TestReader_Read(t *testing.T) {
+	// shard 0 was split into shards 1 and 2
+	client := newFakeReaderClient(t, map[string]*fakeShard{
+		"shard-0": {isClosed: true},
+		"shard-1": {parents: []string{"shard-0"}},
+		"shard-2": {parents: []string{"shard-0"}},
+	}, map[string][]streams.Message{
+		"shard-0": {
+			{ID: "1", StreamName: "foo", StreamKey: "key-a"},
+			{ID: "2", StreamName: "foo", StreamKey: "key-b"},
+			{ID: "3", StreamName: "foo", StreamKey: "key-a"},
+		},
+		"shard-1": {
+			{ID: "4", StreamName: "foo", StreamKey: "key-a"},
+		},
+		"shard-2": {
+			{ID: "5", StreamName: "foo", StreamKey: "key-b"},
+			{ID: "6", StreamName: "foo", StreamKey: "key-b"},
+		},
+	})
+	leases := &fakeLeaseStorage{leases: map[string]dynamodb.Lease{}}
+	r := newReader(ReaderConfig{
+		WorkerID:     "worker-a",
+		MaxRecords:   2,
+		PollInterval: time.Millisecond * 5,
+		SyncInterval: time.Millisecond * 20,
+	}, client, leases)
+
+	mu := sync.Mutex{}
+	handled := make(map[string][]string) // message ids by stream key
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.Read(ctx, streams.ReadTask{
+			Stream: "foo",
+			Handler: func(_ context.Context, msg streams.Message) error {
+				mu.Lock()
+				defer mu.Unlock()
+				handled[msg.StreamKey] = append(handled[msg.StreamKey], msg.ID)
+				if msg.ID == "2" {
+					return fmt.Errorf("generic error")
+				}
+				return nil
+			},
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled["key-a"])+len(handled["key-b"]) == 6
+	}, time.Second, time.Millisecond*10)
+	cancel()
+	require.NoError(t, <-errCh)
+
+	// child shards are read once the parent shard was read entirely
+	assert.Equal(t, []string{"1", "3", "4"}, handled["key-a"])
+	assert.Equal(t, []string{"2", "5", "6"}, handled["key-b"])
+	assert.True(t, leases.get("shard-0").IsShardEnded)
+	assert.Equal(t, "0", leases.get("shard-1").Checkpoint)
+	assert.Equal(t, "1", leases.get("shard-2").Checkpoint)
+	// leases are released once reading stops
+	assert.Empty(t, leases.get("shard-1").Owner)
+	assert.Empty(t, leases.get("shard-2").Owner)
+}
+
+func TestReader_ReadFromCheckpoint(t *testing.T) {
+	client := newFakeReaderClient(t, map[string]*fakeShard{
+		"shard-0": {},
+	}, map[string][]streams.Message{
+		"shard-0": {
+			{ID: "1", StreamName: "foo"},
+			{ID: "2", StreamName: "foo"},
+		},
+	})
+	leases := &fakeLeaseStorage{leases: map[string]dynamodb.Lease{
+		"shard-0": {StreamName: "foo", ShardID: "shard-0", Checkpoint: "0", Owner: "worker-b",
+			ExpirationTime: time.Now().Add(-time.Second)},
+	}}
+	r := newReader(ReaderConfig{
+		WorkerID:     "worker-a",
+		PollInterval: time.Millisecond * 5,
+	}, client, leases)
+
+	handled := make(chan string, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = r.Read(ctx, streams.ReadTask{
+			Stream: "foo",
+			Handler: func(_ context.Context, msg streams.Message) error {
+				handled <- msg.ID
+				return nil
+			},
+		})
+	}()
+
+	// expired leases are acquired, and shards are read after the checkpoint
+	select {
+	case id := <-handled:
+		assert.Equal(t, "2", id)
+	case <-time.After(time.Second):
+		t.Fatal("message was not handled")
+	}
+}
+
+func TestReadableLeases(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		leases []dynamodb.Lease
+		exp    []string
+	}{
+		{
+			name: "parent reading",
+			leases: []dynamodb.Lease{
+				{ShardID: "shard-0", Owner: "worker-a", ExpirationTime: now.Add(time.Minute)},
+				{ShardID: "shard-1", ParentShardIDs: []string{"shard-0"}},
+			},
+			exp: []string{"shard-0"},
+		},
+		{
+			name: "parent ended",
+			leases: []dynamodb.Lease{
+				{ShardID: "shard-0", IsShardEnded: true},
+				{ShardID: "shard-1", ParentShardIDs: []string{"shard-0"}},
+			},
+			exp: []string{"shard-1"},
+		},
+		{
+			name: "merge with parent reading",
+			leases: []dynamodb.Lease{
+				{ShardID: "shard-0", IsShardEnded: true},
+				{ShardID: "shard-1", Owner: "worker-b", ExpirationTime: now.Add(time.Minute)},
+				{ShardID: "shard-2", ParentShardIDs: []string{"shard-0", "shard-1"}},
+			},
+			exp: []string{},
+		},
+		{
+			name: "parent trimmed",
+			leases: []dynamodb.Lease{
+				{ShardID: "shard-1", ParentShardIDs: []string{"shard-0"}},
+			},
+			exp: []string{"shard-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leases := readableLeases(tt.leases, "worker-a", now)
+			ids := make([]string, 0, len(leases))
+			for _, lease := range leases {
+				ids = append(ids, lease.ShardID)
+			}
+			assert.Equal(t, tt.exp, ids)
+		})
+	}
+}
+
+func TestReader_RenewLeaseWhileHandling(t *testing.T) {
+	tests := []struct {
+		name       string
+		inStealID  string // message id whose handler makes another reader acquire the lease
+		expHandled []string
+	}{
+		{
+			name:       "renewed",
+			expHandled: []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			name:       "lost",
+			inStealID:  "2",
+			expHandled: []string{"1", "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeReaderClient(t, map[string]*fakeShard{
+				"shard-0": {},
+			}, map[string][]streams.Message{
+				"shard-0": {
+					{ID: "1", StreamName: "foo"},
+					{ID: "2", StreamName: "foo"},
+					{ID: "3", StreamName: "foo"},
+					{ID: "4", StreamName: "foo"},
+					{ID: "5", StreamName: "foo"},
+				},
+			})
+			leases := &fakeLeaseStorage{leases: map[string]dynamodb.Lease{}}
+			// handling the whole batch outlives the lease
+			r := newReader(ReaderConfig{
+				WorkerID:      "worker-a",
+				MaxRecords:    10,
+				PollInterval:  time.Millisecond * 5,
+				SyncInterval:  time.Millisecond * 20,
+				LeaseDuration: time.Millisecond * 60,
+			}, client, leases)
+
+			mu := sync.Mutex{}
+			handled := make([]string, 0)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- r.Read(ctx, streams.ReadTask{
+					Stream: "foo",
+					Handler: func(_ context.Context, msg streams.Message) error {
+						lease := leases.get("shard-0")
+						assert.Equal(t, "worker-a", lease.Owner)
+						assert.True(t, lease.ExpirationTime.After(time.Now()), "lease expired while handling")
+						mu.Lock()
+						handled = append(handled, msg.ID)
+						mu.Unlock()
+						if msg.ID == tt.inStealID {
+							assert.NoError(t, leases.update("shard-0", "worker-a", func(lease *dynamodb.Lease) {
+								lease.Owner = "worker-b"
+								lease.ExpirationTime = time.Now().Add(time.Minute)
+							}))
+						}
+						time.Sleep(time.Millisecond * 30)
+						return nil
+					},
+				})
+			}()
+
+			assert.Eventually(t, func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return len(handled) == len(tt.expHandled)
+			}, time.Second, time.Millisecond*10)
+			time.Sleep(time.Millisecond * 100) // records after a lost lease must not be handled
+			cancel()
+			require.NoError(t, <-errCh)
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.expHandled, handled)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package kinesis
+
+import (
+	"strconv"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// Amazon Kinesis records have no attributes, thus messages are encoded as persistence.TransportMessage(s) to keep
+// their fields and headers.
+
+func marshalRecord(c codec.Codec, msg streams.Message) (types.PutRecordsRequestEntry, error) {
+	data, err := c.Encode(persistence.NewTransportMessage(msg))
+	if err != nil {
+		return types.PutRecordsRequestEntry{}, err
+	}
+	// partition keys are required, so messages with no key are distributed between shards
+	partitionKey := msg.StreamKey
+	if partitionKey == "" {
+		partitionKey = msg.ID
+	}
+	return types.PutRecordsRequestEntry{
+		Data:         data,
+		PartitionKey: aws.String(partitionKey),
+	}, nil
+}
+
+func unmarshalRecord(c codec.Codec, shardID string, rec types.Record) (streams.Message, error) {
+	transportMsg := &persistence.TransportMessage{}
+	if err := c.Decode(rec.Data, transportMsg); err != nil {
+		return streams.Message{}, err
+	}
+	msg := persistence.NewMessage(transportMsg)
+	if msg.Headers == nil {
+		msg.Headers = make(map[string]string, 3)
+	}
+	msg.Headers[HeaderShardID] = shardID
+	msg.Headers[HeaderSequenceNumber] = aws.ToString(rec.SequenceNumber)
+	if rec.ApproximateArrivalTimestamp != nil {
+		msg.Headers[HeaderArrivalTime] = strconv.FormatInt(rec.ApproximateArrivalTimestamp.UnixMilli(), 10)
+	}
+	return msg, nil
+}
@@ -0,0 +1,153 @@
+package kinesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/driver/amazon"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/hashicorp/go-multierror"
+)
+
+// PutRecords limits.
+const (
+	maxPutRecordsEntries = 500
+	maxPutRecordsBytes   = 5 << 20
+)
+
+// ErrRecordNotWritten a record was rejected by Amazon Kinesis once every retry was exhausted.
+var ErrRecordNotWritten = errors.New("streams.amazon.kinesis: record not written")
+
+// putRecordsClient is the subset of kinesis.Client used by Writer.
+type putRecordsClient interface {
+	PutRecords(ctx context.Context, params *kinesis.PutRecordsInput,
+		optFns ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error)
+}
+
+// WriterConfig is the configuration schema for Amazon Kinesis streams.Writer implementation.
+type WriterConfig struct {
+	Codec codec.Codec // used to encode messages into records (default codec.ProtocolBuffers).
+	// Maximum count of retries for records rejected by Amazon Kinesis (e.g. throttled records). Disabled if < 0
+	// (default 3).
+	MaxRetries int
+	// Time duration to wait before the first retry, doubled on each retry (default 100ms).
+	RetryBackoff time.Duration
+}
+
+// Writer is the Amazon Kinesis Data Streams streams.Writer implementation.
+//
+// Messages are written to the stream named after Message.StreamName using Message.StreamKey as partition key
+// (or Message.ID if empty), so messages sharing a key are stored in order within the same shard. Batches are
+// split to fit PutRecords limits and written sequentially. Partially failed PutRecords calls are retried with
+// the rejected records only; thus, retried records may be stored after records written later within the batch.
+type Writer struct {
+	amazon.Writer
+	cfg    WriterConfig
+	client putRecordsClient
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates an Amazon Kinesis Data Streams concrete implementation of streams.Writer.
+func NewWriter(cfg WriterConfig, client *kinesis.Client) Writer {
+	return newWriter(cfg, client)
+}
+
+func newWriter(cfg WriterConfig, client putRecordsClient) Writer {
+	if cfg.Codec == nil {
+		cfg.Codec = codec.ProtocolBuffers{}
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Millisecond * 100
+	}
+	w := Writer{
+		cfg:    cfg,
+		client: client,
+	}
+	w.WriteFunc = w.write
+	return w
+}
+
+func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Message) error {
+	entries := make([]types.PutRecordsRequestEntry, 0, len(msgBatch))
+	msgIDs := make([]string, 0, len(msgBatch))
+	errs := &multierror.Error{}
+	chunkBytes := 0
+	for _, msg := range msgBatch {
+		entry, err := marshalRecord(w.cfg.Codec, msg)
+		if err != nil {
+			return err
+		}
+
+		entryBytes := len(entry.Data) + len(*entry.PartitionKey)
+		if len(entries) == maxPutRecordsEntries || (len(entries) > 0 && chunkBytes+entryBytes > maxPutRecordsBytes) {
+			if err = w.putRecords(ctx, stream, entries, msgIDs); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+			entries = entries[:0]
+			msgIDs = msgIDs[:0]
+			chunkBytes = 0
+		}
+		entries = append(entries, entry)
+		msgIDs = append(msgIDs, msg.ID)
+		chunkBytes += entryBytes
+	}
+	if len(entries) > 0 {
+		if err := w.putRecords(ctx, stream, entries, msgIDs); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// putRecords writes entries, retrying rejected entries with an exponential backoff. msgIDs holds the message
+// identifier of each entry.
+func (w Writer) putRecords(ctx context.Context, stream string, entries []types.PutRecordsRequestEntry,
+	msgIDs []string) error {
+	backoff := w.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		out, err := w.client.PutRecords(ctx, &kinesis.PutRecordsInput{
+			Records:    entries,
+			StreamName: aws.String(stream),
+		})
+		if err != nil {
+			return err
+		} else if aws.ToInt32(out.FailedRecordCount) == 0 {
+			return nil
+		}
+
+		failedEntries := make([]types.PutRecordsRequestEntry, 0, aws.ToInt32(out.FailedRecordCount))
+		failedIDs := make([]string, 0, aws.ToInt32(out.FailedRecordCount))
+		errs := &multierror.Error{}
+		for i, rec := range out.Records {
+			if rec.ErrorCode == nil {
+				continue
+			}
+			failedEntries = append(failedEntries, entries[i])
+			failedIDs = append(failedIDs, msgIDs[i])
+			errs = multierror.Append(errs, fmt.Errorf("%w: message <%s>, %s: %s", ErrRecordNotWritten, msgIDs[i],
+				aws.ToString(rec.ErrorCode), aws.ToString(rec.ErrorMessage)))
+		}
+		if attempt >= w.cfg.MaxRetries {
+			return errs.ErrorOrNil()
+		}
+
+		select {
+		case <-ctx.Done():
+			return multierror.Append(errs, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		entries = failedEntries
+		msgIDs = failedIDs
+	}
+}
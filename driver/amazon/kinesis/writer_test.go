@@ -0,0 +1,163 @@
+package kinesis
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePutRecordsClient struct {
+	mu sync.Mutex
+	// failures count of PutRecords calls each partition key is rejected on.
+	failures map[string]int
+	calls    [][]string // partition keys of each call
+}
+
+var _ putRecordsClient = &fakePutRecordsClient{}
+
+func (c *fakePutRecordsClient) PutRecords(_ context.Context, params *kinesis.PutRecordsInput,
+	_ ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(params.Records))
+	out := &kinesis.PutRecordsOutput{
+		FailedRecordCount: aws.Int32(0),
+		Records:           make([]types.PutRecordsResultEntry, 0, len(params.Records)),
+	}
+	for _, rec := range params.Records {
+		key := aws.ToString(rec.PartitionKey)
+		keys = append(keys, key)
+		if c.failures[key] > 0 {
+			c.failures[key]--
+			*out.FailedRecordCount++
+			out.Records = append(out.Records, types.PutRecordsResultEntry{
+				ErrorCode:    aws.String("ProvisionedThroughputExceededException"),
+				ErrorMessage: aws.String("Rate exceeded for shard"),
+			})
+			continue
+		}
+		out.Records = append(out.Records, types.PutRecordsResultEntry{
+			SequenceNumber: aws.String(strconv.Itoa(len(c.calls))),
+			ShardId:        aws.String("shardId-000000000000"),
+		})
+	}
+	c.calls = append(c.calls, keys)
+	return out, nil
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		inMsgCount int
+		inFailures map[string]int
+		inRetries  int
+		expCalls   []int
+		expErr     bool
+	}{
+		{
+			name:       "single call",
+			inMsgCount: 3,
+			expCalls:   []int{3},
+		},
+		{
+			name:       "chunked",
+			inMsgCount: maxPutRecordsEntries + 1,
+			expCalls:   []int{maxPutRecordsEntries, 1},
+		},
+		{
+			name:       "retried",
+			inMsgCount: 3,
+			inFailures: map[string]int{"key-1": 2},
+			expCalls:   []int{3, 1, 1},
+		},
+		{
+			name:       "retries exhausted",
+			inMsgCount: 3,
+			inFailures: map[string]int{"key-1": 5, "key-2": 5},
+			inRetries:  1,
+			expCalls:   []int{3, 2},
+			expErr:     true,
+		},
+		{
+			name:       "retries disabled",
+			inMsgCount: 3,
+			inFailures: map[string]int{"key-1": 1},
+			inRetries:  -1,
+			expCalls:   []int{3},
+			expErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakePutRecordsClient{failures: tt.inFailures}
+			w := newWriter(WriterConfig{
+				MaxRetries:   tt.inRetries,
+				RetryBackoff: time.Millisecond,
+			}, client)
+			msgs := make([]streams.Message, 0, tt.inMsgCount)
+			for i := 0; i < tt.inMsgCount; i++ {
+				msgs = append(msgs, streams.Message{
+					ID:         strconv.Itoa(i),
+					StreamName: "foo",
+					StreamKey:  "key-" + strconv.Itoa(i),
+					Data:       []byte("bar"),
+				})
+			}
+			err := w.Write(context.Background(), msgs)
+			assert.Equal(t, tt.expErr, err != nil)
+			if tt.expErr {
+				assert.ErrorIs(t, err, ErrRecordNotWritten)
+			}
+			require.Len(t, client.calls, len(tt.expCalls))
+			for i, count := range tt.expCalls {
+				assert.Len(t, client.calls[i], count)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	w := newWriter(WriterConfig{}, nil)
+	msgTime := time.Now().UTC()
+	entry, err := marshalRecord(w.cfg.Codec, streams.Message{
+		ID:          "1",
+		StreamName:  "foo",
+		ContentType: "text/plain",
+		Headers:     map[string]string{"foo": "bar"},
+		Data:        []byte("baz"),
+		Time:        msgTime,
+	})
+	require.NoError(t, err)
+	// messages with no key use their identifier as partition key
+	assert.Equal(t, "1", aws.ToString(entry.PartitionKey))
+
+	msg, err := unmarshalRecord(w.cfg.Codec, "shardId-000000000000", types.Record{
+		Data:                        entry.Data,
+		PartitionKey:                entry.PartitionKey,
+		SequenceNumber:              aws.String("10"),
+		ApproximateArrivalTimestamp: aws.Time(time.UnixMilli(1000)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, "foo", msg.StreamName)
+	assert.Equal(t, "", msg.StreamKey)
+	assert.Equal(t, "text/plain", msg.ContentType)
+	assert.Equal(t, []byte("baz"), msg.Data)
+	assert.True(t, msgTime.Equal(msg.Time))
+	assert.Equal(t, map[string]string{
+		"foo":                "bar",
+		HeaderShardID:        "shardId-000000000000",
+		HeaderSequenceNumber: "10",
+		HeaderArrivalTime:    "1000",
+	}, msg.Headers)
+}
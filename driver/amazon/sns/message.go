@@ -52,3 +52,13 @@ func newMessageAttributeMap(msg streams.Message) map[string]types.MessageAttribu
 	}
 	return buf
 }
+
+// messageAttributeMapSize calculates the size of attributes as Amazon SNS does to enforce message size limits
+// (i.e. name, data type and value of each attribute).
+func messageAttributeMapSize(attributes map[string]types.MessageAttributeValue) int {
+	size := 0
+	for name, attr := range attributes {
+		size += len(name) + len(aws.ToString(attr.DataType)) + len(aws.ToString(attr.StringValue)) + len(attr.BinaryValue)
+	}
+	return size
+}
@@ -2,7 +2,6 @@ package sns
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
@@ -11,22 +10,44 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sns/types"
-	"github.com/hashicorp/go-multierror"
 	jsoniter "github.com/json-iterator/go"
 )
 
+// WriterConfig is the configuration schema for Amazon SNS streams.Writer implementation.
+type WriterConfig struct {
+	amazon.Config
+	// Retries of messages rejected within PublishBatch requests (e.g. throttled messages).
+	amazon.RetryConfig
+}
+
+// publishEntry is a message entry of a batch request.
+type publishEntry = amazon.BatchEntry[types.PublishBatchRequestEntry]
+
 // Writer is the Amazon Simple Notification Service (SNS) streams.Writer implementation.
+//
+// Batches are split to fit PublishBatch limits (10 entries, 256 KB) and published concurrently, unless the topic is
+// FIFO (.fifo suffix) so they are published sequentially. Messages rejected once every retry was exhausted are
+// returned as amazon.MessageError(s).
 type Writer struct {
 	amazon.Writer
-	config  amazon.Config
+	config  WriterConfig
 	client  *sns.Client
 	baseARN string
 }
 
 var _ streams.Writer = Writer{}
 
-// NewWriter allocates an Amazon Simple Notification Service (SNS) concrete implementation of streams.Writer.
+// NewWriter allocates an Amazon Simple Notification Service (SNS) concrete implementation of streams.Writer with
+// default retries.
 func NewWriter(cfg amazon.Config, client *sns.Client) Writer {
+	return NewWriterWithConfig(WriterConfig{
+		Config: cfg,
+	}, client)
+}
+
+// NewWriterWithConfig allocates an Amazon Simple Notification Service (SNS) concrete implementation of
+// streams.Writer with a specific WriterConfig.
+func NewWriterWithConfig(cfg WriterConfig, client *sns.Client) Writer {
 	w := Writer{
 		config:  cfg,
 		client:  client,
@@ -38,7 +59,7 @@ func NewWriter(cfg amazon.Config, client *sns.Client) Writer {
 
 func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Message) error {
 	isTopicFIFO := strings.HasSuffix(stream, ".fifo")
-	batchBuf := make([]types.PublishBatchRequestEntry, len(msgBatch))
+	batchBuf := make([]publishEntry, len(msgBatch))
 	for i, msg := range msgBatch {
 		msgStr := string(msg.Data)
 		msgJSON, err := jsoniter.Marshal(message{
@@ -68,22 +89,37 @@ func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Mes
 			entry.MessageGroupId = msgKey
 		}
 
-		batchBuf[i] = entry
-	}
-
-	topicARN := newTopic(w.baseARN, stream)
-	out, err := w.client.PublishBatch(ctx, &sns.PublishBatchInput{
-		PublishBatchRequestEntries: batchBuf,
-		TopicArn:                   aws.String(topicARN),
-	})
-	if err != nil {
-		return err
-	} else if len(out.Failed) > 0 {
-		errs := &multierror.Error{}
-		for _, fail := range out.Failed {
-			errs = multierror.Append(errs, errors.New(*fail.Message))
+		batchBuf[i] = publishEntry{
+			MessageID: msg.ID,
+			Size:      len(msgJSON) + len(msg.StreamKey) + messageAttributeMapSize(entry.MessageAttributes),
+			Entry:     entry,
 		}
-		return errs.ErrorOrNil()
 	}
-	return nil
+
+	topicARN := aws.String(newTopic(w.baseARN, stream))
+	return amazon.WriteBatch(ctx, w.config.RetryConfig, batchBuf, isTopicFIFO,
+		func(ctx context.Context, entries []publishEntry) ([]amazon.MessageError, error) {
+			reqEntries := make([]types.PublishBatchRequestEntry, len(entries))
+			for i, entry := range entries {
+				reqEntries[i] = entry.Entry
+			}
+			out, err := w.client.PublishBatch(ctx, &sns.PublishBatchInput{
+				PublishBatchRequestEntries: reqEntries,
+				TopicArn:                   topicARN,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			failed := make([]amazon.MessageError, 0, len(out.Failed))
+			for _, fail := range out.Failed {
+				failed = append(failed, amazon.MessageError{
+					MessageID:   aws.ToString(fail.Id),
+					Code:        aws.ToString(fail.Code),
+					Message:     aws.ToString(fail.Message),
+					SenderFault: fail.SenderFault,
+				})
+			}
+			return failed, nil
+		})
 }
@@ -4,6 +4,7 @@ package sns_test
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -103,3 +104,23 @@ func (s *writerSuite) TestWrite() {
 	})
 	s.Assert().NoError(err)
 }
+
+func (s *writerSuite) TestWriteChunked() {
+	w := streamsns.NewWriterWithConfig(streamsns.WriterConfig{
+		Config: amazon.Config{
+			AccountID: s.accountID,
+			Region:    "us-east-1",
+		},
+	}, s.client)
+	msgs := make([]streams.Message, 0, 11)
+	for i := 0; i < 11; i++ {
+		msgs = append(msgs, streams.Message{
+			ID:          strconv.Itoa(i),
+			StreamName:  s.stream,
+			StreamKey:   "test_route_key",
+			ContentType: "application/json",
+			Data:        []byte("{\"message\":\"foo example\"}"),
+		})
+	}
+	s.Assert().NoError(w.Write(context.TODO(), msgs))
+}
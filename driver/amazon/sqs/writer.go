@@ -2,7 +2,6 @@ package sqs
 
 import (
 	"context"
-	"errors"
 	"strings"
 
 	"github.com/alexandria-oss/streams"
@@ -10,7 +9,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
-	"github.com/hashicorp/go-multierror"
 )
 
 // WriterConfig is the configuration schema for Amazon SQS streams.Writer implementation.
@@ -23,9 +21,18 @@ type WriterConfig struct {
 	// FifoQueue , you can't set DelaySeconds per message. You can set this parameter
 	// only on a queue level.
 	DelaySeconds int32
+	// Retries of messages rejected within SendMessageBatch requests (e.g. throttled messages).
+	amazon.RetryConfig
 }
 
+// sendMessageEntry is a message entry of a batch request.
+type sendMessageEntry = amazon.BatchEntry[types.SendMessageBatchRequestEntry]
+
 // Writer is the Amazon Simple Queue Service (SQS) streams.Writer implementation.
+//
+// Batches are split to fit SendMessageBatch limits (10 entries, 256 KB) and sent concurrently, unless the queue is
+// FIFO (.fifo suffix) so they are sent sequentially. Messages rejected once every retry was exhausted are returned as
+// amazon.MessageError(s).
 type Writer struct {
 	amazon.Writer
 	config WriterConfig
@@ -49,8 +56,8 @@ func NewWriter(cfg WriterConfig, awsCfg aws.Config, client *sqs.Client) Writer {
 
 func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Message) error {
 	isQueueFIFO := strings.HasSuffix(stream, ".fifo")
-	queueURL := newQueueURL(w.baseQueueURL, stream)
-	batchBuf := make([]types.SendMessageBatchRequestEntry, len(msgBatch))
+	queueURL := aws.String(newQueueURL(w.baseQueueURL, stream))
+	batchBuf := make([]sendMessageEntry, len(msgBatch))
 	for i, msg := range msgBatch {
 		msgID := aws.String(msg.ID)
 		entry := types.SendMessageBatchRequestEntry{
@@ -66,21 +73,36 @@ func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Mes
 			entry.MessageDeduplicationId = msgID
 			entry.MessageGroupId = aws.String(msg.StreamKey)
 		}
-		batchBuf[i] = entry
-	}
-
-	out, err := w.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
-		Entries:  batchBuf,
-		QueueUrl: aws.String(queueURL),
-	})
-	if err != nil {
-		return err
-	} else if len(out.Failed) > 0 {
-		errs := &multierror.Error{}
-		for _, fail := range out.Failed {
-			errs = multierror.Append(errs, errors.New(*fail.Message))
+		batchBuf[i] = sendMessageEntry{
+			MessageID: msg.ID,
+			Size:      len(msg.Data) + messageAttributeMapSize(entry.MessageAttributes),
+			Entry:     entry,
 		}
-		return errs.ErrorOrNil()
 	}
-	return nil
+
+	return amazon.WriteBatch(ctx, w.config.RetryConfig, batchBuf, isQueueFIFO,
+		func(ctx context.Context, entries []sendMessageEntry) ([]amazon.MessageError, error) {
+			reqEntries := make([]types.SendMessageBatchRequestEntry, len(entries))
+			for i, entry := range entries {
+				reqEntries[i] = entry.Entry
+			}
+			out, err := w.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+				Entries:  reqEntries,
+				QueueUrl: queueURL,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			failed := make([]amazon.MessageError, 0, len(out.Failed))
+			for _, fail := range out.Failed {
+				failed = append(failed, amazon.MessageError{
+					MessageID:   aws.ToString(fail.Id),
+					Code:        aws.ToString(fail.Code),
+					Message:     aws.ToString(fail.Message),
+					SenderFault: fail.SenderFault,
+				})
+			}
+			return failed, nil
+		})
 }
@@ -4,6 +4,7 @@ package sqs_test
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -108,3 +109,23 @@ func (s *writerSuite) TestWrite() {
 	})
 	s.Assert().NoError(err)
 }
+
+func (s *writerSuite) TestWriteChunked() {
+	w := streamsqs.NewWriter(streamsqs.WriterConfig{
+		Config: amazon.Config{
+			AccountID: s.accountID,
+			Region:    "us-east-1",
+		},
+	}, s.awsCfg, s.client)
+	msgs := make([]streams.Message, 0, 11)
+	for i := 0; i < 11; i++ {
+		msgs = append(msgs, streams.Message{
+			ID:          strconv.Itoa(i),
+			StreamName:  s.stream,
+			StreamKey:   "test_route_key",
+			ContentType: "application/json",
+			Data:        []byte("{\"message\":\"foo example\"}"),
+		})
+	}
+	s.Assert().NoError(w.Write(context.TODO(), msgs))
+}
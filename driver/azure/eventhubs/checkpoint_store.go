@@ -0,0 +1,101 @@
+package eventhubs
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+)
+
+// A MemoryCheckpointStore is an in-memory azeventhubs.CheckpointStore implementation. Checkpoints and partition
+// ownerships are lost once the process exits, so it is only suitable for a single reader process (or tests).
+//
+// Use a durable store (e.g. the Azure Blob Storage store from the azeventhubs/checkpoints package) to share
+// partitions and checkpoints between reader processes.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	etagSeq     int64
+	checkpoints map[string]azeventhubs.Checkpoint
+	ownerships  map[string]azeventhubs.Ownership
+}
+
+var _ azeventhubs.CheckpointStore = &MemoryCheckpointStore{}
+
+// NewMemoryCheckpointStore allocates a MemoryCheckpointStore instance.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]azeventhubs.Checkpoint),
+		ownerships:  make(map[string]azeventhubs.Ownership),
+	}
+}
+
+func newPartitionStoreKey(namespace, eventHub, consumerGroup, partitionID string) string {
+	return namespace + "/" + eventHub + "/" + consumerGroup + "/" + partitionID
+}
+
+// ClaimOwnership claims the partitions in partitionOwnership whose ETag matches the stored ownership ETag (i.e. the
+// ownership was not updated by another processor meanwhile), returning the claimed partitions.
+func (s *MemoryCheckpointStore) ClaimOwnership(_ context.Context, partitionOwnership []azeventhubs.Ownership,
+	_ *azeventhubs.ClaimOwnershipOptions) ([]azeventhubs.Ownership, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	claimed := make([]azeventhubs.Ownership, 0, len(partitionOwnership))
+	for _, ownership := range partitionOwnership {
+		key := newPartitionStoreKey(ownership.FullyQualifiedNamespace, ownership.EventHubName,
+			ownership.ConsumerGroup, ownership.PartitionID)
+		if current, ok := s.ownerships[key]; ok && (ownership.ETag == nil || *ownership.ETag != *current.ETag) {
+			continue
+		}
+		s.etagSeq++
+		etag := azcore.ETag(strconv.FormatInt(s.etagSeq, 10))
+		ownership.ETag = &etag
+		ownership.LastModifiedTime = time.Now().UTC()
+		s.ownerships[key] = ownership
+		claimed = append(claimed, ownership)
+	}
+	return claimed, nil
+}
+
+// ListCheckpoints lists every checkpoint of an event hub consumer group.
+func (s *MemoryCheckpointStore) ListCheckpoints(_ context.Context, fullyQualifiedNamespace string, eventHubName string,
+	consumerGroup string, _ *azeventhubs.ListCheckpointsOptions) ([]azeventhubs.Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoints := make([]azeventhubs.Checkpoint, 0, len(s.checkpoints))
+	for _, checkpoint := range s.checkpoints {
+		if checkpoint.FullyQualifiedNamespace == fullyQualifiedNamespace && checkpoint.EventHubName == eventHubName &&
+			checkpoint.ConsumerGroup == consumerGroup {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	return checkpoints, nil
+}
+
+// ListOwnership lists every partition ownership of an event hub consumer group.
+func (s *MemoryCheckpointStore) ListOwnership(_ context.Context, fullyQualifiedNamespace string, eventHubName string,
+	consumerGroup string, _ *azeventhubs.ListOwnershipOptions) ([]azeventhubs.Ownership, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ownerships := make([]azeventhubs.Ownership, 0, len(s.ownerships))
+	for _, ownership := range s.ownerships {
+		if ownership.FullyQualifiedNamespace == fullyQualifiedNamespace && ownership.EventHubName == eventHubName &&
+			ownership.ConsumerGroup == consumerGroup {
+			ownerships = append(ownerships, ownership)
+		}
+	}
+	return ownerships, nil
+}
+
+// SetCheckpoint stores the checkpoint of a partition.
+func (s *MemoryCheckpointStore) SetCheckpoint(_ context.Context, checkpoint azeventhubs.Checkpoint,
+	_ *azeventhubs.SetCheckpointOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := newPartitionStoreKey(checkpoint.FullyQualifiedNamespace, checkpoint.EventHubName,
+		checkpoint.ConsumerGroup, checkpoint.PartitionID)
+	s.checkpoints[key] = checkpoint
+	return nil
+}
@@ -0,0 +1,72 @@
+package eventhubs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/alexandria-oss/streams/driver/azure/eventhubs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCheckpointStore_ClaimOwnership(t *testing.T) {
+	ctx := context.Background()
+	store := eventhubs.NewMemoryCheckpointStore()
+	ownership := azeventhubs.Ownership{
+		ConsumerGroup:           "$Default",
+		EventHubName:            "foo",
+		FullyQualifiedNamespace: "foo.servicebus.windows.net",
+		PartitionID:             "0",
+		OwnerID:                 "reader-a",
+	}
+	claimed, err := store.ClaimOwnership(ctx, []azeventhubs.Ownership{ownership}, nil)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.NotNil(t, claimed[0].ETag)
+
+	// claims with outdated etags are rejected
+	stale := ownership
+	stale.OwnerID = "reader-b"
+	claimed, err = store.ClaimOwnership(ctx, []azeventhubs.Ownership{stale}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	owned, err := store.ListOwnership(ctx, ownership.FullyQualifiedNamespace, "foo", "$Default", nil)
+	require.NoError(t, err)
+	require.Len(t, owned, 1)
+	assert.Equal(t, "reader-a", owned[0].OwnerID)
+
+	stale.ETag = owned[0].ETag
+	claimed, err = store.ClaimOwnership(ctx, []azeventhubs.Ownership{stale}, nil)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "reader-b", claimed[0].OwnerID)
+
+	owned, err = store.ListOwnership(ctx, ownership.FullyQualifiedNamespace, "bar", "$Default", nil)
+	require.NoError(t, err)
+	assert.Empty(t, owned)
+}
+
+func TestMemoryCheckpointStore_SetCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	store := eventhubs.NewMemoryCheckpointStore()
+	seq, offset := int64(10), int64(1000)
+	checkpoint := azeventhubs.Checkpoint{
+		ConsumerGroup:           "$Default",
+		EventHubName:            "foo",
+		FullyQualifiedNamespace: "foo.servicebus.windows.net",
+		PartitionID:             "0",
+		Offset:                  &offset,
+		SequenceNumber:          &seq,
+	}
+	require.NoError(t, store.SetCheckpoint(ctx, checkpoint, nil))
+	nextSeq := int64(11)
+	checkpoint.SequenceNumber = &nextSeq
+	require.NoError(t, store.SetCheckpoint(ctx, checkpoint, nil))
+
+	checkpoints, err := store.ListCheckpoints(ctx, checkpoint.FullyQualifiedNamespace, "foo", "$Default", nil)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+	assert.Equal(t, int64(11), *checkpoints[0].SequenceNumber)
+}
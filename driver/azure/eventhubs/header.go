@@ -0,0 +1,16 @@
+package eventhubs
+
+const (
+	// HeaderPartitionID is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the partition of the event hub the event was read from.
+	HeaderPartitionID = "eventhubs-partition-id"
+	// HeaderSequenceNumber is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the sequence number of the event within its partition.
+	HeaderSequenceNumber = "eventhubs-sequence-number"
+	// HeaderOffset is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the offset of the event within its partition.
+	HeaderOffset = "eventhubs-offset"
+	// HeaderEnqueuedTime is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the timestamp in Unix milliseconds when Event Hubs enqueued the event.
+	HeaderEnqueuedTime = "eventhubs-enqueued-time"
+)
@@ -0,0 +1,74 @@
+package eventhubs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/azure"
+	"github.com/alexandria-oss/streams/internal/genericutil"
+)
+
+func marshalEvent(msg streams.Message) *azeventhubs.EventData {
+	props := make(map[string]any, len(msg.Headers)+5)
+	props[azure.HeaderMessageID] = msg.ID
+	props[azure.HeaderStreamName] = msg.StreamName
+	props[azure.HeaderStreamKey] = msg.StreamKey
+	props[azure.HeaderContentType] = msg.ContentType
+	props[azure.HeaderMessageTime] = strconv.FormatInt(msg.Time.UnixMilli(), 10)
+	for k, v := range msg.Headers {
+		props[k] = v
+	}
+	event := &azeventhubs.EventData{
+		Properties: props,
+		Body:       msg.Data,
+		MessageID:  to.Ptr(msg.ID),
+	}
+	if msg.ContentType != "" {
+		event.ContentType = to.Ptr(msg.ContentType)
+	}
+	return event
+}
+
+func unmarshalEvent(partitionID string, event *azeventhubs.ReceivedEventData) streams.Message {
+	// 4 as azeventhubs.ReceivedEventData has 4 fields to be appended into headers
+	headers := make(map[string]string, len(event.Properties)+4)
+	headers[HeaderPartitionID] = partitionID
+	headers[HeaderSequenceNumber] = strconv.FormatInt(event.SequenceNumber, 10)
+	headers[HeaderOffset] = strconv.FormatInt(event.Offset, 10)
+	if event.EnqueuedTime != nil {
+		headers[HeaderEnqueuedTime] = strconv.FormatInt(event.EnqueuedTime.UnixMilli(), 10)
+	}
+	msg := streams.Message{
+		ID:          genericutil.SafeDerefPtr(event.MessageID),
+		StreamKey:   genericutil.SafeDerefPtr(event.PartitionKey),
+		ContentType: genericutil.SafeDerefPtr(event.ContentType),
+		Headers:     headers,
+		Data:        event.Body,
+	}
+	for key, rawVal := range event.Properties {
+		val, ok := rawVal.(string)
+		if !ok {
+			val = fmt.Sprint(rawVal)
+		}
+		switch key {
+		case azure.HeaderMessageID:
+			msg.ID = val
+		case azure.HeaderStreamName:
+			msg.StreamName = val
+		case azure.HeaderStreamKey:
+			msg.StreamKey = val
+		case azure.HeaderContentType:
+			msg.ContentType = val
+		case azure.HeaderMessageTime:
+			timeMilli, _ := strconv.ParseInt(val, 10, 64)
+			msg.Time = time.UnixMilli(timeMilli)
+		default:
+			msg.Headers[key] = val
+		}
+	}
+	return msg
+}
@@ -0,0 +1,76 @@
+package eventhubs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/hashicorp/go-multierror"
+)
+
+// eventBatch is the subset of azeventhubs.EventDataBatch used by Writer.
+type eventBatch interface {
+	AddEventData(ed *azeventhubs.EventData, options *azeventhubs.AddEventDataOptions) error
+	NumEvents() int32
+}
+
+// batchProducer is the subset of azeventhubs.ProducerClient used by Writer.
+type batchProducer interface {
+	newBatch(ctx context.Context, partitionKey string) (eventBatch, error)
+	sendBatch(ctx context.Context, batch eventBatch) error
+	close(ctx context.Context) error
+}
+
+type sdkProducer struct {
+	producer *azeventhubs.ProducerClient
+}
+
+var _ batchProducer = sdkProducer{}
+
+func (p sdkProducer) newBatch(ctx context.Context, partitionKey string) (eventBatch, error) {
+	opts := &azeventhubs.EventDataBatchOptions{}
+	if partitionKey != "" {
+		opts.PartitionKey = &partitionKey
+	}
+	return p.producer.NewEventDataBatch(ctx, opts)
+}
+
+func (p sdkProducer) sendBatch(ctx context.Context, batch eventBatch) error {
+	return p.producer.SendEventDataBatch(ctx, batch.(*azeventhubs.EventDataBatch), nil)
+}
+
+func (p sdkProducer) close(ctx context.Context) error {
+	return p.producer.Close(ctx)
+}
+
+// sendBatches sends events sharing partitionKey in as few batches as possible, sending a batch every time it is full.
+func sendBatches(ctx context.Context, producer batchProducer, partitionKey string,
+	events []*azeventhubs.EventData) error {
+	batch, err := producer.newBatch(ctx, partitionKey)
+	if err != nil {
+		return err
+	}
+	errs := &multierror.Error{}
+	for _, event := range events {
+		err = batch.AddEventData(event, nil)
+		if errors.Is(err, azeventhubs.ErrEventDataTooLarge) && batch.NumEvents() > 0 {
+			if err = producer.sendBatch(ctx, batch); err != nil {
+				return multierror.Append(errs, err)
+			}
+			if batch, err = producer.newBatch(ctx, partitionKey); err != nil {
+				return multierror.Append(errs, err)
+			}
+			err = batch.AddEventData(event, nil)
+		}
+		if err != nil {
+			// event does not fit in an empty batch
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if batch.NumEvents() > 0 {
+		if err = producer.sendBatch(ctx, batch); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
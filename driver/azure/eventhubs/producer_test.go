@@ -0,0 +1,72 @@
+package eventhubs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/alexandria-oss/streams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBatch struct {
+	partitionKey string
+	maxBytes     int
+	numBytes     int
+	events       []*azeventhubs.EventData
+}
+
+func (b *fakeBatch) AddEventData(ed *azeventhubs.EventData, _ *azeventhubs.AddEventDataOptions) error {
+	if b.numBytes+len(ed.Body) > b.maxBytes {
+		return azeventhubs.ErrEventDataTooLarge
+	}
+	b.numBytes += len(ed.Body)
+	b.events = append(b.events, ed)
+	return nil
+}
+
+func (b *fakeBatch) NumEvents() int32 {
+	return int32(len(b.events))
+}
+
+type fakeProducer struct {
+	maxBytes int
+	sent     []*fakeBatch
+}
+
+func (p *fakeProducer) newBatch(_ context.Context, partitionKey string) (eventBatch, error) {
+	return &fakeBatch{partitionKey: partitionKey, maxBytes: p.maxBytes}, nil
+}
+
+func (p *fakeProducer) sendBatch(_ context.Context, batch eventBatch) error {
+	p.sent = append(p.sent, batch.(*fakeBatch))
+	return nil
+}
+
+func (p *fakeProducer) close(_ context.Context) error {
+	return nil
+}
+
+func TestWriter_Write(t *testing.T) {
+	producer := &fakeProducer{maxBytes: 6}
+	w := NewWriter(nil)
+	w.producers["foo"] = producer
+	err := w.Write(context.Background(), []streams.Message{
+		{ID: "1", StreamName: "foo", StreamKey: "foo-key", Data: []byte("123")},
+		{ID: "2", StreamName: "foo", Data: []byte("456")},
+		{ID: "3", StreamName: "foo", StreamKey: "foo-key", Data: []byte("789")},
+		{ID: "4", StreamName: "foo", StreamKey: "foo-key", Data: []byte("012")},
+		{ID: "5", StreamName: "foo", StreamKey: "foo-key", Data: []byte("1234567")},
+	})
+	// event 5 does not fit in an empty batch
+	assert.ErrorIs(t, err, azeventhubs.ErrEventDataTooLarge)
+
+	require.Len(t, producer.sent, 3)
+	assert.Equal(t, "foo-key", producer.sent[0].partitionKey)
+	assert.Len(t, producer.sent[0].events, 2)
+	assert.Equal(t, "foo-key", producer.sent[1].partitionKey)
+	assert.Len(t, producer.sent[1].events, 1)
+	assert.Equal(t, "", producer.sent[2].partitionKey)
+	assert.Len(t, producer.sent[2].events, 1)
+}
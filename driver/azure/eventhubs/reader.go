@@ -0,0 +1,182 @@
+package eventhubs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/alexandria-oss/streams"
+)
+
+// ErrMissingCheckpointStore the reader has no checkpoint store.
+var ErrMissingCheckpointStore = errors.New("streams.azure.eventhubs: missing checkpoint store")
+
+// ConsumerFunc allocates a consumer client for an event hub (e.g. using azeventhubs.NewConsumerClient with a
+// consumer group).
+type ConsumerFunc func(eventHub string) (*azeventhubs.ConsumerClient, error)
+
+// partitionClient is the subset of azeventhubs.ProcessorPartitionClient used by Reader.
+type partitionClient interface {
+	ReceiveEvents(ctx context.Context, count int,
+		options *azeventhubs.ReceiveEventsOptions) ([]*azeventhubs.ReceivedEventData, error)
+	UpdateCheckpoint(ctx context.Context, latestEvent *azeventhubs.ReceivedEventData,
+		options *azeventhubs.UpdateCheckpointOptions) error
+	PartitionID() string
+	Close(ctx context.Context) error
+}
+
+// ReaderConfig is the Azure Event Hubs reader configuration schema.
+type ReaderConfig struct {
+	Logger         *log.Logger   // Logging instance preferably with log level at <<info>>.
+	ErrorLogger    *log.Logger   // Logging instance preferably with log level at <<error>>.
+	BatchSize      int           // Maximum count of events for each receiving process.
+	ReceiveTimeout time.Duration // Maximum duration for a receiving process to wait for BatchSize events.
+	HandlerTimeout time.Duration // Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	// Store of partition ownerships and checkpoints (e.g. MemoryCheckpointStore or the Azure Blob Storage store from
+	// the azeventhubs/checkpoints package).
+	CheckpointStore azeventhubs.CheckpointStore
+	// Options of the processor balancing partitions between readers (e.g. start positions).
+	ProcessorOptions *azeventhubs.ProcessorOptions
+}
+
+// Reader is the Azure Event Hubs streams.Reader implementation.
+//
+// Partitions are balanced between readers of a consumer group using an azeventhubs.Processor, reading each owned
+// partition concurrently. A checkpoint is stored once every event of a received batch was handled. Handler failures
+// do not stop partition reading, wrap handlers with middleware functions (e.g. retries, dead-letter queues) instead.
+type Reader struct {
+	config      ReaderConfig
+	newConsumer ConsumerFunc
+}
+
+var _ streams.Reader = Reader{}
+
+// NewReader allocates an Azure Event Hubs concrete implementation of streams.Reader.
+func NewReader(cfg ReaderConfig, newConsumer ConsumerFunc) Reader {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.azure.eventhubs: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 10
+	}
+	if cfg.ReceiveTimeout <= 0 {
+		cfg.ReceiveTimeout = time.Second * 5
+	}
+	if cfg.HandlerTimeout == 0 {
+		cfg.HandlerTimeout = time.Second * 30
+	}
+	return Reader{
+		config:      cfg,
+		newConsumer: newConsumer,
+	}
+}
+
+func (r Reader) Read(ctx context.Context, task streams.ReadTask) error {
+	if r.config.CheckpointStore == nil {
+		return ErrMissingCheckpointStore
+	}
+	consumer, err := r.newConsumer(task.Stream)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), r.config.HandlerTimeout)
+		defer cancel()
+		if errClosure := consumer.Close(closeCtx); errClosure != nil {
+			r.config.ErrorLogger.Printf("error occurred closing consumer client, %s", errClosure.Error())
+		}
+	}()
+	processor, err := azeventhubs.NewProcessor(consumer, r.config.CheckpointStore, r.config.ProcessorOptions)
+	if err != nil {
+		return err
+	}
+
+	inFlight := sync.WaitGroup{}
+	inFlight.Add(1)
+	go func() {
+		defer inFlight.Done()
+		for {
+			client := processor.NextPartitionClient(ctx)
+			if client == nil {
+				// processor stopped
+				return
+			}
+			inFlight.Add(1)
+			go func() {
+				defer inFlight.Done()
+				r.readPartition(ctx, task, client)
+			}()
+		}
+	}()
+	err = processor.Run(ctx)
+	inFlight.Wait()
+	if err != nil {
+		return err
+	}
+	r.config.Logger.Printf("stopping event hub reading process")
+	return nil
+}
+
+// readPartition handles events received by client until ctx is done or the partition ownership is lost.
+func (r Reader) readPartition(ctx context.Context, task streams.ReadTask, client partitionClient) {
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), r.config.HandlerTimeout)
+		defer cancel()
+		if err := client.Close(closeCtx); err != nil {
+			r.config.ErrorLogger.Printf("error occurred closing partition <%s> client, %s", client.PartitionID(),
+				err.Error())
+		}
+	}()
+
+	for {
+		recCtx, cancel := context.WithTimeout(ctx, r.config.ReceiveTimeout)
+		events, err := client.ReceiveEvents(recCtx, r.config.BatchSize, nil)
+		cancel()
+		var errEH *azeventhubs.Error
+		if errors.As(err, &errEH) && errEH.Code == azeventhubs.ErrorCodeOwnershipLost {
+			r.config.Logger.Printf("partition <%s> ownership lost", client.PartitionID())
+			return
+		} else if err != nil && !errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			r.config.ErrorLogger.Printf("error occurred while receiving events from partition <%s>, %s",
+				client.PartitionID(), err.Error())
+			return
+		}
+
+		for _, event := range events {
+			r.handleEvent(ctx, task, client.PartitionID(), event)
+		}
+		if len(events) > 0 {
+			// checkpoint even if ctx was cancelled as events were processed already
+			checkpointCtx, cancelCheckpoint := context.WithTimeout(context.Background(), r.config.HandlerTimeout)
+			if errCheckpoint := client.UpdateCheckpoint(checkpointCtx, events[len(events)-1], nil); errCheckpoint != nil {
+				r.config.ErrorLogger.Printf("failed to checkpoint partition <%s>, %s", client.PartitionID(),
+					errCheckpoint.Error())
+			}
+			cancelCheckpoint()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (r Reader) handleEvent(ctx context.Context, task streams.ReadTask, partitionID string,
+	event *azeventhubs.ReceivedEventData) {
+	scopedCtx, cancel := context.WithTimeout(ctx, r.config.HandlerTimeout)
+	defer cancel()
+	if err := task.Handler(scopedCtx, unmarshalEvent(partitionID, event)); err != nil {
+		// do nothing as developers are able to wrap message handler with middleware functions.
+		r.config.ErrorLogger.Printf("failed to handle event <%d> from partition <%s>, %s", event.SequenceNumber,
+			partitionID, err.Error())
+	}
+}
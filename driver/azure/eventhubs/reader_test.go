@@ -0,0 +1,107 @@
+package eventhubs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/alexandria-oss/streams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePartitionClient struct {
+	pending     [][]*azeventhubs.ReceivedEventData
+	checkpoints []int64
+	closed      bool
+}
+
+var _ partitionClient = &fakePartitionClient{}
+
+func (c *fakePartitionClient) ReceiveEvents(ctx context.Context, _ int,
+	_ *azeventhubs.ReceiveEventsOptions) ([]*azeventhubs.ReceivedEventData, error) {
+	if len(c.pending) == 0 {
+		return nil, &azeventhubs.Error{Code: azeventhubs.ErrorCodeOwnershipLost}
+	}
+	events := c.pending[0]
+	c.pending = c.pending[1:]
+	if len(events) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return events, nil
+}
+
+func (c *fakePartitionClient) UpdateCheckpoint(_ context.Context, latestEvent *azeventhubs.ReceivedEventData,
+	_ *azeventhubs.UpdateCheckpointOptions) error {
+	c.checkpoints = append(c.checkpoints, latestEvent.SequenceNumber)
+	return nil
+}
+
+func (c *fakePartitionClient) PartitionID() string {
+	return "0"
+}
+
+func (c *fakePartitionClient) Close(_ context.Context) error {
+	c.closed = true
+	return nil
+}
+
+func newTestEvent(seq int64, msg streams.Message) *azeventhubs.ReceivedEventData {
+	partitionKey := msg.StreamKey
+	return &azeventhubs.ReceivedEventData{
+		EventData:      *marshalEvent(msg),
+		PartitionKey:   &partitionKey,
+		SequenceNumber: seq,
+		Offset:         seq * 100,
+	}
+}
+
+func TestReader_ReadPartition(t *testing.T) {
+	client := &fakePartitionClient{
+		pending: [][]*azeventhubs.ReceivedEventData{
+			{
+				newTestEvent(1, streams.Message{ID: "1", StreamName: "foo", StreamKey: "foo-key",
+					Headers: map[string]string{"foo": "bar"}, Data: []byte("1")}),
+				newTestEvent(2, streams.Message{ID: "2", StreamName: "foo", Data: []byte("2")}),
+			},
+			{}, // receive timeout
+			{
+				newTestEvent(3, streams.Message{ID: "3", StreamName: "foo", Data: []byte("3")}),
+			},
+		},
+	}
+	r := NewReader(ReaderConfig{ReceiveTimeout: time.Millisecond * 20}, nil)
+	received := make([]streams.Message, 0, 3)
+	r.readPartition(context.Background(), streams.ReadTask{
+		Stream: "foo",
+		Handler: func(_ context.Context, msg streams.Message) error {
+			received = append(received, msg)
+			if msg.ID == "2" {
+				return errors.New("generic error")
+			}
+			return nil
+		},
+	}, client)
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "1", received[0].ID)
+	assert.Equal(t, "foo", received[0].StreamName)
+	assert.Equal(t, "foo-key", received[0].StreamKey)
+	assert.Equal(t, "bar", received[0].Headers["foo"])
+	assert.Equal(t, "0", received[0].Headers[HeaderPartitionID])
+	assert.Equal(t, "1", received[0].Headers[HeaderSequenceNumber])
+	assert.Equal(t, "100", received[0].Headers[HeaderOffset])
+	// failed events do not stop checkpointing
+	assert.Equal(t, []int64{2, 3}, client.checkpoints)
+	// partition clients are closed once ownership is lost
+	assert.True(t, client.closed)
+}
+
+func TestReader_ReadMissingCheckpointStore(t *testing.T) {
+	r := NewReader(ReaderConfig{}, nil)
+	err := r.Read(context.Background(), streams.ReadTask{Stream: "foo"})
+	assert.ErrorIs(t, err, ErrMissingCheckpointStore)
+}
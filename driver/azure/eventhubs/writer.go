@@ -0,0 +1,91 @@
+package eventhubs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/azure"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ProducerFunc allocates a producer client for an event hub (e.g. using azeventhubs.NewProducerClient).
+type ProducerFunc func(eventHub string) (*azeventhubs.ProducerClient, error)
+
+// Writer is the Azure Event Hubs streams.Writer implementation.
+//
+// Messages are sent in batches to the event hub named after Message.StreamName, using Message.StreamKey as
+// partition key so messages sharing a key are stored in order within the same partition. Producer clients are
+// kept until Writer.Close is called.
+type Writer struct {
+	azure.Writer
+	newProducer ProducerFunc
+	mu          *sync.Mutex
+	producers   map[string]batchProducer
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates an Azure Event Hubs concrete implementation of streams.Writer.
+func NewWriter(newProducer ProducerFunc) Writer {
+	w := Writer{
+		newProducer: newProducer,
+		mu:          &sync.Mutex{},
+		producers:   make(map[string]batchProducer),
+	}
+	w.WriteFunc = w.write
+	return w
+}
+
+func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Message) error {
+	producer, err := w.producer(stream)
+	if err != nil {
+		return err
+	}
+
+	// partition keys are set per batch
+	keyBuf := make(map[string][]*azeventhubs.EventData)
+	keys := make([]string, 0, 1)
+	for _, msg := range msgBatch {
+		if _, ok := keyBuf[msg.StreamKey]; !ok {
+			keys = append(keys, msg.StreamKey)
+		}
+		keyBuf[msg.StreamKey] = append(keyBuf[msg.StreamKey], marshalEvent(msg))
+	}
+	errs := &multierror.Error{}
+	for _, key := range keys {
+		if err = sendBatches(ctx, producer, key, keyBuf[key]); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func (w Writer) producer(stream string) (batchProducer, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if producer, ok := w.producers[stream]; ok {
+		return producer, nil
+	}
+	producer, err := w.newProducer(stream)
+	if err != nil {
+		return nil, err
+	}
+	w.producers[stream] = sdkProducer{producer: producer}
+	return w.producers[stream], nil
+}
+
+// Close closes every producer client.
+func (w Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	errs := &multierror.Error{}
+	for stream, producer := range w.producers {
+		if err := producer.close(ctx); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		delete(w.producers, stream)
+	}
+	return errs.ErrorOrNil()
+}
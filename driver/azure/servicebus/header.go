@@ -0,0 +1,19 @@
+package servicebus
+
+const (
+	// HeaderSequenceNumber is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the unique number assigned by Service Bus to the message once enqueued.
+	HeaderSequenceNumber = "servicebus-sequence-number"
+	// HeaderDeliveryCount is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the count of times the message was delivered (i.e. 1 on the first delivery).
+	HeaderDeliveryCount = "servicebus-delivery-count"
+	// HeaderEnqueuedTime is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the timestamp in Unix milliseconds when Service Bus enqueued the message.
+	HeaderEnqueuedTime = "servicebus-enqueued-time"
+	// HeaderSessionID is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the session of the message (Message.StreamKey if sessions are enabled).
+	HeaderSessionID = "servicebus-session-id"
+	// HeaderSubscription is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the topic subscription the message was received from (empty for queues).
+	HeaderSubscription = "servicebus-subscription"
+)
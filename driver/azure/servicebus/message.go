@@ -0,0 +1,78 @@
+package servicebus
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/azure"
+	"github.com/alexandria-oss/streams/internal/genericutil"
+)
+
+func marshalMessage(msg streams.Message, enableSessions bool) *azservicebus.Message {
+	props := make(map[string]any, len(msg.Headers)+5)
+	props[azure.HeaderMessageID] = msg.ID
+	props[azure.HeaderStreamName] = msg.StreamName
+	props[azure.HeaderStreamKey] = msg.StreamKey
+	props[azure.HeaderContentType] = msg.ContentType
+	props[azure.HeaderMessageTime] = strconv.FormatInt(msg.Time.UnixMilli(), 10)
+	for k, v := range msg.Headers {
+		props[k] = v
+	}
+	rawMsg := &azservicebus.Message{
+		ApplicationProperties: props,
+		Body:                  msg.Data,
+		MessageID:             to.Ptr(msg.ID),
+	}
+	if msg.ContentType != "" {
+		rawMsg.ContentType = to.Ptr(msg.ContentType)
+	}
+	if enableSessions {
+		rawMsg.SessionID = to.Ptr(msg.StreamKey)
+	}
+	return rawMsg
+}
+
+func unmarshalMessage(rawMsg *azservicebus.ReceivedMessage) streams.Message {
+	// 5 as azservicebus.ReceivedMessage has 5 fields to be appended into headers
+	headers := make(map[string]string, len(rawMsg.ApplicationProperties)+5)
+	headers[HeaderDeliveryCount] = strconv.FormatUint(uint64(rawMsg.DeliveryCount), 10)
+	headers[HeaderSessionID] = genericutil.SafeDerefPtr(rawMsg.SessionID)
+	if rawMsg.SequenceNumber != nil {
+		headers[HeaderSequenceNumber] = strconv.FormatInt(*rawMsg.SequenceNumber, 10)
+	}
+	if rawMsg.EnqueuedTime != nil {
+		headers[HeaderEnqueuedTime] = strconv.FormatInt(rawMsg.EnqueuedTime.UnixMilli(), 10)
+	}
+	msg := streams.Message{
+		ID:          rawMsg.MessageID,
+		ContentType: genericutil.SafeDerefPtr(rawMsg.ContentType),
+		Headers:     headers,
+		Data:        rawMsg.Body,
+	}
+	for key, rawVal := range rawMsg.ApplicationProperties {
+		val, ok := rawVal.(string)
+		if !ok {
+			val = fmt.Sprint(rawVal)
+		}
+		switch key {
+		case azure.HeaderMessageID:
+			msg.ID = val
+		case azure.HeaderStreamName:
+			msg.StreamName = val
+		case azure.HeaderStreamKey:
+			msg.StreamKey = val
+		case azure.HeaderContentType:
+			msg.ContentType = val
+		case azure.HeaderMessageTime:
+			timeMilli, _ := strconv.ParseInt(val, 10, 64)
+			msg.Time = time.UnixMilli(timeMilli)
+		default:
+			msg.Headers[key] = val
+		}
+	}
+	return msg
+}
@@ -0,0 +1,202 @@
+package servicebus
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/internal/genericutil"
+)
+
+// ReaderTaskSubscriptionKey is the argument key to set up a reader to receive messages from a subscription of the
+// topic named after the task stream. If not set, messages are received from the queue named after the task stream.
+const ReaderTaskSubscriptionKey string = "azure-servicebus-subscription"
+
+const deadLetterReasonUnrecoverable = "streams: unrecoverable"
+
+// receiver is the subset of azservicebus.Receiver and azservicebus.SessionReceiver used by Reader.
+type receiver interface {
+	ReceiveMessages(ctx context.Context, maxMessages int,
+		options *azservicebus.ReceiveMessagesOptions) ([]*azservicebus.ReceivedMessage, error)
+	CompleteMessage(ctx context.Context, message *azservicebus.ReceivedMessage,
+		options *azservicebus.CompleteMessageOptions) error
+	AbandonMessage(ctx context.Context, message *azservicebus.ReceivedMessage,
+		options *azservicebus.AbandonMessageOptions) error
+	DeadLetterMessage(ctx context.Context, message *azservicebus.ReceivedMessage,
+		options *azservicebus.DeadLetterOptions) error
+	Close(ctx context.Context) error
+}
+
+// ReaderConfig is the Azure Service Bus reader configuration schema.
+type ReaderConfig struct {
+	Logger         *log.Logger   // Logging instance preferably with log level at <<info>>.
+	ErrorLogger    *log.Logger   // Logging instance preferably with log level at <<error>>.
+	MaxMessages    int           // Maximum count of messages for each receiving process.
+	HandlerTimeout time.Duration // Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	// Receive messages from sessions (i.e. Message.StreamKey if WriterConfig.EnableSessions is set), one session at
+	// a time. Required by queues and subscriptions with sessions enabled.
+	EnableSessions bool
+	// Maximum duration for a session receiver to wait for messages before releasing its session and accepting the
+	// next one.
+	SessionIdleTimeout time.Duration
+}
+
+// Reader is the Azure Service Bus streams.Reader implementation using peek-lock receivers.
+//
+// Messages are completed once their handler succeeded, dead-lettered if the handler returned a
+// streams.ErrUnrecoverable error and abandoned otherwise, so Service Bus delivers them again (or dead-letters them
+// once the maximum delivery count is reached).
+type Reader struct {
+	config ReaderConfig
+	client *azservicebus.Client
+}
+
+var _ streams.Reader = Reader{}
+
+// NewReader allocates an Azure Service Bus concrete implementation of streams.Reader.
+func NewReader(cfg ReaderConfig, client *azservicebus.Client) Reader {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.azure.servicebus: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.MaxMessages <= 0 {
+		cfg.MaxMessages = 10
+	}
+	if cfg.HandlerTimeout == 0 {
+		cfg.HandlerTimeout = time.Second * 30
+	}
+	if cfg.SessionIdleTimeout <= 0 {
+		cfg.SessionIdleTimeout = time.Second * 5
+	}
+	return Reader{
+		config: cfg,
+		client: client,
+	}
+}
+
+func (r Reader) Read(ctx context.Context, task streams.ReadTask) error {
+	subscription := genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskSubscriptionKey])
+	if r.config.EnableSessions {
+		return r.readSessions(ctx, task, subscription)
+	}
+
+	var rec receiver
+	var err error
+	if subscription != "" {
+		rec, err = r.client.NewReceiverForSubscription(task.Stream, subscription, nil)
+	} else {
+		rec, err = r.client.NewReceiverForQueue(task.Stream, nil)
+	}
+	if err != nil {
+		return err
+	}
+	defer r.closeReceiver(rec)
+	if err = r.receive(ctx, task, subscription, rec, 0); err != nil {
+		return err
+	}
+	r.config.Logger.Printf("stopping queue receiving process")
+	return nil
+}
+
+// readSessions accepts the next available session and receives its messages until the session is idle, one
+// session at a time.
+func (r Reader) readSessions(ctx context.Context, task streams.ReadTask, subscription string) error {
+	for {
+		var rec *azservicebus.SessionReceiver
+		var err error
+		if subscription != "" {
+			rec, err = r.client.AcceptNextSessionForSubscription(ctx, task.Stream, subscription, nil)
+		} else {
+			rec, err = r.client.AcceptNextSessionForQueue(ctx, task.Stream, nil)
+		}
+		if ctx.Err() != nil {
+			r.config.Logger.Printf("stopping session receiving process")
+			return nil
+		}
+		var errSB *azservicebus.Error
+		if errors.As(err, &errSB) && errSB.Code == azservicebus.CodeTimeout {
+			// no session is available
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		err = r.receive(ctx, task, subscription, rec, r.config.SessionIdleTimeout)
+		r.closeReceiver(rec)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// receive handles messages received by rec until ctx is done or, if idleTimeout is positive, no messages were
+// received within idleTimeout.
+func (r Reader) receive(ctx context.Context, task streams.ReadTask, subscription string, rec receiver,
+	idleTimeout time.Duration) error {
+	for {
+		recCtx, cancel := ctx, context.CancelFunc(func() {})
+		if idleTimeout > 0 {
+			recCtx, cancel = context.WithTimeout(ctx, idleTimeout)
+		}
+		msgs, err := rec.ReceiveMessages(recCtx, r.config.MaxMessages, nil)
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		} else if idleTimeout > 0 && len(msgs) == 0 && (err == nil || errors.Is(err, context.DeadlineExceeded)) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			r.handleMessage(ctx, task, subscription, rec, msg)
+		}
+	}
+}
+
+func (r Reader) handleMessage(ctx context.Context, task streams.ReadTask, subscription string, rec receiver,
+	rawMsg *azservicebus.ReceivedMessage) {
+	msg := unmarshalMessage(rawMsg)
+	msg.Headers[HeaderSubscription] = subscription
+
+	scopedCtx, cancel := context.WithTimeout(ctx, r.config.HandlerTimeout)
+	errHandle := task.Handler(scopedCtx, msg)
+	cancel()
+
+	// settle even if ctx was cancelled as messages were processed already
+	settleCtx, cancelSettle := context.WithTimeout(context.Background(), r.config.HandlerTimeout)
+	defer cancelSettle()
+	var err error
+	switch {
+	case errHandle == nil:
+		err = rec.CompleteMessage(settleCtx, rawMsg, nil)
+	case errors.Is(errHandle, streams.ErrUnrecoverable):
+		err = rec.DeadLetterMessage(settleCtx, rawMsg, &azservicebus.DeadLetterOptions{
+			ErrorDescription: to.Ptr(errHandle.Error()),
+			Reason:           to.Ptr(deadLetterReasonUnrecoverable),
+		})
+	default:
+		err = rec.AbandonMessage(settleCtx, rawMsg, nil)
+	}
+	if err != nil {
+		r.config.ErrorLogger.Printf("failed to settle message <%s>, %s", msg.ID, err.Error())
+	}
+}
+
+func (r Reader) closeReceiver(rec receiver) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.HandlerTimeout)
+	defer cancel()
+	if err := rec.Close(ctx); err != nil {
+		r.config.ErrorLogger.Printf("error occurred closing receiver, %s", err.Error())
+	}
+}
@@ -0,0 +1,135 @@
+package servicebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/alexandria-oss/streams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReceiver struct {
+	pending      [][]*azservicebus.ReceivedMessage
+	completed    []string
+	abandoned    []string
+	deadLettered []string
+	deadReasons  []string
+}
+
+var _ receiver = &fakeReceiver{}
+
+func (r *fakeReceiver) ReceiveMessages(ctx context.Context, _ int,
+	_ *azservicebus.ReceiveMessagesOptions) ([]*azservicebus.ReceivedMessage, error) {
+	if len(r.pending) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	msgs := r.pending[0]
+	r.pending = r.pending[1:]
+	return msgs, nil
+}
+
+func (r *fakeReceiver) CompleteMessage(_ context.Context, message *azservicebus.ReceivedMessage,
+	_ *azservicebus.CompleteMessageOptions) error {
+	r.completed = append(r.completed, message.MessageID)
+	return nil
+}
+
+func (r *fakeReceiver) AbandonMessage(_ context.Context, message *azservicebus.ReceivedMessage,
+	_ *azservicebus.AbandonMessageOptions) error {
+	r.abandoned = append(r.abandoned, message.MessageID)
+	return nil
+}
+
+func (r *fakeReceiver) DeadLetterMessage(_ context.Context, message *azservicebus.ReceivedMessage,
+	options *azservicebus.DeadLetterOptions) error {
+	r.deadLettered = append(r.deadLettered, message.MessageID)
+	r.deadReasons = append(r.deadReasons, *options.Reason)
+	return nil
+}
+
+func (r *fakeReceiver) Close(_ context.Context) error {
+	return nil
+}
+
+func TestReader_Receive(t *testing.T) {
+	msgTime := time.UnixMilli(time.Now().UnixMilli())
+	written := marshalMessage(streams.Message{
+		ID:          "1",
+		StreamName:  "foo",
+		StreamKey:   "foo-key",
+		ContentType: "text/plain",
+		Headers:     map[string]string{"foo": "bar"},
+		Data:        []byte("1"),
+		Time:        msgTime,
+	}, true)
+	rec := &fakeReceiver{
+		pending: [][]*azservicebus.ReceivedMessage{
+			{
+				{
+					MessageID:             "1",
+					ApplicationProperties: written.ApplicationProperties,
+					Body:                  written.Body,
+					ContentType:           written.ContentType,
+					SessionID:             written.SessionID,
+					DeliveryCount:         1,
+					SequenceNumber:        to.Ptr(int64(10)),
+				},
+				{MessageID: "2"},
+			},
+			{
+				{MessageID: "3"},
+			},
+		},
+	}
+
+	r := NewReader(ReaderConfig{}, nil)
+	received := make([]streams.Message, 0, 3)
+	task := streams.ReadTask{
+		Stream: "foo",
+		Handler: func(_ context.Context, msg streams.Message) error {
+			received = append(received, msg)
+			switch msg.ID {
+			case "2":
+				return errors.New("generic error")
+			case "3":
+				return fmt.Errorf("%w: invalid message", streams.ErrUnrecoverable)
+			}
+			return nil
+		},
+	}
+	// receivers with an idle timeout (i.e. session receivers) return once no messages are received
+	err := r.receive(context.Background(), task, "foo-sub", rec, time.Millisecond*50)
+	require.NoError(t, err)
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "foo", received[0].StreamName)
+	assert.Equal(t, "foo-key", received[0].StreamKey)
+	assert.Equal(t, "text/plain", received[0].ContentType)
+	assert.Equal(t, []byte("1"), received[0].Data)
+	assert.True(t, msgTime.Equal(received[0].Time))
+	assert.Equal(t, "bar", received[0].Headers["foo"])
+	assert.Equal(t, "foo-key", received[0].Headers[HeaderSessionID])
+	assert.Equal(t, "1", received[0].Headers[HeaderDeliveryCount])
+	assert.Equal(t, "10", received[0].Headers[HeaderSequenceNumber])
+	assert.Equal(t, "foo-sub", received[0].Headers[HeaderSubscription])
+
+	assert.Equal(t, []string{"1"}, rec.completed)
+	assert.Equal(t, []string{"2"}, rec.abandoned)
+	assert.Equal(t, []string{"3"}, rec.deadLettered)
+	assert.Equal(t, []string{deadLetterReasonUnrecoverable}, rec.deadReasons)
+}
+
+func TestReader_ReceiveCancelled(t *testing.T) {
+	r := NewReader(ReaderConfig{}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	err := r.receive(ctx, streams.ReadTask{}, "", &fakeReceiver{}, 0)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,71 @@
+package servicebus
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/hashicorp/go-multierror"
+)
+
+// messageBatch is the subset of azservicebus.MessageBatch used by Writer.
+type messageBatch interface {
+	AddMessage(m *azservicebus.Message, options *azservicebus.AddMessageOptions) error
+	NumMessages() int32
+}
+
+// batchSender is the subset of azservicebus.Sender used by Writer.
+type batchSender interface {
+	newBatch(ctx context.Context) (messageBatch, error)
+	sendBatch(ctx context.Context, batch messageBatch) error
+	close(ctx context.Context) error
+}
+
+type sdkSender struct {
+	sender *azservicebus.Sender
+}
+
+var _ batchSender = sdkSender{}
+
+func (s sdkSender) newBatch(ctx context.Context) (messageBatch, error) {
+	return s.sender.NewMessageBatch(ctx, nil)
+}
+
+func (s sdkSender) sendBatch(ctx context.Context, batch messageBatch) error {
+	return s.sender.SendMessageBatch(ctx, batch.(*azservicebus.MessageBatch), nil)
+}
+
+func (s sdkSender) close(ctx context.Context) error {
+	return s.sender.Close(ctx)
+}
+
+// sendBatches sends msgBatch in as few batches as possible, sending a batch every time it is full.
+func sendBatches(ctx context.Context, sender batchSender, msgBatch []*azservicebus.Message) error {
+	batch, err := sender.newBatch(ctx)
+	if err != nil {
+		return err
+	}
+	errs := &multierror.Error{}
+	for _, msg := range msgBatch {
+		err = batch.AddMessage(msg, nil)
+		if errors.Is(err, azservicebus.ErrMessageTooLarge) && batch.NumMessages() > 0 {
+			if err = sender.sendBatch(ctx, batch); err != nil {
+				return multierror.Append(errs, err)
+			}
+			if batch, err = sender.newBatch(ctx); err != nil {
+				return multierror.Append(errs, err)
+			}
+			err = batch.AddMessage(msg, nil)
+		}
+		if err != nil {
+			// message does not fit in an empty batch
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if batch.NumMessages() > 0 {
+		if err = sender.sendBatch(ctx, batch); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,101 @@
+package servicebus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBatch struct {
+	maxBytes int
+	numBytes int
+	msgs     []*azservicebus.Message
+}
+
+func (b *fakeBatch) AddMessage(m *azservicebus.Message, _ *azservicebus.AddMessageOptions) error {
+	if b.numBytes+len(m.Body) > b.maxBytes {
+		return azservicebus.ErrMessageTooLarge
+	}
+	b.numBytes += len(m.Body)
+	b.msgs = append(b.msgs, m)
+	return nil
+}
+
+func (b *fakeBatch) NumMessages() int32 {
+	return int32(len(b.msgs))
+}
+
+type fakeSender struct {
+	maxBytes int
+	sendErr  error
+	sent     [][]*azservicebus.Message
+}
+
+func (s *fakeSender) newBatch(_ context.Context) (messageBatch, error) {
+	return &fakeBatch{maxBytes: s.maxBytes}, nil
+}
+
+func (s *fakeSender) sendBatch(_ context.Context, batch messageBatch) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.sent = append(s.sent, batch.(*fakeBatch).msgs)
+	return nil
+}
+
+func (s *fakeSender) close(_ context.Context) error {
+	return nil
+}
+
+func TestSendBatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		inBodies    []string
+		inSendErr   error
+		wantBatches []int
+		wantErr     bool
+	}{
+		{
+			name:        "single batch",
+			inBodies:    []string{"foo", "bar"},
+			wantBatches: []int{2},
+		},
+		{
+			name:        "full batches",
+			inBodies:    []string{"foo", "bar", "baz", "qux", "quux"},
+			wantBatches: []int{3, 2},
+		},
+		{
+			name:        "too large",
+			inBodies:    []string{"foo", "foobarbazqux", "bar"},
+			wantBatches: []int{1, 1},
+			wantErr:     true,
+		},
+		{
+			name:      "send failure",
+			inBodies:  []string{"foo"},
+			inSendErr: errors.New("generic error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{maxBytes: 10, sendErr: tt.inSendErr}
+			msgs := make([]*azservicebus.Message, 0, len(tt.inBodies))
+			for _, body := range tt.inBodies {
+				msgs = append(msgs, &azservicebus.Message{Body: []byte(body)})
+			}
+			err := sendBatches(context.Background(), sender, msgs)
+			assert.Equal(t, tt.wantErr, err != nil)
+			require.Len(t, sender.sent, len(tt.wantBatches))
+			for i, size := range tt.wantBatches {
+				assert.Len(t, sender.sent[i], size)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package servicebus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/azure"
+	"github.com/hashicorp/go-multierror"
+)
+
+// WriterConfig is the Azure Service Bus writer configuration schema.
+type WriterConfig struct {
+	// Set Message.StreamKey as the message session identifier, so messages sharing a key are received in order by a
+	// single session receiver. Required by queues and subscriptions with sessions enabled.
+	EnableSessions bool
+}
+
+// Writer is the Azure Service Bus streams.Writer implementation.
+//
+// Messages are sent in batches to the queue or topic named after Message.StreamName; senders are kept until
+// Writer.Close is called.
+type Writer struct {
+	azure.Writer
+	config  WriterConfig
+	client  *azservicebus.Client
+	mu      *sync.Mutex
+	senders map[string]batchSender
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates an Azure Service Bus concrete implementation of streams.Writer.
+func NewWriter(cfg WriterConfig, client *azservicebus.Client) Writer {
+	w := Writer{
+		config:  cfg,
+		client:  client,
+		mu:      &sync.Mutex{},
+		senders: make(map[string]batchSender),
+	}
+	w.WriteFunc = w.write
+	return w
+}
+
+func (w Writer) write(ctx context.Context, stream string, msgBatch []streams.Message) error {
+	sender, err := w.sender(stream)
+	if err != nil {
+		return err
+	}
+	rawMsgs := make([]*azservicebus.Message, len(msgBatch))
+	for i, msg := range msgBatch {
+		rawMsgs[i] = marshalMessage(msg, w.config.EnableSessions)
+	}
+	return sendBatches(ctx, sender, rawMsgs)
+}
+
+func (w Writer) sender(stream string) (batchSender, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if sender, ok := w.senders[stream]; ok {
+		return sender, nil
+	}
+	sender, err := w.client.NewSender(stream, nil)
+	if err != nil {
+		return nil, err
+	}
+	w.senders[stream] = sdkSender{sender: sender}
+	return w.senders[stream], nil
+}
+
+// Close closes every sender.
+func (w Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	errs := &multierror.Error{}
+	for stream, sender := range w.senders {
+		if err := sender.close(ctx); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		delete(w.senders, stream)
+	}
+	return errs.ErrorOrNil()
+}
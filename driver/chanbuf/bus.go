@@ -84,9 +84,10 @@ func newDefaultBus() {
 // Start spins up the Bus readers, blocking the I/O until Bus.Shutdown is called.
 func (b *Bus) Start() {
 	b.baseCtx, b.baseCtxCancel = context.WithCancel(context.Background())
+	msgQueue := b.msgQueue // Shutdown resets the queue once Bus is ready.
 	b.isReady.Store(1)
 	b.isReadyWg.Done()
-	for msg := range b.msgQueue {
+	for msg := range msgQueue {
 		// fan-out process
 
 		// We implement a root-child lock mechanism.
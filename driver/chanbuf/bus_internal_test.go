@@ -0,0 +1,30 @@
+package chanbuf
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// run with -race flag.
+func TestBus_StartShutdown(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		bus := NewBus(Config{
+			ReaderHandlerTimeout: time.Second,
+		})
+		done := make(chan struct{})
+		go func() {
+			bus.Start()
+			close(done)
+		}()
+		for bus.isReady.Load() == 0 {
+			runtime.Gosched()
+		}
+		bus.Shutdown() // resets queue as soon as Bus is ready
+		select {
+		case <-done:
+		case <-time.After(time.Second * 5):
+			t.Fatal("bus did not stop after shutdown")
+		}
+	}
+}
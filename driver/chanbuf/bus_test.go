@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -123,13 +124,13 @@ func TestReaderWriter(t *testing.T) {
 	var writer streams.Writer = chanbuf.NewWriter(nil)
 	go chanbuf.Start()
 
-	wasHandlerExec := false
+	wasHandlerExec := atomic.Bool{}
 	waitChan := make(chan struct{}, 1)
 
 	err := reader.Read(context.TODO(), streams.ReadTask{
 		Stream: "foo",
 		Handler: func(ctx context.Context, msg streams.Message) error {
-			wasHandlerExec = true
+			wasHandlerExec.Store(true)
 			waitChan <- struct{}{}
 			return nil
 		},
@@ -155,7 +156,7 @@ func TestReaderWriter(t *testing.T) {
 	assert.NoError(t, err)
 
 	<-waitChan
-	assert.True(t, wasHandlerExec)
+	assert.True(t, wasHandlerExec.Load())
 	chanbuf.Shutdown()
 
 	err = writer.Write(context.TODO(), []streams.Message{
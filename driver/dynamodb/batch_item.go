@@ -0,0 +1,95 @@
+package dynamodb
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Egress table attributes. Time values are stored as Unix time in nanoseconds.
+const (
+	batchIDAttribute        = "batch_id"
+	messageCountAttribute   = "message_count"
+	rawDataAttribute        = "raw_data"
+	insertTimeAttribute     = "insert_time"
+	writeRetriesAttribute   = "write_retries"
+	lastWriteErrorAttribute = "last_write_error"
+	parkedAttribute         = "parked"
+	publishTimeAttribute    = "publish_time"
+)
+
+func newTimeAttribute(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberN{
+		Value: strconv.FormatInt(t.UnixNano(), 10),
+	}
+}
+
+func newBatchItem(batchID string, messageCount int, rawData []byte, insertTime time.Time) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		batchIDAttribute: &types.AttributeValueMemberS{
+			Value: batchID,
+		},
+		messageCountAttribute: &types.AttributeValueMemberN{
+			Value: strconv.Itoa(messageCount),
+		},
+		rawDataAttribute: &types.AttributeValueMemberB{
+			Value: rawData,
+		},
+		insertTimeAttribute: newTimeAttribute(insertTime),
+		writeRetriesAttribute: &types.AttributeValueMemberN{
+			Value: "0",
+		},
+		parkedAttribute: &types.AttributeValueMemberBOOL{
+			Value: false,
+		},
+	}
+}
+
+func getIntAttribute(item map[string]types.AttributeValue, key string) int64 {
+	val, ok := item[key].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0
+	}
+	i, _ := strconv.ParseInt(val.Value, 10, 64)
+	return i
+}
+
+func getTimeAttribute(item map[string]types.AttributeValue, key string) time.Time {
+	nanos := getIntAttribute(item, key)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
+
+func getStringAttribute(item map[string]types.AttributeValue, key string) string {
+	if val, ok := item[key].(*types.AttributeValueMemberS); ok {
+		return val.Value
+	}
+	return ""
+}
+
+func getBoolAttribute(item map[string]types.AttributeValue, key string) bool {
+	if val, ok := item[key].(*types.AttributeValueMemberBOOL); ok {
+		return val.Value
+	}
+	return false
+}
+
+func newBatch(item map[string]types.AttributeValue) egress.Batch {
+	var rawData []byte
+	if val, ok := item[rawDataAttribute].(*types.AttributeValueMemberB); ok {
+		rawData = val.Value
+	}
+	return egress.Batch{
+		BatchID:           getStringAttribute(item, batchIDAttribute),
+		TransportBatchRaw: rawData,
+		InsertTime:        getTimeAttribute(item, insertTimeAttribute),
+		WriteRetries:      int(getIntAttribute(item, writeRetriesAttribute)),
+		LastWriteError:    getStringAttribute(item, lastWriteErrorAttribute),
+		IsParked:          getBoolAttribute(item, parkedAttribute),
+		PublishTime:       getTimeAttribute(item, publishTimeAttribute),
+	}
+}
@@ -0,0 +1,87 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBatch(t *testing.T) {
+	insertTime := time.Now().UTC()
+	item := newBatchItem("123", 2, []byte("foo"), insertTime)
+	assert.Equal(t, egress.Batch{
+		BatchID:           "123",
+		TransportBatchRaw: []byte("foo"),
+		InsertTime:        insertTime,
+	}, newBatch(item))
+
+	item[lastWriteErrorAttribute] = &types.AttributeValueMemberS{Value: "generic error"}
+	item[writeRetriesAttribute] = &types.AttributeValueMemberN{Value: "3"}
+	item[parkedAttribute] = &types.AttributeValueMemberBOOL{Value: true}
+	item[publishTimeAttribute] = newTimeAttribute(insertTime)
+	batch := newBatch(item)
+	assert.Equal(t, "generic error", batch.LastWriteError)
+	assert.Equal(t, 3, batch.WriteRetries)
+	assert.True(t, batch.IsParked)
+	assert.True(t, batch.IsPublished())
+}
+
+func TestWriter_Write(t *testing.T) {
+	w := NewWriter(WriterConfig{})
+	assert.ErrorIs(t, w.Write(context.TODO(), nil), streams.ErrEmptyMessage)
+	assert.ErrorIs(t, w.Write(context.TODO(), []streams.Message{{ID: "1"}}), persistence.ErrTransactionContextNotFound)
+
+	tx := NewTransaction()
+	ctx := persistence.SetTransactionContext(context.TODO(), persistence.TransactionContext[*Transaction]{
+		TransactionID: "123",
+		Tx:            tx,
+	})
+	require.NoError(t, w.Write(ctx, []streams.Message{{ID: "1", StreamName: "foo"}}))
+	require.Len(t, tx.Items(), 1)
+	put := tx.Items()[0].Put
+	assert.Equal(t, egress.DefaultEgressTableName, *put.TableName)
+	assert.Equal(t, "123", getStringAttribute(put.Item, batchIDAttribute))
+	assert.Equal(t, int64(1), getIntAttribute(put.Item, messageCountAttribute))
+}
+
+func TestTransaction_Commit(t *testing.T) {
+	tx := NewTransaction()
+	assert.NoError(t, tx.Commit(context.TODO(), nil, ""))
+
+	for i := 0; i <= MaxTransactionItems; i++ {
+		tx.Append(types.TransactWriteItem{})
+	}
+	assert.ErrorIs(t, tx.Commit(context.TODO(), nil, ""), ErrTransactionTooLarge)
+}
+
+func TestNewBatchUpdateErr(t *testing.T) {
+	genericErr := errors.New("generic error")
+	tests := []struct {
+		name  string
+		inErr error
+		exp   error
+	}{
+		{name: "nil", inErr: nil, exp: nil},
+		{name: "generic", inErr: genericErr, exp: genericErr},
+		{
+			name:  "condition failed",
+			inErr: fmt.Errorf("operation error: %w", &types.ConditionalCheckFailedException{}),
+			exp:   ErrBatchNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, newBatchUpdateErr(tt.inErr))
+		})
+	}
+}
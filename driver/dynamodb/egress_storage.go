@@ -0,0 +1,243 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxBatchWriteItems maximum count of items a BatchWriteItem call may hold.
+const maxBatchWriteItems = 25
+
+// A EgressStorage is an Amazon DynamoDB implementation of egress.Storage. Enables interaction with
+// a stream egress table (aka. outbox).
+type EgressStorage struct {
+	client   *dynamodb.Client
+	cfg      egress.StorageConfig
+	tableRef *string
+}
+
+var _ egress.Storage = EgressStorage{}
+
+// NewEgressStorage allocates a new EgressStorage instance with default configuration but open to apply any egress.StorageOption(s).
+func NewEgressStorage(client *dynamodb.Client, opts ...egress.StorageOption) EgressStorage {
+	baseOpts := egress.StorageConfig{
+		TableName: egress.DefaultEgressTableName,
+	}
+	for _, o := range opts {
+		o.Apply(&baseOpts)
+	}
+	return NewEgressStorageWithConfig(client, baseOpts)
+}
+
+// NewEgressStorageWithConfig allocates a new EgressStorage instance with a specific egress.StorageConfig.
+func NewEgressStorageWithConfig(client *dynamodb.Client, cfg egress.StorageConfig) EgressStorage {
+	return EgressStorage{
+		client:   client,
+		cfg:      cfg,
+		tableRef: aws.String(cfg.TableName),
+	}
+}
+
+// batchExistsCondition avoids upserting batches removed from the egress table.
+var batchExistsCondition = aws.String("attribute_exists(" + batchIDAttribute + ")")
+
+func newBatchKey(batchID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		batchIDAttribute: &types.AttributeValueMemberS{
+			Value: batchID,
+		},
+	}
+}
+
+func (e EgressStorage) GetBatch(ctx context.Context, batchID string) (egress.Batch, error) {
+	out, err := e.client.GetItem(ctx, &dynamodb.GetItemInput{
+		Key:            newBatchKey(batchID),
+		TableName:      e.tableRef,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return egress.Batch{}, err
+	} else if len(out.Item) == 0 {
+		return egress.Batch{}, ErrBatchNotFound
+	}
+	return newBatch(out.Item), nil
+}
+
+func (e EgressStorage) Commit(ctx context.Context, batchID string) error {
+	if e.cfg.RetainPublished {
+		_, err := e.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			Key:                 newBatchKey(batchID),
+			TableName:           e.tableRef,
+			ConditionExpression: batchExistsCondition,
+			UpdateExpression:    aws.String("SET " + publishTimeAttribute + " = :publish_time"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":publish_time": newTimeAttribute(time.Now().UTC()),
+			},
+		})
+		return newBatchUpdateErr(err)
+	}
+
+	_, err := e.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		Key:       newBatchKey(batchID),
+		TableName: e.tableRef,
+	})
+	return err
+}
+
+func (e EgressStorage) RecordFailure(ctx context.Context, batchID string, failure error) (bool, error) {
+	var lastErr string
+	if failure != nil {
+		lastErr = failure.Error()
+	}
+	out, err := e.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		Key:                 newBatchKey(batchID),
+		TableName:           e.tableRef,
+		ConditionExpression: batchExistsCondition,
+		UpdateExpression: aws.String("SET " + lastWriteErrorAttribute + " = :last_error ADD " +
+			writeRetriesAttribute + " :one"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":last_error": &types.AttributeValueMemberS{Value: lastErr},
+			":one":        &types.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		return false, newBatchUpdateErr(err)
+	}
+
+	isParked := getBoolAttribute(out.Attributes, parkedAttribute)
+	writeRetries := getIntAttribute(out.Attributes, writeRetriesAttribute)
+	shouldPark := errors.Is(failure, streams.ErrUnrecoverable) ||
+		(e.cfg.MaxAttempts > 0 && writeRetries >= int64(e.cfg.MaxAttempts))
+	if isParked || !shouldPark {
+		return isParked, nil
+	}
+
+	_, err = e.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		Key:                 newBatchKey(batchID),
+		TableName:           e.tableRef,
+		ConditionExpression: batchExistsCondition,
+		UpdateExpression:    aws.String("SET " + parkedAttribute + " = :parked"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":parked": &types.AttributeValueMemberBOOL{Value: true},
+		},
+	})
+	if err != nil {
+		return false, newBatchUpdateErr(err)
+	}
+	return true, nil
+}
+
+// newBatchUpdateErr maps failed batch existence conditions to ErrBatchNotFound.
+func newBatchUpdateErr(err error) error {
+	if isConditionalCheckFailed(err) {
+		return ErrBatchNotFound
+	}
+	return err
+}
+
+// PurgePublished removes published batches older than threshold. Amazon DynamoDB has no conditional bulk deletes,
+// thus the egress table is scanned.
+func (e EgressStorage) PurgePublished(ctx context.Context, threshold time.Time) (int64, error) {
+	paginator := dynamodb.NewScanPaginator(e.client, &dynamodb.ScanInput{
+		TableName:            e.tableRef,
+		ConsistentRead:       aws.Bool(true),
+		ProjectionExpression: aws.String(batchIDAttribute),
+		FilterExpression:     aws.String(publishTimeAttribute + " < :threshold"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":threshold": newTimeAttribute(threshold),
+		},
+	})
+
+	var total int64
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return total, err
+		}
+
+		for offset := 0; offset < len(page.Items); offset += maxBatchWriteItems {
+			end := offset + maxBatchWriteItems
+			if end > len(page.Items) {
+				end = len(page.Items)
+			}
+			if err = e.deleteItems(ctx, page.Items[offset:end]); err != nil {
+				return total, err
+			}
+			total += int64(end - offset)
+		}
+	}
+	return total, nil
+}
+
+// ListPending retrieves pending batches scanning the egress table, thus batches are not sorted. As Amazon DynamoDB
+// evaluates limit before filtering items, pages might hold fewer batches than limit even if batches are left.
+func (e EgressStorage) ListPending(ctx context.Context, threshold time.Time, cursor string,
+	limit int) ([]egress.Batch, string, error) {
+	in := &dynamodb.ScanInput{
+		TableName:      e.tableRef,
+		ConsistentRead: aws.Bool(true),
+		Limit:          aws.Int32(int32(limit)),
+		FilterExpression: aws.String(parkedAttribute + " = :parked AND attribute_not_exists(" + publishTimeAttribute +
+			") AND " + insertTimeAttribute + " < :threshold"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":parked":    &types.AttributeValueMemberBOOL{Value: false},
+			":threshold": newTimeAttribute(threshold),
+		},
+	}
+	if cursor != "" {
+		in.ExclusiveStartKey = newBatchKey(cursor)
+	}
+	out, err := e.client.Scan(ctx, in)
+	if err != nil {
+		return nil, "", err
+	}
+
+	batches := make([]egress.Batch, 0, len(out.Items))
+	for _, item := range out.Items {
+		batches = append(batches, newBatch(item))
+	}
+	return batches, getStringAttribute(out.LastEvaluatedKey, batchIDAttribute), nil
+}
+
+func (e EgressStorage) deleteItems(ctx context.Context, items []map[string]types.AttributeValue) error {
+	reqs := make([]types.WriteRequest, 0, len(items))
+	for _, item := range items {
+		reqs = append(reqs, types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{
+				Key: item,
+			},
+		})
+	}
+
+	pending := map[string][]types.WriteRequest{
+		e.cfg.TableName: reqs,
+	}
+	for attempt := 1; len(pending) > 0; attempt++ {
+		out, err := e.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: pending,
+		})
+		if err != nil {
+			return err
+		}
+		pending = out.UnprocessedItems
+		if len(pending) == 0 {
+			break
+		}
+
+		// unprocessed items are caused by throttling, thus backing off before retrying.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond * 50 * time.Duration(attempt)):
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+//go:build integration
+
+package dynamodb_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsdynamo "github.com/alexandria-oss/streams/driver/dynamodb"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type egressStorageSuite struct {
+	suite.Suite
+	client    *dynamodb.Client
+	tableName string
+}
+
+func TestEgressStorage(t *testing.T) {
+	suite.Run(t, &egressStorageSuite{})
+}
+
+func (s *egressStorageSuite) SetupSuite() {
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("fake", "fake", "TOKEN")),
+		config.WithRegion("us-east-1"),
+		config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(
+			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+				return aws.Endpoint{
+					URL:           "http://localhost:8001",
+					PartitionID:   "aws",
+					SigningRegion: "us-east-1",
+				}, nil
+			})),
+	)
+	s.Require().NoError(err)
+	s.client = dynamodb.NewFromConfig(cfg)
+	s.tableName = "streams-egress"
+	_, err = s.client.CreateTable(context.TODO(), &dynamodb.CreateTableInput{
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("batch_id"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("batch_id"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		TableName:   aws.String(s.tableName),
+		BillingMode: types.BillingModePayPerRequest,
+		StreamSpecification: &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: types.StreamViewTypeKeysOnly,
+		},
+	})
+	if err != nil && !strings.Contains(err.Error(), "ResourceInUseException") {
+		s.Fail(err.Error())
+	}
+}
+
+func (s *egressStorageSuite) TearDownSuite() {
+	_, err := s.client.DeleteTable(context.TODO(), &dynamodb.DeleteTableInput{
+		TableName: aws.String(s.tableName),
+	})
+	s.Assert().NoError(err)
+}
+
+func (s *egressStorageSuite) TestStorage() {
+	storage := streamsdynamo.NewEgressStorage(s.client, egress.WithEgressTable(s.tableName),
+		egress.WithMaxAttempts(2), egress.WithPublishedRetention(true))
+	writer := streamsdynamo.NewWriter(streamsdynamo.WriterConfig{
+		TableName: s.tableName,
+	})
+
+	ctx := context.TODO()
+	tx := streamsdynamo.NewTransaction()
+	scopedCtx := persistence.SetTransactionContext(ctx, persistence.TransactionContext[*streamsdynamo.Transaction]{
+		TransactionID: "123",
+		Tx:            tx,
+	})
+	s.Require().NoError(writer.Write(scopedCtx, []streams.Message{{ID: "1", StreamName: "foo"}}))
+	s.Require().NoError(tx.Commit(ctx, s.client, "123"))
+
+	batch, err := storage.GetBatch(ctx, "123")
+	s.Require().NoError(err)
+	s.Assert().Equal("123", batch.BatchID)
+	s.Assert().False(batch.IsParked)
+
+	pending, cursor, err := storage.ListPending(ctx, time.Now().Add(time.Minute), "", 10)
+	s.Require().NoError(err)
+	s.Assert().Len(pending, 1)
+	s.Assert().Empty(cursor)
+
+	isParked, err := storage.RecordFailure(ctx, "123", errors.New("generic error"))
+	s.Require().NoError(err)
+	s.Assert().False(isParked)
+	isParked, err = storage.RecordFailure(ctx, "123", errors.New("generic error"))
+	s.Require().NoError(err)
+	s.Assert().True(isParked)
+	pending, _, err = storage.ListPending(ctx, time.Now().Add(time.Minute), "", 10)
+	s.Require().NoError(err)
+	s.Assert().Empty(pending)
+
+	s.Require().NoError(storage.Commit(ctx, "123"))
+	total, err := storage.PurgePublished(ctx, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.Assert().Equal(int64(1), total)
+
+	_, err = storage.GetBatch(ctx, "123")
+	s.Assert().ErrorIs(err, streamsdynamo.ErrBatchNotFound)
+
+	// missing batches must not be upserted
+	s.Assert().ErrorIs(storage.Commit(ctx, "123"), streamsdynamo.ErrBatchNotFound)
+	isParked, err = storage.RecordFailure(ctx, "123", errors.New("generic error"))
+	s.Assert().ErrorIs(err, streamsdynamo.ErrBatchNotFound)
+	s.Assert().False(isParked)
+	_, err = storage.GetBatch(ctx, "123")
+	s.Assert().ErrorIs(err, streamsdynamo.ErrBatchNotFound)
+}
@@ -0,0 +1,10 @@
+package dynamodb
+
+import "errors"
+
+var (
+	ErrBatchNotFound       = errors.New("streams.dynamodb: batch not found")
+	ErrTransactionTooLarge = errors.New("streams.dynamodb: transaction exceeds maximum count of items")
+	ErrLeaseNotAcquired    = errors.New("streams.dynamodb: lease is owned by another reader")
+	ErrLeaseLost           = errors.New("streams.dynamodb: lease is no longer owned by reader")
+)
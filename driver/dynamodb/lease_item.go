@@ -0,0 +1,63 @@
+package dynamodb
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Lease table attributes. Time values are stored as Unix time in nanoseconds.
+const (
+	leaseStreamAttribute     = "stream_name"
+	leaseShardAttribute      = "shard_id"
+	leaseParentsAttribute    = "parent_shard_ids"
+	leaseOwnerAttribute      = "owner_id"
+	leaseCheckpointAttribute = "checkpoint"
+	leaseExpirationAttribute = "lease_expiration"
+	leaseShardEndedAttribute = "shard_ended"
+	leaseUpdateTimeAttribute = "update_time"
+)
+
+// leaseShardEndedCheckpoint checkpoint of leases whose shard records were all processed.
+const leaseShardEndedCheckpoint = "SHARD_END"
+
+func newLeaseKey(stream, shardID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		leaseStreamAttribute: &types.AttributeValueMemberS{
+			Value: stream,
+		},
+		leaseShardAttribute: &types.AttributeValueMemberS{
+			Value: shardID,
+		},
+	}
+}
+
+func newLeaseItem(lease Lease, updateTime time.Time) map[string]types.AttributeValue {
+	item := newLeaseKey(lease.StreamName, lease.ShardID)
+	if len(lease.ParentShardIDs) > 0 {
+		item[leaseParentsAttribute] = &types.AttributeValueMemberSS{
+			Value: lease.ParentShardIDs,
+		}
+	}
+	item[leaseShardEndedAttribute] = &types.AttributeValueMemberBOOL{
+		Value: false,
+	}
+	item[leaseUpdateTimeAttribute] = newTimeAttribute(updateTime)
+	return item
+}
+
+func newLease(item map[string]types.AttributeValue) Lease {
+	var parents []string
+	if val, ok := item[leaseParentsAttribute].(*types.AttributeValueMemberSS); ok {
+		parents = val.Value
+	}
+	return Lease{
+		StreamName:     getStringAttribute(item, leaseStreamAttribute),
+		ShardID:        getStringAttribute(item, leaseShardAttribute),
+		ParentShardIDs: parents,
+		Owner:          getStringAttribute(item, leaseOwnerAttribute),
+		Checkpoint:     getStringAttribute(item, leaseCheckpointAttribute),
+		ExpirationTime: getTimeAttribute(item, leaseExpirationAttribute),
+		IsShardEnded:   getBoolAttribute(item, leaseShardEndedAttribute),
+	}
+}
@@ -0,0 +1,80 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLease(t *testing.T) {
+	item := newLeaseItem(Lease{
+		StreamName:     "foo",
+		ShardID:        "shardId-000000000002",
+		ParentShardIDs: []string{"shardId-000000000000", "shardId-000000000001"},
+		Owner:          "worker-a", // ownership is set by LeaseStorage.AcquireLease only
+	}, time.Now())
+	assert.Equal(t, Lease{
+		StreamName:     "foo",
+		ShardID:        "shardId-000000000002",
+		ParentShardIDs: []string{"shardId-000000000000", "shardId-000000000001"},
+	}, newLease(item))
+
+	expirationTime := time.Now().UTC()
+	item[leaseOwnerAttribute] = &types.AttributeValueMemberS{Value: "worker-a"}
+	item[leaseCheckpointAttribute] = &types.AttributeValueMemberS{Value: "49590338271490256608559692538361571095921575989136588898"}
+	item[leaseExpirationAttribute] = newTimeAttribute(expirationTime)
+	lease := newLease(item)
+	assert.Equal(t, "worker-a", lease.Owner)
+	assert.Equal(t, "49590338271490256608559692538361571095921575989136588898", lease.Checkpoint)
+	assert.Equal(t, expirationTime, lease.ExpirationTime)
+	assert.False(t, lease.IsShardEnded)
+}
+
+func TestLease_IsAvailable(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		lease Lease
+		owner string
+		exp   bool
+	}{
+		{
+			name:  "unowned",
+			lease: Lease{},
+			owner: "worker-a",
+			exp:   true,
+		},
+		{
+			name:  "owned",
+			lease: Lease{Owner: "worker-b", ExpirationTime: now.Add(time.Minute)},
+			owner: "worker-a",
+			exp:   false,
+		},
+		{
+			name:  "renewal",
+			lease: Lease{Owner: "worker-a", ExpirationTime: now.Add(time.Minute)},
+			owner: "worker-a",
+			exp:   true,
+		},
+		{
+			name:  "expired",
+			lease: Lease{Owner: "worker-b", ExpirationTime: now.Add(-time.Minute)},
+			owner: "worker-a",
+			exp:   true,
+		},
+		{
+			name:  "shard ended",
+			lease: Lease{IsShardEnded: true},
+			owner: "worker-a",
+			exp:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, tt.lease.IsAvailable(tt.owner, now))
+		})
+	}
+}
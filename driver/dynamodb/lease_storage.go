@@ -0,0 +1,169 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DefaultLeaseTableName default name of the lease table.
+const DefaultLeaseTableName = "streams_leases"
+
+// A Lease is the ownership record of a stream shard (e.g. an Amazon Kinesis shard). A reader holds a lease until it
+// expires, and it stores the sequence number of the last processed record as checkpoint.
+type Lease struct {
+	StreamName     string
+	ShardID        string
+	ParentShardIDs []string  // Shards this shard was split from or merged from (i.e. resharding).
+	Owner          string    // Identifier of the reader holding the lease. Empty if released.
+	Checkpoint     string    // Sequence number of the last processed record.
+	ExpirationTime time.Time // Time when the lease is available to other readers if not renewed.
+	IsShardEnded   bool      // The shard is closed, and every record was processed.
+}
+
+// IsAvailable indicates if the lease may be acquired by owner at t.
+func (l Lease) IsAvailable(owner string, t time.Time) bool {
+	return !l.IsShardEnded && (l.Owner == "" || l.Owner == owner || !l.ExpirationTime.After(t))
+}
+
+// LeaseStorageConfig is the configuration schema for Amazon DynamoDB LeaseStorage.
+type LeaseStorageConfig struct {
+	TableName string // table to store leases (default DefaultLeaseTableName).
+}
+
+// A LeaseStorage is an Amazon DynamoDB lease table used to coordinate readers of sharded streams (e.g.
+// Amazon Kinesis). Ownership changes are conditional writes, so a single reader holds a lease at a time.
+type LeaseStorage struct {
+	client   *dynamodb.Client
+	cfg      LeaseStorageConfig
+	tableRef *string
+}
+
+// NewLeaseStorage allocates a new LeaseStorage instance.
+func NewLeaseStorage(cfg LeaseStorageConfig, client *dynamodb.Client) LeaseStorage {
+	if cfg.TableName == "" {
+		cfg.TableName = DefaultLeaseTableName
+	}
+	return LeaseStorage{
+		client:   client,
+		cfg:      cfg,
+		tableRef: aws.String(cfg.TableName),
+	}
+}
+
+func isConditionalCheckFailed(err error) bool {
+	var errCond *types.ConditionalCheckFailedException
+	return errors.As(err, &errCond)
+}
+
+// CreateLease writes an unowned lease if it does not exist already.
+func (s LeaseStorage) CreateLease(ctx context.Context, lease Lease) error {
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		Item:                newLeaseItem(lease, time.Now().UTC()),
+		TableName:           s.tableRef,
+		ConditionExpression: aws.String("attribute_not_exists(" + leaseShardAttribute + ")"),
+	})
+	if isConditionalCheckFailed(err) {
+		return nil
+	}
+	return err
+}
+
+// ListLeases retrieves every lease of stream.
+func (s LeaseStorage) ListLeases(ctx context.Context, stream string) ([]Lease, error) {
+	paginator := dynamodb.NewQueryPaginator(s.client, &dynamodb.QueryInput{
+		TableName:              s.tableRef,
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String(leaseStreamAttribute + " = :stream"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":stream": &types.AttributeValueMemberS{Value: stream},
+		},
+	})
+
+	leases := make([]Lease, 0)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			leases = append(leases, newLease(item))
+		}
+	}
+	return leases, nil
+}
+
+// AcquireLease sets owner as lease owner until expirationTime. Leases are acquired if they are unowned, expired or
+// already owned by owner (i.e. renewal), otherwise ErrLeaseNotAcquired is returned.
+func (s LeaseStorage) AcquireLease(ctx context.Context, stream, shardID, owner string, expirationTime time.Time) error {
+	now := time.Now().UTC()
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		Key:       newLeaseKey(stream, shardID),
+		TableName: s.tableRef,
+		UpdateExpression: aws.String("SET " + leaseOwnerAttribute + " = :owner, " + leaseExpirationAttribute +
+			" = :expiration, " + leaseUpdateTimeAttribute + " = :now"),
+		ConditionExpression: aws.String("attribute_exists(" + leaseShardAttribute + ") AND " +
+			leaseShardEndedAttribute + " = :false AND (attribute_not_exists(" + leaseOwnerAttribute + ") OR " +
+			leaseOwnerAttribute + " = :owner OR " + leaseExpirationAttribute + " <= :now)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner":      &types.AttributeValueMemberS{Value: owner},
+			":expiration": newTimeAttribute(expirationTime),
+			":now":        newTimeAttribute(now),
+			":false":      &types.AttributeValueMemberBOOL{Value: false},
+		},
+	})
+	if isConditionalCheckFailed(err) {
+		return ErrLeaseNotAcquired
+	}
+	return err
+}
+
+// Checkpoint stores sequenceNumber as the lease checkpoint. Returns ErrLeaseLost if owner no longer holds the lease.
+func (s LeaseStorage) Checkpoint(ctx context.Context, stream, shardID, owner, sequenceNumber string) error {
+	return s.updateOwned(ctx, stream, shardID, owner, "SET "+leaseCheckpointAttribute+" = :checkpoint, "+
+		leaseUpdateTimeAttribute+" = :now", map[string]types.AttributeValue{
+		":checkpoint": &types.AttributeValueMemberS{Value: sequenceNumber},
+	})
+}
+
+// EndShard marks the lease shard as ended and releases the lease, so readers may start reading child shards.
+// Returns ErrLeaseLost if owner no longer holds the lease.
+func (s LeaseStorage) EndShard(ctx context.Context, stream, shardID, owner string) error {
+	return s.updateOwned(ctx, stream, shardID, owner, "SET "+leaseCheckpointAttribute+" = :checkpoint, "+
+		leaseShardEndedAttribute+" = :true, "+leaseUpdateTimeAttribute+" = :now REMOVE "+leaseOwnerAttribute+", "+
+		leaseExpirationAttribute, map[string]types.AttributeValue{
+		":checkpoint": &types.AttributeValueMemberS{Value: leaseShardEndedCheckpoint},
+		":true":       &types.AttributeValueMemberBOOL{Value: true},
+	})
+}
+
+// ReleaseLease removes owner from the lease, so other readers may acquire it right away. Returns ErrLeaseLost if
+// owner no longer holds the lease.
+func (s LeaseStorage) ReleaseLease(ctx context.Context, stream, shardID, owner string) error {
+	return s.updateOwned(ctx, stream, shardID, owner, "SET "+leaseUpdateTimeAttribute+" = :now REMOVE "+
+		leaseOwnerAttribute+", "+leaseExpirationAttribute, nil)
+}
+
+func (s LeaseStorage) updateOwned(ctx context.Context, stream, shardID, owner, expr string,
+	values map[string]types.AttributeValue) error {
+	if values == nil {
+		values = make(map[string]types.AttributeValue, 2)
+	}
+	values[":owner"] = &types.AttributeValueMemberS{Value: owner}
+	values[":now"] = newTimeAttribute(time.Now().UTC())
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		Key:                       newLeaseKey(stream, shardID),
+		TableName:                 s.tableRef,
+		UpdateExpression:          aws.String(expr),
+		ConditionExpression:       aws.String(leaseOwnerAttribute + " = :owner"),
+		ExpressionAttributeValues: values,
+	})
+	if isConditionalCheckFailed(err) {
+		return ErrLeaseLost
+	}
+	return err
+}
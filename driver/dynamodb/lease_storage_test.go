@@ -0,0 +1,131 @@
+//go:build integration
+
+package dynamodb_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	streamsdynamo "github.com/alexandria-oss/streams/driver/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type leaseStorageSuite struct {
+	suite.Suite
+	client    *dynamodb.Client
+	tableName string
+}
+
+func TestLeaseStorage(t *testing.T) {
+	suite.Run(t, &leaseStorageSuite{})
+}
+
+func (s *leaseStorageSuite) SetupSuite() {
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("fake", "fake", "TOKEN")),
+		config.WithRegion("us-east-1"),
+		config.WithEndpointResolverWithOptions(aws.EndpointResolverWithOptionsFunc(
+			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+				return aws.Endpoint{
+					URL:           "http://localhost:8001",
+					PartitionID:   "aws",
+					SigningRegion: "us-east-1",
+				}, nil
+			})),
+	)
+	s.Require().NoError(err)
+	s.client = dynamodb.NewFromConfig(cfg)
+	s.tableName = "streams-leases"
+	_, err = s.client.CreateTable(context.TODO(), &dynamodb.CreateTableInput{
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("stream_name"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("shard_id"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("stream_name"),
+				KeyType:       types.KeyTypeHash,
+			},
+			{
+				AttributeName: aws.String("shard_id"),
+				KeyType:       types.KeyTypeRange,
+			},
+		},
+		TableName:   aws.String(s.tableName),
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil && !strings.Contains(err.Error(), "ResourceInUseException") {
+		s.Fail(err.Error())
+	}
+}
+
+func (s *leaseStorageSuite) TearDownSuite() {
+	_, err := s.client.DeleteTable(context.TODO(), &dynamodb.DeleteTableInput{
+		TableName: aws.String(s.tableName),
+	})
+	s.Assert().NoError(err)
+}
+
+func (s *leaseStorageSuite) TestLeaseLifecycle() {
+	ctx := context.TODO()
+	storage := streamsdynamo.NewLeaseStorage(streamsdynamo.LeaseStorageConfig{
+		TableName: s.tableName,
+	}, s.client)
+	stream := "lease-lifecycle"
+	shardID := "shardId-000000000000"
+	s.Require().NoError(storage.CreateLease(ctx, streamsdynamo.Lease{StreamName: stream, ShardID: shardID}))
+	// leases are created once
+	s.Require().NoError(storage.CreateLease(ctx, streamsdynamo.Lease{StreamName: stream, ShardID: shardID}))
+
+	expiration := time.Now().Add(time.Minute)
+	s.Require().NoError(storage.AcquireLease(ctx, stream, shardID, "worker-a", expiration))
+	s.Assert().ErrorIs(storage.AcquireLease(ctx, stream, shardID, "worker-b", expiration),
+		streamsdynamo.ErrLeaseNotAcquired)
+	s.Assert().ErrorIs(storage.Checkpoint(ctx, stream, shardID, "worker-b", "1"), streamsdynamo.ErrLeaseLost)
+	s.Require().NoError(storage.Checkpoint(ctx, stream, shardID, "worker-a", "1"))
+
+	leases, err := storage.ListLeases(ctx, stream)
+	s.Require().NoError(err)
+	s.Require().Len(leases, 1)
+	s.Assert().Equal("worker-a", leases[0].Owner)
+	s.Assert().Equal("1", leases[0].Checkpoint)
+
+	s.Require().NoError(storage.ReleaseLease(ctx, stream, shardID, "worker-a"))
+	s.Require().NoError(storage.AcquireLease(ctx, stream, shardID, "worker-b", expiration))
+	s.Require().NoError(storage.EndShard(ctx, stream, shardID, "worker-b"))
+	s.Assert().ErrorIs(storage.AcquireLease(ctx, stream, shardID, "worker-a", expiration),
+		streamsdynamo.ErrLeaseNotAcquired)
+
+	leases, err = storage.ListLeases(ctx, stream)
+	s.Require().NoError(err)
+	s.Require().Len(leases, 1)
+	s.Assert().Empty(leases[0].Owner)
+	s.Assert().True(leases[0].IsShardEnded)
+}
+
+func (s *leaseStorageSuite) TestAcquireExpiredLease() {
+	ctx := context.TODO()
+	storage := streamsdynamo.NewLeaseStorage(streamsdynamo.LeaseStorageConfig{
+		TableName: s.tableName,
+	}, s.client)
+	stream := "lease-expired"
+	shardID := "shardId-000000000000"
+	s.Require().NoError(storage.CreateLease(ctx, streamsdynamo.Lease{StreamName: stream, ShardID: shardID}))
+	s.Require().NoError(storage.AcquireLease(ctx, stream, shardID, "worker-a", time.Now().Add(-time.Second)))
+	s.Require().NoError(storage.AcquireLease(ctx, stream, shardID, "worker-b", time.Now().Add(time.Minute)))
+	s.Assert().ErrorIs(storage.AcquireLease(ctx, "lease-missing", shardID, "worker-a", time.Now()),
+		streamsdynamo.ErrLeaseNotAcquired)
+}
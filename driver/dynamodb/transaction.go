@@ -0,0 +1,63 @@
+package dynamodb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// MaxTransactionItems maximum count of items an Amazon DynamoDB transaction (TransactWriteItems) may hold.
+const MaxTransactionItems = 100
+
+// A Transaction buffers Amazon DynamoDB write operations to be executed atomically through TransactWriteItems.
+// Systems append their own operations along the ones appended by Writer instances, then they call Transaction.Commit.
+//
+// Use along transaction context functions (persistence.SetTransactionContext, persistence.GetTransactionContext).
+type Transaction struct {
+	mu    sync.Mutex
+	items []types.TransactWriteItem
+}
+
+// NewTransaction allocates an empty Transaction.
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Append appends write operations to the Transaction.
+func (t *Transaction) Append(items ...types.TransactWriteItem) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.items = append(t.items, items...)
+}
+
+// Items retrieves the buffered write operations.
+func (t *Transaction) Items() []types.TransactWriteItem {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.items
+}
+
+// Commit executes buffered write operations atomically. clientToken (optional) makes the call idempotent for
+// ten minutes, use TransactionContext.TransactionID as token to avoid duplicated writes on retries.
+//
+// Returns ErrTransactionTooLarge if the Transaction holds more than MaxTransactionItems.
+func (t *Transaction) Commit(ctx context.Context, client *dynamodb.Client, clientToken string) error {
+	items := t.Items()
+	if len(items) == 0 {
+		return nil
+	} else if len(items) > MaxTransactionItems {
+		return ErrTransactionTooLarge
+	}
+
+	in := &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	}
+	if clientToken != "" {
+		in.ClientRequestToken = aws.String(clientToken)
+	}
+	_, err := client.TransactWriteItems(ctx, in)
+	return err
+}
@@ -0,0 +1,82 @@
+package dynamodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// WriterConfig is the configuration schema for Amazon DynamoDB Writer.
+type WriterConfig struct {
+	TableName string      // table to write message batches to be later published (default egress.DefaultEgressTableName).
+	Codec     codec.Codec // used to encode message batches (default codec.ProtocolBuffers).
+}
+
+// A Writer is an Amazon DynamoDB writer.
+// This specific kind of streams.Writer is used by systems implementing the transactional outbox messaging pattern.
+// More in depth, a Writer instance appends a put operation into a Transaction, so message batches are written into
+// an <<egress table>> atomically along the system's own operations (TransactWriteItems).
+//
+// Writer instances MUST be used along transaction context functions
+// (persistence.SetTransactionContext, persistence.GetTransactionContext) holding a *Transaction instance.
+// If no context is found, then Writer.Write will fail.
+//
+// Note: Amazon DynamoDB items have a maximum size of 400 KB.
+type Writer struct {
+	cfg      WriterConfig
+	tableRef *string
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates a new Writer instance.
+func NewWriter(cfg WriterConfig) Writer {
+	if cfg.TableName == "" {
+		cfg.TableName = egress.DefaultEgressTableName
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = codec.ProtocolBuffers{}
+	}
+	return Writer{
+		cfg:      cfg,
+		tableRef: aws.String(cfg.TableName),
+	}
+}
+
+// Write appends a put operation of the message batch into the Transaction found in the transaction context.
+//
+// Batch identifier will be taken from TransactionContext.TransactionID.
+func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) error {
+	if len(msgBatch) == 0 {
+		return streams.ErrEmptyMessage
+	}
+
+	txCtx, err := persistence.GetTransactionContext[*Transaction](ctx)
+	if err != nil {
+		return err
+	}
+
+	var msgBatchAny any = msgBatch
+	if w.cfg.Codec.ApplicationType() == codec.ProtocolBuffersApplicationType {
+		msgBatchAny = persistence.NewTransportMessageBatch(msgBatch)
+	}
+	encodedData, err := w.cfg.Codec.Encode(msgBatchAny)
+	if err != nil {
+		return err
+	}
+
+	txCtx.Tx.Append(types.TransactWriteItem{
+		Put: &types.Put{
+			Item:                newBatchItem(txCtx.TransactionID, len(msgBatch), encodedData, time.Now().UTC()),
+			TableName:           w.tableRef,
+			ConditionExpression: aws.String("attribute_not_exists(" + batchIDAttribute + ")"),
+		},
+	})
+	return nil
+}
@@ -0,0 +1,17 @@
+package pubsub
+
+const (
+	// HeaderMessageID is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the identifier assigned by Pub/Sub to the message once published.
+	HeaderMessageID = "pubsub-message-id"
+	// HeaderPublishTime is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the timestamp in Unix milliseconds when Pub/Sub received the message.
+	HeaderPublishTime = "pubsub-publish-time"
+	// HeaderSubscription is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the subscription the message was received from.
+	HeaderSubscription = "pubsub-subscription"
+	// HeaderDeliveryAttempt is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the count of delivery attempts of the message (i.e. 1 on the first delivery). Only set
+	// if the subscription has a dead-letter policy.
+	HeaderDeliveryAttempt = "pubsub-delivery-attempt"
+)
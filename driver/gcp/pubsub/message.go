@@ -0,0 +1,56 @@
+package pubsub
+
+import (
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/gcp"
+)
+
+func newMessageAttributes(msg streams.Message) map[string]string {
+	attrs := make(map[string]string, len(msg.Headers)+5)
+	attrs[gcp.HeaderMessageID] = msg.ID
+	attrs[gcp.HeaderStreamName] = msg.StreamName
+	attrs[gcp.HeaderStreamKey] = msg.StreamKey
+	attrs[gcp.HeaderContentType] = msg.ContentType
+	attrs[gcp.HeaderMessageTime] = strconv.FormatInt(msg.Time.UnixMilli(), 10)
+	for k, v := range msg.Headers {
+		attrs[k] = v
+	}
+	return attrs
+}
+
+func unmarshalMessage(rawMsg *pubsub.Message) streams.Message {
+	// 4 as pubsub.Message has 4 fields to be appended into headers
+	headers := make(map[string]string, len(rawMsg.Attributes)+4)
+	headers[HeaderMessageID] = rawMsg.ID
+	headers[HeaderPublishTime] = strconv.FormatInt(rawMsg.PublishTime.UnixMilli(), 10)
+	if rawMsg.DeliveryAttempt != nil {
+		headers[HeaderDeliveryAttempt] = strconv.Itoa(*rawMsg.DeliveryAttempt)
+	}
+	msg := streams.Message{
+		StreamKey: rawMsg.OrderingKey,
+		Headers:   headers,
+		Data:      rawMsg.Data,
+	}
+	for key, val := range rawMsg.Attributes {
+		switch key {
+		case gcp.HeaderMessageID:
+			msg.ID = val
+		case gcp.HeaderStreamName:
+			msg.StreamName = val
+		case gcp.HeaderStreamKey:
+			msg.StreamKey = val
+		case gcp.HeaderContentType:
+			msg.ContentType = val
+		case gcp.HeaderMessageTime:
+			timeMilli, _ := strconv.ParseInt(val, 10, 64)
+			msg.Time = time.UnixMilli(timeMilli)
+		default:
+			msg.Headers[key] = val
+		}
+	}
+	return msg
+}
@@ -0,0 +1,112 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/internal/genericutil"
+)
+
+// ReaderTaskSubscriptionKey is the argument key to set up the subscription a reader receives messages from. If not
+// set, the subscription named after the task stream is used.
+const ReaderTaskSubscriptionKey string = "gcp-pubsub-subscription"
+
+// ReaderConfig is the Google Cloud Pub/Sub reader configuration schema.
+type ReaderConfig struct {
+	Logger                 *log.Logger   // Logging instance preferably with log level at <<info>>.
+	ErrorLogger            *log.Logger   // Logging instance preferably with log level at <<error>>.
+	MaxOutstandingMessages int           // Maximum count of unacknowledged messages handled concurrently.
+	NumGoroutines          int           // Count of streaming pull connections (pubsub.ReceiveSettings).
+	HandlerTimeout         time.Duration // Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	// Maximum duration the ack deadline of a message is extended while its handler runs. Default is
+	// HandlerTimeout.
+	MaxExtension time.Duration
+}
+
+// Reader is the Google Cloud Pub/Sub streams.Reader implementation using streaming pull.
+//
+// Messages are acknowledged once their handler succeeded, otherwise they are negatively acknowledged so Pub/Sub
+// delivers them again following the subscription retry policy (or forwards them to its dead-letter topic once
+// the maximum delivery attempts are reached). Ack deadlines are extended while handlers run. If the subscription
+// has exactly-once delivery enabled, the reader waits until Pub/Sub confirms each acknowledgement.
+type Reader struct {
+	config ReaderConfig
+	client *pubsub.Client
+}
+
+var _ streams.Reader = Reader{}
+
+// NewReader allocates a Google Cloud Pub/Sub concrete implementation of streams.Reader.
+func NewReader(cfg ReaderConfig, client *pubsub.Client) Reader {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.gcp.pubsub: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.HandlerTimeout == 0 {
+		cfg.HandlerTimeout = time.Second * 30
+	}
+	if cfg.MaxExtension == 0 {
+		cfg.MaxExtension = cfg.HandlerTimeout
+	}
+	return Reader{
+		config: cfg,
+		client: client,
+	}
+}
+
+func (r Reader) Read(ctx context.Context, task streams.ReadTask) error {
+	subID := genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskSubscriptionKey])
+	if subID == "" {
+		subID = task.Stream
+	}
+
+	sub := r.client.Subscription(subID)
+	sub.ReceiveSettings.MaxExtension = r.config.MaxExtension
+	if r.config.MaxOutstandingMessages != 0 {
+		sub.ReceiveSettings.MaxOutstandingMessages = r.config.MaxOutstandingMessages
+	}
+	if r.config.NumGoroutines > 0 {
+		sub.ReceiveSettings.NumGoroutines = r.config.NumGoroutines
+	}
+	err := sub.Receive(ctx, func(msgCtx context.Context, rawMsg *pubsub.Message) {
+		r.handleMessage(msgCtx, task, subID, rawMsg)
+	})
+	if err != nil {
+		return err
+	}
+	r.config.Logger.Printf("stopping subscription receiving process")
+	return nil
+}
+
+func (r Reader) handleMessage(ctx context.Context, task streams.ReadTask, subID string, rawMsg *pubsub.Message) {
+	msg := unmarshalMessage(rawMsg)
+	msg.Headers[HeaderSubscription] = subID
+
+	scopedCtx, cancel := context.WithTimeout(ctx, r.config.HandlerTimeout)
+	errHandle := task.Handler(scopedCtx, msg)
+	cancel()
+
+	var res *pubsub.AckResult
+	if errHandle != nil {
+		// do nothing else as developers are able to wrap message handler with middleware functions.
+		res = rawMsg.NackWithResult()
+	} else {
+		res = rawMsg.AckWithResult()
+	}
+
+	// results are ready right away unless exactly-once delivery is enabled; ctx might be already cancelled.
+	ackCtx, cancelAck := context.WithTimeout(context.Background(), r.config.HandlerTimeout)
+	defer cancelAck()
+	if _, err := res.Get(ackCtx); err != nil {
+		r.config.ErrorLogger.Printf("failed to acknowledge message <%s>, %s", rawMsg.ID, err.Error())
+	}
+}
@@ -0,0 +1,97 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamspubsub "github.com/alexandria-oss/streams/driver/gcp/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_Read(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+	_, err := streamspubsub.EnsureSubscription(ctx, client, streamspubsub.SubscriptionConfig{
+		Stream:              "foo",
+		SubscriptionID:      "foo-worker",
+		DeadLetter:          true,
+		MaxDeliveryAttempts: 5,
+	})
+	require.NoError(t, err)
+
+	w := streamspubsub.NewWriter(streamspubsub.WriterConfig{}, client)
+	defer w.Close()
+	err = w.Write(ctx, []streams.Message{
+		{ID: "1", StreamName: "foo", StreamKey: "foo-key", Headers: map[string]string{"foo": "bar"},
+			Data: []byte("1")},
+		{ID: "2", StreamName: "foo", Data: []byte("2")},
+	})
+	require.NoError(t, err)
+
+	r := streamspubsub.NewReader(streamspubsub.ReaderConfig{}, client)
+	readCtx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+	mu := sync.Mutex{}
+	received := make(map[string][]streams.Message)
+	err = r.Read(readCtx, streams.ReadTask{
+		Stream: "foo",
+		Handler: func(_ context.Context, msg streams.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received[msg.ID] = append(received[msg.ID], msg)
+			if msg.ID == "2" {
+				if msg.Headers[streamspubsub.HeaderDeliveryAttempt] == "5" {
+					cancel()
+				}
+				return errors.New("generic error")
+			}
+			return nil
+		},
+		ExternalArgs: map[string]any{
+			streamspubsub.ReaderTaskSubscriptionKey: "foo-worker",
+		},
+	})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received["1"], 1)
+	msg := received["1"][0]
+	assert.Equal(t, "foo", msg.StreamName)
+	assert.Equal(t, "foo-key", msg.StreamKey)
+	assert.Equal(t, []byte("1"), msg.Data)
+	assert.Equal(t, "bar", msg.Headers["foo"])
+	assert.Equal(t, "foo-worker", msg.Headers[streamspubsub.HeaderSubscription])
+	assert.Equal(t, "1", msg.Headers[streamspubsub.HeaderDeliveryAttempt])
+	assert.NotEmpty(t, msg.Headers[streamspubsub.HeaderMessageID])
+	assert.Len(t, received["2"], 5)
+
+	// messages are forwarded to the dead-letter topic once max delivery attempts are reached
+	dlqCtx, cancelDLQ := context.WithTimeout(ctx, time.Second*10)
+	defer cancelDLQ()
+	var deadLetter streams.Message
+	err = r.Read(dlqCtx, streams.ReadTask{
+		Stream: "foo.dlq",
+		Handler: func(_ context.Context, msg streams.Message) error {
+			deadLetter = msg
+			cancelDLQ()
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2", deadLetter.ID)
+}
+
+func TestReader_ReadMissingSubscription(t *testing.T) {
+	_, client := newTestClient(t)
+	r := streamspubsub.NewReader(streamspubsub.ReaderConfig{}, client)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err := r.Read(ctx, streams.ReadTask{Stream: "foo"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// DeadLetterTopicSuffix is the suffix of dead-letter topic names, matching the stream names written by
+// streams.WithDeadLetterQueue.
+const DeadLetterTopicSuffix = ".dlq"
+
+// SubscriptionConfig is the configuration schema of the topics and subscription created by EnsureSubscription.
+type SubscriptionConfig struct {
+	Stream         string        // Name of the topic.
+	SubscriptionID string        // Identifier of the subscription. Default is Stream.
+	AckDeadline    time.Duration // Initial ack deadline of messages. Default is 10 seconds.
+	EnableOrdering bool          // Deliver messages sharing an ordering key (Message.StreamKey) in order.
+	ExactlyOnce    bool          // Enable exactly-once delivery.
+	// Forward messages to a dead-letter topic (Stream with DeadLetterTopicSuffix) once MaxDeliveryAttempts are
+	// reached. A subscription named after the dead-letter topic is created to retain them.
+	//
+	// The Pub/Sub service account requires publisher permissions on the dead-letter topic and subscriber
+	// permissions on the subscription.
+	DeadLetter bool
+	// Maximum count of delivery attempts before forwarding a message to the dead-letter topic (5 to 100). Default is 5.
+	MaxDeliveryAttempts int
+}
+
+// EnsureSubscription creates the topic, the dead-letter topic and the subscription of cfg if they do not exist.
+func EnsureSubscription(ctx context.Context, client *pubsub.Client, cfg SubscriptionConfig) (*pubsub.Subscription,
+	error) {
+	if cfg.SubscriptionID == "" {
+		cfg.SubscriptionID = cfg.Stream
+	}
+	if cfg.MaxDeliveryAttempts == 0 {
+		cfg.MaxDeliveryAttempts = 5
+	}
+
+	topic, err := ensureTopic(ctx, client, cfg.Stream)
+	if err != nil {
+		return nil, err
+	}
+	subCfg := pubsub.SubscriptionConfig{
+		Topic:                     topic,
+		AckDeadline:               cfg.AckDeadline,
+		EnableMessageOrdering:     cfg.EnableOrdering,
+		EnableExactlyOnceDelivery: cfg.ExactlyOnce,
+	}
+	if cfg.DeadLetter {
+		deadLetterTopicID := cfg.Stream + DeadLetterTopicSuffix
+		deadLetterTopic, errTopic := ensureTopic(ctx, client, deadLetterTopicID)
+		if errTopic != nil {
+			return nil, errTopic
+		}
+		_, err = ensureSubscription(ctx, client, deadLetterTopicID, pubsub.SubscriptionConfig{
+			Topic: deadLetterTopic,
+		})
+		if err != nil {
+			return nil, err
+		}
+		subCfg.DeadLetterPolicy = &pubsub.DeadLetterPolicy{
+			DeadLetterTopic:     deadLetterTopic.String(),
+			MaxDeliveryAttempts: cfg.MaxDeliveryAttempts,
+		}
+	}
+	return ensureSubscription(ctx, client, cfg.SubscriptionID, subCfg)
+}
+
+func ensureTopic(ctx context.Context, client *pubsub.Client, id string) (*pubsub.Topic, error) {
+	topic := client.Topic(id)
+	exists, err := topic.Exists(ctx)
+	if err != nil {
+		return nil, err
+	} else if exists {
+		return topic, nil
+	}
+	return client.CreateTopic(ctx, id)
+}
+
+func ensureSubscription(ctx context.Context, client *pubsub.Client, id string,
+	cfg pubsub.SubscriptionConfig) (*pubsub.Subscription, error) {
+	sub := client.Subscription(id)
+	exists, err := sub.Exists(ctx)
+	if err != nil {
+		return nil, err
+	} else if exists {
+		return sub, nil
+	}
+	return client.CreateSubscription(ctx, id, cfg)
+}
@@ -0,0 +1,42 @@
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+
+	streamspubsub "github.com/alexandria-oss/streams/driver/gcp/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureSubscription(t *testing.T) {
+	_, client := newTestClient(t)
+	ctx := context.Background()
+	cfg := streamspubsub.SubscriptionConfig{
+		Stream:         "foo",
+		SubscriptionID: "foo-worker",
+		EnableOrdering: true,
+		ExactlyOnce:    true,
+		DeadLetter:     true,
+	}
+	sub, err := streamspubsub.EnsureSubscription(ctx, client, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "foo-worker", sub.ID())
+
+	// declarations are idempotent
+	_, err = streamspubsub.EnsureSubscription(ctx, client, cfg)
+	require.NoError(t, err)
+
+	subCfg, err := sub.Config(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", subCfg.Topic.ID())
+	assert.True(t, subCfg.EnableMessageOrdering)
+	assert.True(t, subCfg.EnableExactlyOnceDelivery)
+	require.NotNil(t, subCfg.DeadLetterPolicy)
+	assert.Equal(t, client.Topic("foo.dlq").String(), subCfg.DeadLetterPolicy.DeadLetterTopic)
+	assert.Equal(t, 5, subCfg.DeadLetterPolicy.MaxDeliveryAttempts)
+
+	exists, err := client.Subscription("foo.dlq").Exists(ctx)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/alexandria-oss/streams"
+	"github.com/hashicorp/go-multierror"
+)
+
+// WriterConfig is the Google Cloud Pub/Sub writer configuration schema.
+type WriterConfig struct {
+	// Set Message.StreamKey as the message ordering key, so messages sharing a key are delivered in order to
+	// subscriptions with message ordering enabled. Once a message fails to be published, messages with the same
+	// key are not published until the next Writer.Write call.
+	EnableOrdering bool
+	// Batching settings of every topic. Default is pubsub.DefaultPublishSettings.
+	PublishSettings *pubsub.PublishSettings
+}
+
+// Writer is the Google Cloud Pub/Sub streams.Writer implementation.
+//
+// Messages are published to the topic named after Message.StreamName; topic handles are kept until Writer.Close is
+// called.
+type Writer struct {
+	config WriterConfig
+	client *pubsub.Client
+	mu     *sync.Mutex
+	topics map[string]*pubsub.Topic
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates a Google Cloud Pub/Sub concrete implementation of streams.Writer.
+func NewWriter(cfg WriterConfig, client *pubsub.Client) Writer {
+	return Writer{
+		config: cfg,
+		client: client,
+		mu:     &sync.Mutex{},
+		topics: make(map[string]*pubsub.Topic),
+	}
+}
+
+func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) error {
+	results := make([]*pubsub.PublishResult, len(msgBatch))
+	topics := make([]*pubsub.Topic, len(msgBatch))
+	for i, msg := range msgBatch {
+		rawMsg := &pubsub.Message{
+			Data:       msg.Data,
+			Attributes: newMessageAttributes(msg),
+		}
+		if w.config.EnableOrdering {
+			rawMsg.OrderingKey = msg.StreamKey
+		}
+		topics[i] = w.topic(msg.StreamName)
+		results[i] = topics[i].Publish(ctx, rawMsg)
+	}
+
+	errs := &multierror.Error{}
+	for i, res := range results {
+		if _, err := res.Get(ctx); err != nil {
+			errs = multierror.Append(errs, err)
+			if w.config.EnableOrdering && msgBatch[i].StreamKey != "" {
+				topics[i].ResumePublish(msgBatch[i].StreamKey)
+			}
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// Close publishes every buffered message and releases topic handles.
+func (w Writer) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for name, topic := range w.topics {
+		topic.Stop()
+		delete(w.topics, name)
+	}
+}
+
+func (w Writer) topic(stream string) *pubsub.Topic {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if topic, ok := w.topics[stream]; ok {
+		return topic
+	}
+
+	topic := w.client.Topic(stream)
+	topic.EnableMessageOrdering = w.config.EnableOrdering
+	if w.config.PublishSettings != nil {
+		topic.PublishSettings = *w.config.PublishSettings
+	}
+	w.topics[stream] = topic
+	return topic
+}
@@ -0,0 +1,134 @@
+package pubsub_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/driver/gcp"
+	streamspubsub "github.com/alexandria-oss/streams/driver/gcp/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newTestClient starts an in-process Pub/Sub emulator (pstest), creating a topic for every stream.
+func newTestClient(t *testing.T, streamNames ...string) (*pstest.Server, *pubsub.Client) {
+	srv := pstest.NewServer()
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+	conn, err := grpc.Dial(srv.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	client, err := pubsub.NewClient(context.Background(), "streams-test", option.WithGRPCConn(conn))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	for _, stream := range streamNames {
+		_, err = client.CreateTopic(context.Background(), stream)
+		require.NoError(t, err)
+	}
+	return srv, client
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name           string
+		inOrdering     bool
+		inMsgs         []streams.Message
+		wantPublished  int
+		wantOrderingOK bool
+		wantErr        bool
+	}{
+		{
+			name: "multiple topics",
+			inMsgs: []streams.Message{
+				{ID: "1", StreamName: "foo", StreamKey: "foo-key", Data: []byte("1")},
+				{ID: "2", StreamName: "bar", StreamKey: "bar-key", Data: []byte("2")},
+				{ID: "3", StreamName: "foo", Data: []byte("3")},
+			},
+			wantPublished: 3,
+		},
+		{
+			name:       "ordering keys",
+			inOrdering: true,
+			inMsgs: []streams.Message{
+				{ID: "1", StreamName: "foo", StreamKey: "foo-key", Data: []byte("1")},
+				{ID: "2", StreamName: "foo", StreamKey: "foo-key", Data: []byte("2")},
+			},
+			wantPublished:  2,
+			wantOrderingOK: true,
+		},
+		{
+			name: "missing topic",
+			inMsgs: []streams.Message{
+				{ID: "1", StreamName: "baz", Data: []byte("1")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestClient(t, "foo", "bar")
+			w := streamspubsub.NewWriter(streamspubsub.WriterConfig{
+				EnableOrdering: tt.inOrdering,
+			}, client)
+			defer w.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+			err := w.Write(ctx, tt.inMsgs)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			published := srv.Messages()
+			require.Len(t, published, tt.wantPublished)
+			for _, msg := range published {
+				assert.NotEmpty(t, msg.Attributes[gcp.HeaderMessageID])
+				assert.NotEmpty(t, msg.Attributes[gcp.HeaderStreamName])
+				if tt.wantOrderingOK {
+					assert.Equal(t, "foo-key", msg.OrderingKey)
+				} else {
+					assert.Empty(t, msg.OrderingKey)
+				}
+			}
+		})
+	}
+}
+
+func TestWriter_WriteAttributes(t *testing.T) {
+	srv, client := newTestClient(t, "foo")
+	w := streamspubsub.NewWriter(streamspubsub.WriterConfig{}, client)
+	defer w.Close()
+	msgTime := time.UnixMilli(time.Now().UnixMilli())
+	err := w.Write(context.Background(), []streams.Message{
+		{
+			ID:          "1",
+			StreamName:  "foo",
+			StreamKey:   "foo-key",
+			ContentType: "application/json",
+			Headers:     map[string]string{"foo": "bar"},
+			Data:        []byte(`{"foo":"bar"}`),
+			Time:        msgTime,
+		},
+	})
+	require.NoError(t, err)
+
+	published := srv.Messages()
+	require.Len(t, published, 1)
+	assert.Equal(t, []byte(`{"foo":"bar"}`), published[0].Data)
+	assert.Equal(t, map[string]string{
+		gcp.HeaderMessageID:   "1",
+		gcp.HeaderStreamName:  "foo",
+		gcp.HeaderStreamKey:   "foo-key",
+		gcp.HeaderContentType: "application/json",
+		gcp.HeaderMessageTime: strconv.FormatInt(msgTime.UnixMilli(), 10),
+		"foo":                 "bar",
+	}, published[0].Attributes)
+}
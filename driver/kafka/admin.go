@@ -0,0 +1,412 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// DeadLetterTopicSuffix is the suffix of dead-letter queue topics (e.g. streams.WithDeadLetterQueue).
+	DeadLetterTopicSuffix = ".dlq"
+	// RetryTopicSuffix is the suffix of retry topics.
+	RetryTopicSuffix = ".retry"
+)
+
+const (
+	retentionConfigKey     = "retention.ms"
+	cleanupPolicyConfigKey = "cleanup.policy"
+	cleanupPolicyCompact   = "compact"
+	cleanupPolicyDelete    = "delete"
+	partitionsProperty     = "partitions"
+	replicationProperty    = "replication_factor"
+)
+
+// A TopicSpec is the declared configuration of an Apache Kafka topic.
+type TopicSpec struct {
+	Partitions        int // Count of partitions of the topic.
+	ReplicationFactor int // Count of replicas of each partition.
+	// Maximum time duration messages are retained (retention.ms). Broker default if zero, unlimited if negative.
+	Retention time.Duration
+	Compacted bool // Use the compact cleanup policy instead of delete.
+}
+
+func (s TopicSpec) configEntries() []kafka.ConfigEntry {
+	entries := []kafka.ConfigEntry{
+		{
+			ConfigName:  cleanupPolicyConfigKey,
+			ConfigValue: s.cleanupPolicy(),
+		},
+	}
+	if retention := s.retentionMillis(); retention != "" {
+		entries = append(entries, kafka.ConfigEntry{
+			ConfigName:  retentionConfigKey,
+			ConfigValue: retention,
+		})
+	}
+	return entries
+}
+
+func (s TopicSpec) cleanupPolicy() string {
+	if s.Compacted {
+		return cleanupPolicyCompact
+	}
+	return cleanupPolicyDelete
+}
+
+func (s TopicSpec) retentionMillis() string {
+	if s.Retention == 0 {
+		return ""
+	} else if s.Retention < 0 {
+		return "-1"
+	}
+	return strconv.FormatInt(s.Retention.Milliseconds(), 10)
+}
+
+// A TopicDrift is a difference between the declared and actual configuration of a topic property.
+type TopicDrift struct {
+	Topic    string
+	Property string // Topic property (e.g. partitions, replication_factor or a topic config key like retention.ms).
+	Declared string
+	Actual   string
+}
+
+// A TopicReport is the outcome of an Admin operation.
+type TopicReport struct {
+	Missing []string     // Declared topics not found in the cluster.
+	Created []string     // Topics created by the Admin.
+	Altered []string     // Topics altered by the Admin to match their declared configuration.
+	Drift   []TopicDrift // Differences between declared and actual configuration left in the cluster.
+}
+
+// AdminConfig is the configuration for Admin.
+type AdminConfig struct {
+	Client   *kafka.Client         // Apache Kafka client used to send administrative requests.
+	Registry streams.EventRegistry // Registry containing every topic to be declared.
+	Topics   []string              // Topics to be declared besides Registry topics (optional).
+	Default  TopicSpec             // Configuration of topics with no entry in Specs.
+	Specs    map[string]TopicSpec  // Configuration of specific topics, including dead-letter and retry topics.
+	Logger   *log.Logger           // Logger to write information to.
+	// Declare a dead-letter queue topic (DeadLetterTopicSuffix) for each topic. The topic configuration is used if
+	// no entry was found in Specs.
+	DeadLetterTopics bool
+	// Declare a retry topic (RetryTopicSuffix) for each topic. The topic configuration is used if no entry was
+	// found in Specs.
+	RetryTopics bool
+	// Alter existing topics to match their declared configuration. Otherwise, differences are only reported.
+	// Replication factor and partition count decrements are always reported as they cannot be altered.
+	AlterDrift bool
+}
+
+// An Admin is a component used to manage Apache Kafka topics declared by an EventRegistry. Use it on
+// application startup (or through the streams-kafka-admin command) instead of relying on automatic topic creation.
+type Admin struct {
+	cfg AdminConfig
+}
+
+// NewAdmin allocates an Admin instance.
+func NewAdmin(cfg AdminConfig) Admin {
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(os.Stdout, "streams.kafka.admin: ", 0)
+	}
+	if cfg.Default.Partitions <= 0 {
+		cfg.Default.Partitions = 1
+	}
+	if cfg.Default.ReplicationFactor <= 0 {
+		cfg.Default.ReplicationFactor = 1
+	}
+	return Admin{
+		cfg: cfg,
+	}
+}
+
+// DeclaredTopics retrieves the declared configuration of every topic.
+func (a Admin) DeclaredTopics() map[string]TopicSpec {
+	topics := make(map[string]TopicSpec, len(a.cfg.Registry)+len(a.cfg.Topics))
+	declare := func(topic string, fallback TopicSpec) {
+		if spec, ok := a.cfg.Specs[topic]; ok {
+			topics[topic] = spec
+			return
+		}
+		topics[topic] = fallback
+	}
+
+	baseTopics := make([]string, 0, len(a.cfg.Registry)+len(a.cfg.Topics))
+	for _, topic := range a.cfg.Registry {
+		baseTopics = append(baseTopics, topic)
+	}
+	baseTopics = append(baseTopics, a.cfg.Topics...)
+	for _, topic := range baseTopics {
+		declare(topic, a.cfg.Default)
+		spec := topics[topic]
+		if a.cfg.DeadLetterTopics {
+			declare(topic+DeadLetterTopicSuffix, spec)
+		}
+		if a.cfg.RetryTopics {
+			declare(topic+RetryTopicSuffix, spec)
+		}
+	}
+	return topics
+}
+
+// DescribeTopics retrieves the actual configuration of topics. Topics not found in the cluster are not included.
+func (a Admin) DescribeTopics(ctx context.Context, topics []string) (map[string]TopicSpec, error) {
+	metadata, err := a.cfg.Client.Metadata(ctx, &kafka.MetadataRequest{
+		Topics: topics,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make(map[string]TopicSpec, len(topics))
+	resources := make([]kafka.DescribeConfigRequestResource, 0, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if errors.Is(topic.Error, kafka.UnknownTopicOrPartition) {
+			continue
+		} else if topic.Error != nil {
+			return nil, topic.Error
+		}
+		spec := TopicSpec{
+			Partitions: len(topic.Partitions),
+		}
+		if len(topic.Partitions) > 0 {
+			spec.ReplicationFactor = len(topic.Partitions[0].Replicas)
+		}
+		specs[topic.Name] = spec
+		resources = append(resources, kafka.DescribeConfigRequestResource{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic.Name,
+			ConfigNames:  []string{retentionConfigKey, cleanupPolicyConfigKey},
+		})
+	}
+	if len(resources) == 0 {
+		return specs, nil
+	}
+
+	configs, err := a.cfg.Client.DescribeConfigs(ctx, &kafka.DescribeConfigsRequest{
+		Resources: resources,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range configs.Resources {
+		if resource.Error != nil {
+			return nil, resource.Error
+		}
+		spec := specs[resource.ResourceName]
+		for _, entry := range resource.ConfigEntries {
+			switch entry.ConfigName {
+			case retentionConfigKey:
+				millis, errParse := strconv.ParseInt(entry.ConfigValue, 10, 64)
+				if errParse != nil {
+					return nil, errParse
+				}
+				spec.Retention = time.Duration(millis) * time.Millisecond
+				if millis < 0 {
+					spec.Retention = -1
+				}
+			case cleanupPolicyConfigKey:
+				spec.Compacted = entry.ConfigValue == cleanupPolicyCompact
+			}
+		}
+		specs[resource.ResourceName] = spec
+	}
+	return specs, nil
+}
+
+// Diff compares declared and actual topic configurations, reporting missing topics and drift.
+func (a Admin) Diff(ctx context.Context) (TopicReport, error) {
+	declared := a.DeclaredTopics()
+	actual, err := a.DescribeTopics(ctx, sortedTopics(declared))
+	if err != nil {
+		return TopicReport{}, err
+	}
+	return diffTopics(declared, actual), nil
+}
+
+// EnsureTopics creates every missing topic and, if AdminConfig.AlterDrift is set, alters existing topics to match
+// their declared configuration. The report contains every drift left.
+func (a Admin) EnsureTopics(ctx context.Context) (TopicReport, error) {
+	declared := a.DeclaredTopics()
+	report, err := a.Diff(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	if err = a.createTopics(ctx, declared, report.Missing); err != nil {
+		return report, err
+	}
+	report.Created = report.Missing
+	report.Missing = nil
+	if !a.cfg.AlterDrift || len(report.Drift) == 0 {
+		return report, nil
+	}
+
+	report.Altered, report.Drift, err = a.alterTopics(ctx, declared, report.Drift)
+	return report, err
+}
+
+func (a Admin) createTopics(ctx context.Context, declared map[string]TopicSpec, topics []string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	configs := make([]kafka.TopicConfig, 0, len(topics))
+	for _, topic := range topics {
+		spec := declared[topic]
+		configs = append(configs, kafka.TopicConfig{
+			Topic:             topic,
+			NumPartitions:     spec.Partitions,
+			ReplicationFactor: spec.ReplicationFactor,
+			ConfigEntries:     spec.configEntries(),
+		})
+	}
+	res, err := a.cfg.Client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: configs,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	for _, topic := range topics {
+		if errTopic := res.Errors[topic]; errTopic != nil && !errors.Is(errTopic, kafka.TopicAlreadyExists) {
+			errs = multierror.Append(errs, errTopic)
+			continue
+		}
+		a.cfg.Logger.Printf("created topic <%s>", topic)
+	}
+	return errs.ErrorOrNil()
+}
+
+// alterTopics alters topics to match their declared configuration, returning altered topics and drift left.
+func (a Admin) alterTopics(ctx context.Context, declared map[string]TopicSpec,
+	drift []TopicDrift) ([]string, []TopicDrift, error) {
+	var (
+		partitions []kafka.TopicPartitionsConfig
+		resources  []kafka.IncrementalAlterConfigsRequestResource
+		left       []TopicDrift
+		altered    = make(map[string]struct{})
+		configs    = make(map[string][]kafka.IncrementalAlterConfigsRequestConfig)
+	)
+	for _, d := range drift {
+		spec := declared[d.Topic]
+		switch d.Property {
+		case partitionsProperty:
+			actual, _ := strconv.Atoi(d.Actual)
+			if spec.Partitions < actual {
+				left = append(left, d)
+				continue
+			}
+			partitions = append(partitions, kafka.TopicPartitionsConfig{
+				Name:  d.Topic,
+				Count: int32(spec.Partitions),
+			})
+		case retentionConfigKey, cleanupPolicyConfigKey:
+			configs[d.Topic] = append(configs[d.Topic], kafka.IncrementalAlterConfigsRequestConfig{
+				Name:            d.Property,
+				Value:           d.Declared,
+				ConfigOperation: kafka.ConfigOperationSet,
+			})
+		default:
+			left = append(left, d)
+			continue
+		}
+		altered[d.Topic] = struct{}{}
+	}
+
+	var errs *multierror.Error
+	if len(partitions) > 0 {
+		res, err := a.cfg.Client.CreatePartitions(ctx, &kafka.CreatePartitionsRequest{
+			Topics: partitions,
+		})
+		if err != nil {
+			return nil, drift, err
+		}
+		for _, errTopic := range res.Errors {
+			if errTopic != nil {
+				errs = multierror.Append(errs, errTopic)
+			}
+		}
+	}
+	for topic, topicConfigs := range configs {
+		resources = append(resources, kafka.IncrementalAlterConfigsRequestResource{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic,
+			Configs:      topicConfigs,
+		})
+	}
+	if len(resources) > 0 {
+		res, err := a.cfg.Client.IncrementalAlterConfigs(ctx, &kafka.IncrementalAlterConfigsRequest{
+			Resources: resources,
+		})
+		if err != nil {
+			return nil, drift, err
+		}
+		for _, resource := range res.Resources {
+			if resource.Error != nil {
+				errs = multierror.Append(errs, resource.Error)
+			}
+		}
+	}
+
+	alteredTopics := make([]string, 0, len(altered))
+	for topic := range altered {
+		alteredTopics = append(alteredTopics, topic)
+		a.cfg.Logger.Printf("altered topic <%s>", topic)
+	}
+	sort.Strings(alteredTopics)
+	return alteredTopics, left, errs.ErrorOrNil()
+}
+
+func diffTopics(declared, actual map[string]TopicSpec) TopicReport {
+	report := TopicReport{}
+	for _, topic := range sortedTopics(declared) {
+		spec := declared[topic]
+		actualSpec, ok := actual[topic]
+		if !ok {
+			report.Missing = append(report.Missing, topic)
+			continue
+		}
+		report.Drift = append(report.Drift, diffTopicSpec(topic, spec, actualSpec)...)
+	}
+	return report
+}
+
+func diffTopicSpec(topic string, declared, actual TopicSpec) []TopicDrift {
+	var drift []TopicDrift
+	appendDrift := func(property, declaredVal, actualVal string) {
+		if declaredVal == actualVal {
+			return
+		}
+		drift = append(drift, TopicDrift{
+			Topic:    topic,
+			Property: property,
+			Declared: declaredVal,
+			Actual:   actualVal,
+		})
+	}
+	appendDrift(partitionsProperty, strconv.Itoa(declared.Partitions), strconv.Itoa(actual.Partitions))
+	appendDrift(replicationProperty, strconv.Itoa(declared.ReplicationFactor), strconv.Itoa(actual.ReplicationFactor))
+	if declared.Retention != 0 {
+		appendDrift(retentionConfigKey, declared.retentionMillis(), actual.retentionMillis())
+	}
+	appendDrift(cleanupPolicyConfigKey, declared.cleanupPolicy(), actual.cleanupPolicy())
+	return drift
+}
+
+func sortedTopics(specs map[string]TopicSpec) []string {
+	topics := make([]string, 0, len(specs))
+	for topic := range specs {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
@@ -0,0 +1,54 @@
+//go:build integration
+
+package kafka_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	streamskafka "github.com/alexandria-oss/streams/driver/kafka"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmin_EnsureTopics(t *testing.T) {
+	const (
+		addr  = "localhost:9092"
+		topic = "org.alexandria.integration_test.admin"
+	)
+	cfg := streamskafka.AdminConfig{
+		Client: &kafka.Client{
+			Addr: kafka.TCP(addr),
+		},
+		Topics: []string{topic},
+		Default: streamskafka.TopicSpec{
+			Partitions: 1,
+			Retention:  time.Hour,
+		},
+		DeadLetterTopics: true,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	report, err := streamskafka.NewAdmin(cfg).EnsureTopics(ctx)
+	require.NoError(t, err)
+	defer deleteTopic(t, addr, topic)
+	defer deleteTopic(t, addr, topic+streamskafka.DeadLetterTopicSuffix)
+	assert.Equal(t, []string{topic, topic + streamskafka.DeadLetterTopicSuffix}, report.Created)
+	assert.Empty(t, report.Drift)
+
+	// declare new configuration, drift is reported until altered.
+	cfg.Default.Partitions = 2
+	cfg.Default.Retention = time.Hour * 2
+	report, err = streamskafka.NewAdmin(cfg).Diff(ctx)
+	require.NoError(t, err)
+	assert.Len(t, report.Drift, 4)
+
+	cfg.AlterDrift = true
+	report, err = streamskafka.NewAdmin(cfg).EnsureTopics(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{topic, topic + streamskafka.DeadLetterTopicSuffix}, report.Altered)
+	assert.Empty(t, report.Drift)
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/stretchr/testify/assert"
+)
+
+type fooEvent struct{}
+
+func (f fooEvent) GetHeaders() map[string]string { return nil }
+
+func (f fooEvent) GetKey() string { return "" }
+
+func TestAdmin_DeclaredTopics(t *testing.T) {
+	reg := streams.EventRegistry{}
+	reg.RegisterEvent(fooEvent{}, "foo")
+	admin := NewAdmin(AdminConfig{
+		Registry: reg,
+		Topics:   []string{"bar"},
+		Default: TopicSpec{
+			Partitions: 3,
+			Retention:  time.Hour,
+		},
+		Specs: map[string]TopicSpec{
+			"bar":     {Partitions: 6, ReplicationFactor: 3, Compacted: true},
+			"foo.dlq": {Partitions: 1, ReplicationFactor: 1, Retention: -1},
+		},
+		DeadLetterTopics: true,
+		RetryTopics:      true,
+	})
+	assert.Equal(t, map[string]TopicSpec{
+		"foo":       {Partitions: 3, ReplicationFactor: 1, Retention: time.Hour},
+		"foo.dlq":   {Partitions: 1, ReplicationFactor: 1, Retention: -1},
+		"foo.retry": {Partitions: 3, ReplicationFactor: 1, Retention: time.Hour},
+		"bar":       {Partitions: 6, ReplicationFactor: 3, Compacted: true},
+		"bar.dlq":   {Partitions: 6, ReplicationFactor: 3, Compacted: true},
+		"bar.retry": {Partitions: 6, ReplicationFactor: 3, Compacted: true},
+	}, admin.DeclaredTopics())
+}
+
+func TestDiffTopics(t *testing.T) {
+	declared := map[string]TopicSpec{
+		"foo": {Partitions: 3, ReplicationFactor: 1, Retention: time.Hour},
+		"bar": {Partitions: 1, ReplicationFactor: 3, Compacted: true},
+		"baz": {Partitions: 1, ReplicationFactor: 1},
+		"qux": {Partitions: 1, ReplicationFactor: 1},
+	}
+	actual := map[string]TopicSpec{
+		"foo": {Partitions: 1, ReplicationFactor: 1, Retention: time.Hour * 168},
+		"bar": {Partitions: 1, ReplicationFactor: 1},
+		"baz": {Partitions: 1, ReplicationFactor: 1, Retention: time.Hour * 168},
+	}
+	assert.Equal(t, TopicReport{
+		Missing: []string{"qux"},
+		Drift: []TopicDrift{
+			{Topic: "bar", Property: "replication_factor", Declared: "3", Actual: "1"},
+			{Topic: "bar", Property: "cleanup.policy", Declared: "compact", Actual: "delete"},
+			{Topic: "foo", Property: "partitions", Declared: "3", Actual: "1"},
+			{Topic: "foo", Property: "retention.ms", Declared: "3600000", Actual: "604800000"},
+		},
+	}, diffTopics(declared, actual))
+}
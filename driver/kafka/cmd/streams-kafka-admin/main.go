@@ -0,0 +1,92 @@
+// Command streams-kafka-admin ensures Apache Kafka topics exist with their declared configuration, reporting
+// configuration drift.
+//
+// Usage:
+//
+//	streams-kafka-admin -brokers localhost:9092 -topics org.alexandria.foo,org.alexandria.bar -partitions 3 -dlq
+//
+// Use -dry_run to only report missing topics and drift. The command exits with status 2 if drift (or missing
+// topics on dry runs) is left in the cluster.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	streamskafka "github.com/alexandria-oss/streams/driver/kafka"
+	"github.com/segmentio/kafka-go"
+)
+
+func main() {
+	var (
+		brokers     = flag.String("brokers", "localhost:9092", "comma-separated list of Apache Kafka brokers")
+		topics      = flag.String("topics", "", "comma-separated list of topics to declare")
+		partitions  = flag.Int("partitions", 1, "count of partitions of each topic")
+		replication = flag.Int("replication_factor", 1, "count of replicas of each partition")
+		retention   = flag.Duration("retention", 0, "retention of each topic (broker default if zero, unlimited if negative)")
+		compact     = flag.Bool("compact", false, "use the compact cleanup policy")
+		dlq         = flag.Bool("dlq", false, "declare a dead-letter queue topic for each topic")
+		retry       = flag.Bool("retry", false, "declare a retry topic for each topic")
+		alter       = flag.Bool("alter", false, "alter existing topics to match their declared configuration")
+		dryRun      = flag.Bool("dry_run", false, "only report missing topics and drift")
+		timeout     = flag.Duration("timeout", time.Second*30, "maximum time duration to wait for the command")
+	)
+	flag.Parse()
+	logger := log.New(os.Stdout, "streams-kafka-admin: ", 0)
+	if *topics == "" {
+		logger.Fatal("no topics were declared, use -topics flag")
+	}
+
+	admin := streamskafka.NewAdmin(streamskafka.AdminConfig{
+		Client: &kafka.Client{
+			Addr: kafka.TCP(strings.Split(*brokers, ",")...),
+		},
+		Topics: strings.Split(*topics, ","),
+		Default: streamskafka.TopicSpec{
+			Partitions:        *partitions,
+			ReplicationFactor: *replication,
+			Retention:         *retention,
+			Compacted:         *compact,
+		},
+		Logger:           logger,
+		DeadLetterTopics: *dlq,
+		RetryTopics:      *retry,
+		AlterDrift:       *alter,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var (
+		report streamskafka.TopicReport
+		err    error
+	)
+	if *dryRun {
+		report, err = admin.Diff(ctx)
+	} else {
+		report, err = admin.EnsureTopics(ctx)
+	}
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	for _, topic := range report.Missing {
+		fmt.Printf("missing\t%s\n", topic)
+	}
+	for _, topic := range report.Created {
+		fmt.Printf("created\t%s\n", topic)
+	}
+	for _, topic := range report.Altered {
+		fmt.Printf("altered\t%s\n", topic)
+	}
+	for _, d := range report.Drift {
+		fmt.Printf("drift\t%s\t%s\tdeclared=%s\tactual=%s\n", d.Topic, d.Property, d.Declared, d.Actual)
+	}
+	if len(report.Missing) > 0 || len(report.Drift) > 0 {
+		os.Exit(2)
+	}
+}
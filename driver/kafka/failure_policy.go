@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+)
+
+// A FailurePolicy is the action a Reader takes when a reader handler (streams.ReaderHandleFunc) fails.
+type FailurePolicy uint8
+
+const (
+	// StopOnFailure stops the Reader, returning the handler error. The message offset is not committed.
+	StopOnFailure FailurePolicy = iota
+	// SkipOnFailure logs the handler error and commits the message offset, moving to the next message.
+	SkipOnFailure
+	// RetryOnFailure seeks back to the failed message offset after a backoff interval (ReaderConfig.RetryBackoff),
+	// processing the message again. Once every interval was used -or the handler returned a
+	// streams.ErrUnrecoverable error-, ReaderConfig.RetryExhaustedPolicy is applied.
+	RetryOnFailure
+	// DeadLetterOnFailure writes the failed message to the dead-letter queue (ReaderConfig.DeadLetterWriter) and
+	// commits the message offset. Dead-letter queue messages are emitted to the Message.StreamName but with the
+	// suffix ".dlq". The Reader stops if the dead-letter queue write fails.
+	DeadLetterOnFailure
+)
+
+// ErrMissingDeadLetterWriter the reader failure policy is DeadLetterOnFailure but no dead-letter writer was set.
+var ErrMissingDeadLetterWriter = errors.New("streams.kafka: missing dead-letter queue writer")
+
+// failureResult is the outcome of a reader handler failure.
+type failureResult struct {
+	retry   bool          // seek back to the failed message offset.
+	backoff time.Duration // time duration to wait before seeking back.
+	err     error         // stops the Reader if not nil. Otherwise, the message offset is committed.
+}
+
+// resolveFailure applies policy to a failed message. attempt is the count of times the message was retried.
+func (r Reader) resolveFailure(ctx context.Context, policy FailurePolicy, attempt int, msg streams.Message,
+	errHandler error) failureResult {
+	r.cfg.ErrorLogger.Printf("error occurred while handling message <%s> from <%s>, %s", msg.ID, msg.StreamName,
+		errHandler.Error())
+	switch policy {
+	case SkipOnFailure:
+		return failureResult{}
+	case RetryOnFailure:
+		if attempt >= len(r.cfg.RetryBackoff) || errors.Is(errHandler, streams.ErrUnrecoverable) {
+			if r.cfg.RetryExhaustedPolicy == RetryOnFailure {
+				return failureResult{err: errHandler}
+			}
+			return r.resolveFailure(ctx, r.cfg.RetryExhaustedPolicy, attempt, msg, errHandler)
+		}
+		return failureResult{
+			retry:   true,
+			backoff: r.cfg.RetryBackoff[attempt],
+		}
+	case DeadLetterOnFailure:
+		if r.cfg.DeadLetterWriter == nil {
+			return failureResult{err: ErrMissingDeadLetterWriter}
+		}
+		msg.StreamName += ".dlq"
+		return failureResult{err: r.cfg.DeadLetterWriter.Write(ctx, []streams.Message{msg})}
+	default:
+		return failureResult{err: errHandler}
+	}
+}
+
+// waitBackoff blocks the I/O until backoff elapses or ctx is done.
+func waitBackoff(ctx context.Context, backoff time.Duration) error {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
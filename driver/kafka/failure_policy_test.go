@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/stretchr/testify/assert"
+)
+
+type deadLetterWriterSpy struct {
+	err  error
+	msgs []streams.Message
+}
+
+func (w *deadLetterWriterSpy) Write(_ context.Context, msgBatch []streams.Message) error {
+	w.msgs = append(w.msgs, msgBatch...)
+	return w.err
+}
+
+func TestReader_ResolveFailure(t *testing.T) {
+	errHandler := errors.New("generic error")
+	backoff := []time.Duration{time.Millisecond, time.Millisecond * 2}
+	tests := []struct {
+		name           string
+		inPolicy       FailurePolicy
+		inExhausted    FailurePolicy
+		inAttempt      int
+		inErr          error
+		inNoDLQ        bool
+		inDLQErr       error
+		wantResult     failureResult
+		wantDLQStreams []string
+	}{
+		{
+			name:       "stop",
+			inPolicy:   StopOnFailure,
+			inErr:      errHandler,
+			wantResult: failureResult{err: errHandler},
+		},
+		{
+			name:       "skip",
+			inPolicy:   SkipOnFailure,
+			inErr:      errHandler,
+			wantResult: failureResult{},
+		},
+		{
+			name:       "retry",
+			inPolicy:   RetryOnFailure,
+			inAttempt:  1,
+			inErr:      errHandler,
+			wantResult: failureResult{retry: true, backoff: time.Millisecond * 2},
+		},
+		{
+			name:       "retry exhausted",
+			inPolicy:   RetryOnFailure,
+			inAttempt:  2,
+			inErr:      errHandler,
+			wantResult: failureResult{err: errHandler},
+		},
+		{
+			name:        "retry exhausted skip",
+			inPolicy:    RetryOnFailure,
+			inExhausted: SkipOnFailure,
+			inAttempt:   2,
+			inErr:       errHandler,
+			wantResult:  failureResult{},
+		},
+		{
+			name:           "retry unrecoverable",
+			inPolicy:       RetryOnFailure,
+			inExhausted:    DeadLetterOnFailure,
+			inErr:          streams.ErrUnrecoverableWrap{ParentErr: errHandler},
+			wantResult:     failureResult{},
+			wantDLQStreams: []string{"foo.dlq"},
+		},
+		{
+			name:           "dead-letter queue",
+			inPolicy:       DeadLetterOnFailure,
+			inErr:          errHandler,
+			wantResult:     failureResult{},
+			wantDLQStreams: []string{"foo.dlq"},
+		},
+		{
+			name:           "dead-letter queue failure",
+			inPolicy:       DeadLetterOnFailure,
+			inErr:          errHandler,
+			inDLQErr:       io.ErrUnexpectedEOF,
+			wantResult:     failureResult{err: io.ErrUnexpectedEOF},
+			wantDLQStreams: []string{"foo.dlq"},
+		},
+		{
+			name:       "missing dead-letter queue",
+			inPolicy:   DeadLetterOnFailure,
+			inErr:      errHandler,
+			inNoDLQ:    true,
+			wantResult: failureResult{err: ErrMissingDeadLetterWriter},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dlq := &deadLetterWriterSpy{err: tt.inDLQErr}
+			cfg := ReaderConfig{
+				RetryBackoff:         backoff,
+				RetryExhaustedPolicy: tt.inExhausted,
+				DeadLetterWriter:     dlq,
+			}
+			cfg.ErrorLogger = log.New(io.Discard, "", 0)
+			if tt.inNoDLQ {
+				cfg.DeadLetterWriter = nil
+			}
+			r := NewReader(cfg)
+			res := r.resolveFailure(context.Background(), tt.inPolicy, tt.inAttempt,
+				streams.Message{ID: "123", StreamName: "foo"}, tt.inErr)
+			assert.Equal(t, tt.wantResult, res)
+			streamNames := make([]string, 0, len(dlq.msgs))
+			for _, msg := range dlq.msgs {
+				streamNames = append(streamNames, msg.StreamName)
+			}
+			if tt.wantDLQStreams == nil {
+				assert.Empty(t, streamNames)
+				return
+			}
+			assert.Equal(t, tt.wantDLQStreams, streamNames)
+		})
+	}
+}
@@ -6,8 +6,10 @@ replace github.com/alexandria-oss/streams => ../../
 
 require (
 	github.com/alexandria-oss/streams v0.0.1-alpha.7
+	github.com/hashicorp/go-multierror v1.1.1
 	github.com/segmentio/kafka-go v0.4.39
 	github.com/stretchr/testify v1.8.2
+	github.com/twmb/franz-go v1.13.6
 )
 
 require (
@@ -16,16 +18,16 @@ require (
 	github.com/eapache/go-resiliency v1.3.0 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/hashicorp/errwrap v1.0.0 // indirect
-	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
-	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/klauspost/compress v1.16.3 // indirect
 	github.com/kr/text v0.2.0 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
-	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/pierrec/lz4/v4 v4.1.17 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.9.0 // indirect
 	github.com/segmentio/ksuid v1.0.4 // indirect
+	github.com/twmb/franz-go/pkg/kmsg v1.4.0 // indirect
 	google.golang.org/protobuf v1.29.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -5,6 +5,7 @@ import (
 
 	"github.com/alexandria-oss/streams"
 	"github.com/segmentio/kafka-go"
+	"github.com/twmb/franz-go/pkg/kgo"
 )
 
 const (
@@ -49,6 +50,41 @@ func marshalMessageBatch(msgs []streams.Message) []kafka.Message {
 	return buf
 }
 
+func marshalRecordHeaders(msg streams.Message) []kgo.RecordHeader {
+	headers := make([]kgo.RecordHeader, 0, fixedHeaderCount+len(msg.Headers))
+	headers = append(headers,
+		kgo.RecordHeader{
+			Key:   messageIDHeaderKey,
+			Value: []byte(msg.ID),
+		},
+		kgo.RecordHeader{
+			Key:   contentTypeHeaderKey,
+			Value: []byte(msg.ContentType),
+		},
+	)
+	for k, v := range msg.Headers {
+		headers = append(headers, kgo.RecordHeader{
+			Key:   k,
+			Value: []byte(v),
+		})
+	}
+	return headers
+}
+
+func marshalRecordBatch(msgs []streams.Message) []*kgo.Record {
+	buf := make([]*kgo.Record, 0, len(msgs))
+	for _, msg := range msgs {
+		buf = append(buf, &kgo.Record{
+			Topic:     msg.StreamName,
+			Key:       []byte(msg.StreamKey),
+			Value:     msg.Data,
+			Headers:   marshalRecordHeaders(msg),
+			Timestamp: msg.Time,
+		})
+	}
+	return buf
+}
+
 func unmarshalMessage(msg kafka.Message) streams.Message {
 	var (
 		messageID   string
@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// A TopicPartition is a partition of an Apache Kafka topic.
+type TopicPartition struct {
+	Topic     string
+	Partition int
+}
+
+// A PartitionHookFunc is a function called by Reader when topic partitions are assigned to (or revoked from) a
+// consumer group member.
+type PartitionHookFunc func(ctx context.Context, partitions []TopicPartition)
+
+// PartitionInfo is the position of the message a reader handler (streams.ReaderHandleFunc) is processing within its
+// topic partition.
+type PartitionInfo struct {
+	TopicPartition
+	Offset        int64 // Offset of the message within the partition append log.
+	HighWaterMark int64 // Next offset available from the partition append log.
+	Lag           int64 // Count of messages available after the current message.
+}
+
+type partitionContextKey struct{}
+
+// PartitionFromContext retrieves the PartitionInfo of the message being processed from a reader handler context.
+// Returns false if ctx was not created by Reader.
+func PartitionFromContext(ctx context.Context) (PartitionInfo, bool) {
+	info, ok := ctx.Value(partitionContextKey{}).(PartitionInfo)
+	return info, ok
+}
+
+func newPartitionContext(parent context.Context, msg kafka.Message) context.Context {
+	lag := msg.HighWaterMark - msg.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+	return context.WithValue(parent, partitionContextKey{}, PartitionInfo{
+		TopicPartition: TopicPartition{
+			Topic:     msg.Topic,
+			Partition: msg.Partition,
+		},
+		Offset:        msg.Offset,
+		HighWaterMark: msg.HighWaterMark,
+		Lag:           lag,
+	})
+}
@@ -0,0 +1,15 @@
+package kafka_test
+
+import (
+	"context"
+	"testing"
+
+	streamskafka "github.com/alexandria-oss/streams/driver/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionFromContext(t *testing.T) {
+	info, ok := streamskafka.PartitionFromContext(context.Background())
+	assert.False(t, ok)
+	assert.Zero(t, info)
+}
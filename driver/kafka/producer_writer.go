@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// A ProducerClient is an Apache Kafka producer client. *kgo.Client from github.com/twmb/franz-go satisfies this
+// interface.
+type ProducerClient interface {
+	// BeginTransaction starts a transaction in the client.
+	BeginTransaction() error
+	// ProduceSync produces records, blocking the I/O until every record was acknowledged by Apache Kafka brokers.
+	ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults
+	// AbortBufferedRecords fails every buffered record of the client.
+	AbortBufferedRecords(ctx context.Context) error
+	// EndTransaction commits or aborts the current transaction of the client.
+	EndTransaction(ctx context.Context, commit kgo.TransactionEndTry) error
+}
+
+var _ ProducerClient = &kgo.Client{}
+
+// ProducerWriterConfig is the configuration for ProducerWriter.
+type ProducerWriterConfig struct {
+	// Apache Kafka producer client. Idempotent writes are enabled by default on *kgo.Client instances.
+	Client ProducerClient
+	// Produce every message batch within a transaction. Client MUST be configured with kgo.TransactionalID.
+	Transactional bool
+}
+
+// A ProducerWriter type is the concrete implementation of streams.Writer using the idempotent (or transactional)
+// Apache Kafka producer from github.com/twmb/franz-go.
+//
+// If ProducerWriterConfig.Transactional is set, every message of a ProducerWriter.Write call is produced within a
+// single transaction, making writes atomic across topics and partitions (i.e. consumers using the read_committed
+// isolation level either read the whole batch or nothing). Transactions are serialized as a client might only have
+// one transaction in progress.
+type ProducerWriter struct {
+	cfg ProducerWriterConfig
+	mu  *sync.Mutex
+}
+
+var _ streams.Writer = ProducerWriter{}
+
+// NewProducerWriter allocates a ProducerWriter instance.
+func NewProducerWriter(cfg ProducerWriterConfig) ProducerWriter {
+	return ProducerWriter{
+		cfg: cfg,
+		mu:  &sync.Mutex{},
+	}
+}
+
+func (w ProducerWriter) Write(ctx context.Context, msgBatch []streams.Message) error {
+	records := marshalRecordBatch(msgBatch)
+	if !w.cfg.Transactional {
+		return w.cfg.Client.ProduceSync(ctx, records...).FirstErr()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.cfg.Client.BeginTransaction(); err != nil {
+		return err
+	}
+	if err := w.cfg.Client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		w.abortTransaction(ctx)
+		return err
+	}
+	return w.cfg.Client.EndTransaction(ctx, kgo.TryCommit)
+}
+
+// abortTransaction fails every buffered record and aborts the current transaction. Errors are ignored as the
+// produce error is more relevant to callers; brokers abort the transaction on timeout regardless.
+func (w ProducerWriter) abortTransaction(ctx context.Context) {
+	_ = w.cfg.Client.AbortBufferedRecords(ctx)
+	_ = w.cfg.Client.EndTransaction(ctx, kgo.TryAbort)
+}
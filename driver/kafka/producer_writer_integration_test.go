@@ -0,0 +1,97 @@
+//go:build integration
+
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamskafka "github.com/alexandria-oss/streams/driver/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestProducerWriter_Transactional(t *testing.T) {
+	const addr = "localhost:9092"
+	topics := []string{
+		"org.alexandria.integration_test.producer_writer.foo",
+		"org.alexandria.integration_test.producer_writer.bar",
+	}
+	for _, topic := range topics {
+		createTopic(t, addr, topic)
+		defer deleteTopic(t, addr, topic)
+	}
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(addr),
+		kgo.TransactionalID("streams-integration-test"),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	w := streamskafka.NewProducerWriter(streamskafka.ProducerWriterConfig{
+		Client:        client,
+		Transactional: true,
+	})
+	err = w.Write(ctx, []streams.Message{
+		{
+			ID:          "123",
+			StreamName:  topics[0],
+			StreamKey:   "foo-key",
+			ContentType: "application/text",
+			Data:        []byte("foo"),
+		},
+		{
+			ID:          "456",
+			StreamName:  topics[1],
+			StreamKey:   "bar-key",
+			ContentType: "application/text",
+			Data:        []byte("bar"),
+		},
+	})
+	require.NoError(t, err)
+
+	// a failed write MUST NOT be visible to read_committed consumers.
+	err = w.Write(ctx, []streams.Message{
+		{
+			ID:         "789",
+			StreamName: topics[0],
+			Data:       []byte("baz"),
+		},
+		{
+			ID:         "012",
+			StreamName: "",
+			Data:       []byte("invalid topic"),
+		},
+	})
+	assert.Error(t, err)
+
+	consumer, err := kgo.NewClient(
+		kgo.SeedBrokers(addr),
+		kgo.ConsumeTopics(topics...),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	values := make([]string, 0, 2)
+	pollCtx, pollCancel := context.WithTimeout(ctx, time.Second*10)
+	defer pollCancel()
+	for len(values) < 3 {
+		fetches := consumer.PollFetches(pollCtx)
+		if errors.Is(pollCtx.Err(), context.DeadlineExceeded) {
+			break
+		}
+		fetches.EachRecord(func(r *kgo.Record) {
+			values = append(values, string(r.Value))
+		})
+	}
+	assert.ElementsMatch(t, []string{"foo", "bar"}, values)
+}
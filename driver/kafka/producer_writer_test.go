@@ -0,0 +1,157 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamskafka "github.com/alexandria-oss/streams/driver/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+type producerClientFake struct {
+	beginErr   error
+	produceErr error
+	endErr     error
+
+	calls   []string
+	records []*kgo.Record
+}
+
+var _ streamskafka.ProducerClient = &producerClientFake{}
+
+func (c *producerClientFake) BeginTransaction() error {
+	c.calls = append(c.calls, "begin")
+	return c.beginErr
+}
+
+func (c *producerClientFake) ProduceSync(_ context.Context, rs ...*kgo.Record) kgo.ProduceResults {
+	c.calls = append(c.calls, "produce")
+	c.records = append(c.records, rs...)
+	results := make(kgo.ProduceResults, 0, len(rs))
+	for _, r := range rs {
+		results = append(results, kgo.ProduceResult{
+			Record: r,
+			Err:    c.produceErr,
+		})
+	}
+	return results
+}
+
+func (c *producerClientFake) AbortBufferedRecords(_ context.Context) error {
+	c.calls = append(c.calls, "abort_buffered")
+	return nil
+}
+
+func (c *producerClientFake) EndTransaction(_ context.Context, commit kgo.TransactionEndTry) error {
+	if commit == kgo.TryCommit {
+		c.calls = append(c.calls, "commit")
+	} else {
+		c.calls = append(c.calls, "abort")
+	}
+	return c.endErr
+}
+
+func TestProducerWriter_Write(t *testing.T) {
+	msgs := []streams.Message{
+		{
+			ID:          "123",
+			StreamName:  "foo-stream",
+			StreamKey:   "foo-key",
+			Headers:     map[string]string{"foo": "bar"},
+			ContentType: "application/text",
+			Data:        []byte("foo"),
+			Time:        time.Unix(1000, 0),
+		},
+		{
+			ID:          "456",
+			StreamName:  "bar-stream",
+			ContentType: "application/text",
+			Data:        []byte("bar"),
+		},
+	}
+
+	tests := []struct {
+		name            string
+		inTransactional bool
+		inBeginErr      error
+		inProduceErr    error
+		inEndErr        error
+		wantErr         error
+		wantCalls       []string
+	}{
+		{
+			name:      "idempotent",
+			wantCalls: []string{"produce"},
+		},
+		{
+			name:         "idempotent produce failure",
+			inProduceErr: errors.New("generic error"),
+			wantErr:      errors.New("generic error"),
+			wantCalls:    []string{"produce"},
+		},
+		{
+			name:            "transactional",
+			inTransactional: true,
+			wantCalls:       []string{"begin", "produce", "commit"},
+		},
+		{
+			name:            "begin failure",
+			inTransactional: true,
+			inBeginErr:      errors.New("generic error"),
+			wantErr:         errors.New("generic error"),
+			wantCalls:       []string{"begin"},
+		},
+		{
+			name:            "produce failure aborts",
+			inTransactional: true,
+			inProduceErr:    errors.New("generic error"),
+			wantErr:         errors.New("generic error"),
+			wantCalls:       []string{"begin", "produce", "abort_buffered", "abort"},
+		},
+		{
+			name:            "commit failure",
+			inTransactional: true,
+			inEndErr:        errors.New("generic error"),
+			wantErr:         errors.New("generic error"),
+			wantCalls:       []string{"begin", "produce", "commit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &producerClientFake{
+				beginErr:   tt.inBeginErr,
+				produceErr: tt.inProduceErr,
+				endErr:     tt.inEndErr,
+			}
+			w := streamskafka.NewProducerWriter(streamskafka.ProducerWriterConfig{
+				Client:        client,
+				Transactional: tt.inTransactional,
+			})
+			err := w.Write(context.Background(), msgs)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantCalls, client.calls)
+			if tt.inBeginErr != nil {
+				return
+			}
+
+			if assert.Len(t, client.records, len(msgs)) {
+				r := client.records[0]
+				assert.Equal(t, "foo-stream", r.Topic)
+				assert.Equal(t, []byte("foo-key"), r.Key)
+				assert.Equal(t, []byte("foo"), r.Value)
+				assert.Equal(t, time.Unix(1000, 0), r.Timestamp)
+				assert.ElementsMatch(t, []kgo.RecordHeader{
+					{Key: "message_id", Value: []byte("123")},
+					{Key: "content_type", Value: []byte("application/text")},
+					{Key: "foo", Value: []byte("bar")},
+				}, r.Headers)
+				assert.Equal(t, "bar-stream", client.records[1].Topic)
+			}
+		})
+	}
+}
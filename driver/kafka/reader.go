@@ -20,15 +20,63 @@ const (
 	// ReaderTaskInitialOffsetKey is the argument key to set up a reader to start reading messages
 	// from a specific offset from the topic's partition append log.
 	ReaderTaskInitialOffsetKey string = "kafka-init-offset"
+	// ReaderTaskFailurePolicyKey is the argument key to set up the FailurePolicy of a reader, overriding
+	// ReaderConfig.FailurePolicy.
+	ReaderTaskFailurePolicyKey string = "kafka-failure-policy"
+	// ReaderTaskStartTimeKey is the argument key (time.Time) to set up a replay reader to start reading messages
+	// written at (or after) a specific time from every partition.
+	ReaderTaskStartTimeKey string = "kafka-start-time"
+	// ReaderTaskEndTimeKey is the argument key (time.Time) to set up a replay reader to stop reading messages
+	// from a partition once a message written at (or after) a specific time was found.
+	ReaderTaskEndTimeKey string = "kafka-end-time"
+	// ReaderTaskEndOffsetKey is the argument key (int64) to set up a replay reader to stop reading messages
+	// from a partition once a specific offset (exclusive) was reached.
+	ReaderTaskEndOffsetKey string = "kafka-end-offset"
 )
 
 // ReaderConfig is the configuration for Reader.
 type ReaderConfig struct {
 	kafka.ReaderConfig
 	HandlerTimeout time.Duration // Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	// Function called after topic partitions were assigned to the consumer group member (optional).
+	OnPartitionsAssigned PartitionHookFunc
+	// Function called before assigned topic partitions are revoked from the consumer group member
+	// (e.g. rebalance or Reader shutdown) and after every handler of those partitions returned (optional).
+	// Use it to flush per-partition state.
+	OnPartitionsRevoked PartitionHookFunc
+	// Run one handler goroutine per assigned partition. Messages are processed in order within each partition.
+	// Otherwise, handlers of every partition are serialized.
+	PartitionHandlers bool
+	// Action to take when a handler fails. Might be overridden per task using ReaderTaskFailurePolicyKey.
+	FailurePolicy FailurePolicy
+	// Time durations to wait before each retry when using RetryOnFailure (e.g. retrier.ExponentialBackoff).
+	RetryBackoff []time.Duration
+	// Action to take when every RetryBackoff interval was used. StopOnFailure by default.
+	RetryExhaustedPolicy FailurePolicy
+	// Writer used by DeadLetterOnFailure.
+	DeadLetterWriter streams.Writer
+}
+
+// isGenerationAware indicates if a consumer group reader must track group generations (i.e. partition assignments).
+//
+// Consumer group readers retrying failed messages are generation-aware as seeking back requires partition readers.
+func (c ReaderConfig) isGenerationAware() bool {
+	return c.GroupID != "" && (c.PartitionHandlers || c.OnPartitionsAssigned != nil || c.OnPartitionsRevoked != nil ||
+		c.FailurePolicy == RetryOnFailure)
 }
 
 // A Reader type is the concrete implementation of streams.Reader using Apache Kafka.
+//
+// Consumer group readers run one partition reader per assigned partition if ReaderConfig.PartitionHandlers,
+// ReaderConfig.OnPartitionsAssigned or ReaderConfig.OnPartitionsRevoked is set. Handlers might retrieve the partition
+// position of a message through PartitionFromContext.
+//
+// Consumer group offsets are committed asynchronously in batches every ReaderConfig.CommitInterval (one second by
+// default). Handler failures are resolved using ReaderConfig.FailurePolicy.
+//
+// Readers become replay readers if ReaderTaskStartTimeKey, ReaderTaskEndTimeKey or ReaderTaskEndOffsetKey is set.
+// Replay readers read every partition of a topic (or ReaderTaskPartitionIDKey) within bounds resolved per partition,
+// returning once every partition passed its end bound. Replay readers do not join consumer groups.
 type Reader struct {
 	cfg ReaderConfig
 }
@@ -49,6 +97,9 @@ func NewReader(cfg ReaderConfig) Reader {
 	if cfg.HandlerTimeout == 0 {
 		cfg.HandlerTimeout = time.Second * 30
 	}
+	if cfg.CommitInterval == 0 {
+		cfg.CommitInterval = time.Second
+	}
 	return Reader{
 		cfg: cfg,
 	}
@@ -59,11 +110,19 @@ func (r Reader) Read(ctx context.Context, task streams.ReadTask) (err error) {
 	r.cfg.GroupID = genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskGroupIDKey])
 	r.cfg.Partition = genericutil.SafeCast[int](task.ExternalArgs[ReaderTaskPartitionIDKey])
 	r.cfg.StartOffset = genericutil.SafeCast[int64](task.ExternalArgs[ReaderTaskInitialOffsetKey])
+	if policy, ok := task.ExternalArgs[ReaderTaskFailurePolicyKey].(FailurePolicy); ok {
+		r.cfg.FailurePolicy = policy
+	}
+	if args, ok := newReplayArgs(task); ok {
+		return r.readReplay(ctx, task, args)
+	} else if r.cfg.isGenerationAware() {
+		return r.readGroup(ctx, task)
+	}
 
 	kReader := kafka.NewReader(r.cfg.ReaderConfig)
 	defer func() {
 		if errClosure := kReader.Close(); errClosure != nil {
-			r.cfg.ErrorLogger.Printf("error occurred closing reader, %s", err.Error())
+			r.cfg.ErrorLogger.Printf("error occurred closing reader, %s", errClosure.Error())
 		}
 	}()
 	if r.cfg.GroupID == "" && r.cfg.StartOffset != 0 {
@@ -74,7 +133,10 @@ func (r Reader) Read(ctx context.Context, task streams.ReadTask) (err error) {
 		}
 	}
 
-	var kMsg kafka.Message
+	var (
+		kMsg    kafka.Message
+		attempt int
+	)
 	for {
 		kMsg, err = kReader.FetchMessage(ctx)
 		if err != nil {
@@ -82,28 +144,39 @@ func (r Reader) Read(ctx context.Context, task streams.ReadTask) (err error) {
 			break
 		}
 
-		scopedCtx, cancel := context.WithTimeout(ctx, r.cfg.HandlerTimeout)
+		scopedCtx, cancel := context.WithTimeout(newPartitionContext(ctx, kMsg), r.cfg.HandlerTimeout)
 		msg := unmarshalMessage(kMsg)
 		stats := kReader.Stats()
 		msg.Headers[HeaderClientID] = stats.ClientID
 		msg.Headers[HeaderGroupID] = r.cfg.GroupID
 		msg.Headers[HeaderInitialOffset] = strconv.Itoa(int(r.cfg.StartOffset))
 
-		if errHandler := task.Handler(scopedCtx, msg); errHandler != nil {
-			cancel()
-			return
-		}
+		errHandler := task.Handler(scopedCtx, msg)
 		cancel()
+		if errHandler != nil {
+			res := r.resolveFailure(ctx, r.cfg.FailurePolicy, attempt, msg, errHandler)
+			if res.err != nil {
+				return res.err
+			} else if res.retry {
+				// only partitioned readers retry here as consumer group readers are generation-aware when retrying
+				attempt++
+				if err = waitBackoff(ctx, res.backoff); err != nil {
+					break
+				}
+				if err = kReader.SetOffset(kMsg.Offset); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		attempt = 0
 
-		var errCommit error
 		if r.cfg.GroupID == "" {
-			// commit is not available when reading directly from partitions
-			errCommit = kReader.SetOffset(kMsg.Offset + 1)
-		} else {
-			errCommit = kReader.CommitMessages(ctx, kMsg)
+			// commit is not available when reading directly from partitions, the reader keeps the next offset
+			continue
 		}
-
-		if errCommit != nil {
+		// commits are sent asynchronously in batches if CommitInterval is set
+		if errCommit := kReader.CommitMessages(ctx, kMsg); errCommit != nil {
 			r.cfg.ErrorLogger.Printf("error occurred while committing message, %s", errCommit.Error())
 		}
 	}
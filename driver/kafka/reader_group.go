@@ -0,0 +1,272 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/segmentio/kafka-go"
+)
+
+// groupReadState is the state shared by partition readers of every consumer group generation.
+type groupReadState struct {
+	ctx       context.Context // cancelled when partition readers must stop.
+	cancel    context.CancelFunc
+	handlerMu sync.Mutex // serializes handlers if ReaderConfig.PartitionHandlers is not set.
+	errOnce   sync.Once
+	err       error
+}
+
+// stop cancels every partition reader, keeping the first error.
+func (s *groupReadState) stop(err error) {
+	s.errOnce.Do(func() {
+		s.err = err
+	})
+	s.cancel()
+}
+
+// readGroup reads messages from the task stream as a consumer group member, running one partition reader per
+// assigned partition on each group generation.
+func (r Reader) readGroup(ctx context.Context, task streams.ReadTask) error {
+	startOffset := r.cfg.StartOffset
+	if startOffset != kafka.FirstOffset && startOffset != kafka.LastOffset {
+		startOffset = kafka.FirstOffset
+	}
+	group, err := kafka.NewConsumerGroup(kafka.ConsumerGroupConfig{
+		ID:                     r.cfg.GroupID,
+		Brokers:                r.cfg.Brokers,
+		Dialer:                 r.cfg.Dialer,
+		Topics:                 []string{task.Stream},
+		GroupBalancers:         r.cfg.GroupBalancers,
+		HeartbeatInterval:      r.cfg.HeartbeatInterval,
+		PartitionWatchInterval: r.cfg.PartitionWatchInterval,
+		WatchPartitionChanges:  r.cfg.WatchPartitionChanges,
+		SessionTimeout:         r.cfg.SessionTimeout,
+		RebalanceTimeout:       r.cfg.RebalanceTimeout,
+		JoinGroupBackoff:       r.cfg.JoinGroupBackoff,
+		RetentionTime:          r.cfg.RetentionTime,
+		StartOffset:            startOffset,
+		Logger:                 r.cfg.Logger,
+		ErrorLogger:            r.cfg.ErrorLogger,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// closing the group ends the current generation, waiting for its partition readers to exit.
+		if errClosure := group.Close(); errClosure != nil {
+			r.cfg.ErrorLogger.Printf("error occurred closing consumer group, %s", errClosure.Error())
+		}
+	}()
+
+	state := &groupReadState{}
+	state.ctx, state.cancel = context.WithCancel(ctx)
+	defer state.cancel()
+	for {
+		gen, errNext := group.Next(state.ctx)
+		if errNext != nil {
+			if state.err != nil {
+				return state.err
+			} else if errors.Is(errNext, context.Canceled) || errors.Is(errNext, kafka.ErrGroupClosed) {
+				return nil
+			}
+			return errNext
+		}
+		r.startGeneration(gen, task, state)
+	}
+}
+
+// startGeneration runs one partition reader per partition assigned in gen, calling partition hooks.
+func (r Reader) startGeneration(gen *kafka.Generation, task streams.ReadTask, state *groupReadState) {
+	assignments := gen.Assignments[task.Stream]
+	if len(assignments) == 0 {
+		return
+	}
+	partitions := make([]TopicPartition, 0, len(assignments))
+	for _, assignment := range assignments {
+		partitions = append(partitions, TopicPartition{
+			Topic:     task.Stream,
+			Partition: assignment.ID,
+		})
+	}
+	if r.cfg.OnPartitionsAssigned != nil {
+		hookCtx, cancel := context.WithTimeout(state.ctx, r.cfg.HandlerTimeout)
+		r.cfg.OnPartitionsAssigned(hookCtx, partitions)
+		cancel()
+	}
+
+	committer := newOffsetCommitter(gen)
+	inFlight := &sync.WaitGroup{}
+	inFlight.Add(len(assignments))
+	for _, assignment := range assignments {
+		assignment := assignment
+		gen.Start(func(genCtx context.Context) {
+			defer inFlight.Done()
+			if err := r.readPartition(genCtx, committer, task, assignment, state); err != nil {
+				r.cfg.ErrorLogger.Printf("error occurred while reading partition <%d>, %s", assignment.ID, err.Error())
+				state.stop(err)
+			}
+		})
+	}
+	gen.Start(func(genCtx context.Context) {
+		ticker := time.NewTicker(r.cfg.CommitInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-genCtx.Done():
+				return
+			case <-ticker.C:
+				r.commitOffsets(committer)
+			}
+		}
+	})
+	gen.Start(func(genCtx context.Context) {
+		<-genCtx.Done()
+		inFlight.Wait()
+		r.commitOffsets(committer)
+		if r.cfg.OnPartitionsRevoked != nil {
+			// state.ctx might be already cancelled (i.e. Reader shutdown).
+			hookCtx, cancel := context.WithTimeout(context.Background(), r.cfg.HandlerTimeout)
+			r.cfg.OnPartitionsRevoked(hookCtx, partitions)
+			cancel()
+		}
+	})
+}
+
+func (r Reader) commitOffsets(committer *offsetCommitter) {
+	if err := committer.commit(); err != nil {
+		r.cfg.ErrorLogger.Printf("error occurred while committing offsets, %s", err.Error())
+	}
+}
+
+// readPartition reads messages from an assigned partition until the generation ends or state is stopped, committing
+// offsets through committer.
+func (r Reader) readPartition(genCtx context.Context, committer *offsetCommitter, task streams.ReadTask,
+	assignment kafka.PartitionAssignment, state *groupReadState) error {
+	ctx, cancel := context.WithCancel(genCtx)
+	defer cancel()
+	go func() {
+		select {
+		case <-state.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	cfg := r.cfg.ReaderConfig
+	cfg.GroupID = ""
+	cfg.GroupTopics = nil
+	cfg.Topic = task.Stream
+	cfg.Partition = assignment.ID
+	kReader := kafka.NewReader(cfg)
+	defer func() {
+		if errClosure := kReader.Close(); errClosure != nil {
+			r.cfg.ErrorLogger.Printf("error occurred closing partition reader, %s", errClosure.Error())
+		}
+	}()
+	if err := kReader.SetOffset(assignment.Offset); err != nil {
+		return err
+	}
+
+	attempt := 0
+	for {
+		kMsg, err := kReader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		msg := unmarshalMessage(kMsg)
+		msg.Headers[HeaderClientID] = kReader.Stats().ClientID
+		msg.Headers[HeaderGroupID] = r.cfg.GroupID
+		msg.Headers[HeaderInitialOffset] = strconv.Itoa(int(assignment.Offset))
+		if errHandler := r.handlePartitionMessage(ctx, task, kMsg, msg, state); errHandler != nil {
+			res := r.resolveFailure(ctx, r.cfg.FailurePolicy, attempt, msg, errHandler)
+			if res.err != nil {
+				return res.err
+			} else if res.retry {
+				attempt++
+				if errWait := waitBackoff(ctx, res.backoff); errWait != nil {
+					return nil
+				}
+				if err = kReader.SetOffset(kMsg.Offset); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		attempt = 0
+		committer.mark(kMsg)
+	}
+}
+
+func (r Reader) handlePartitionMessage(ctx context.Context, task streams.ReadTask, kMsg kafka.Message,
+	msg streams.Message, state *groupReadState) error {
+	if !r.cfg.PartitionHandlers {
+		state.handlerMu.Lock()
+		defer state.handlerMu.Unlock()
+	}
+	scopedCtx, cancel := context.WithTimeout(newPartitionContext(ctx, kMsg), r.cfg.HandlerTimeout)
+	defer cancel()
+	return task.Handler(scopedCtx, msg)
+}
+
+// offsetCommitter keeps the next offset of every partition assigned in a consumer group generation, committing them
+// in batches.
+type offsetCommitter struct {
+	gen     *kafka.Generation
+	mu      sync.Mutex
+	offsets map[string]map[int]int64
+}
+
+func newOffsetCommitter(gen *kafka.Generation) *offsetCommitter {
+	return &offsetCommitter{
+		gen:     gen,
+		offsets: make(map[string]map[int]int64),
+	}
+}
+
+// mark schedules the commit of the offset next to msg.
+func (c *offsetCommitter) mark(msg kafka.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.offsets[msg.Topic]; !ok {
+		c.offsets[msg.Topic] = make(map[int]int64)
+	}
+	c.offsets[msg.Topic][msg.Partition] = msg.Offset + 1
+}
+
+// commit commits every scheduled offset to the consumer group coordinator. Offsets are scheduled again on failure
+// unless a newer offset was scheduled meanwhile.
+func (c *offsetCommitter) commit() error {
+	c.mu.Lock()
+	offsets := c.offsets
+	c.offsets = make(map[string]map[int]int64)
+	c.mu.Unlock()
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	err := c.gen.CommitOffsets(offsets)
+	if err == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for topic, partitions := range offsets {
+		if _, ok := c.offsets[topic]; !ok {
+			c.offsets[topic] = make(map[int]int64)
+		}
+		for partition, offset := range partitions {
+			if _, ok := c.offsets[topic][partition]; !ok {
+				c.offsets[topic][partition] = offset
+			}
+		}
+	}
+	return err
+}
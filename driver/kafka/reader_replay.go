@@ -0,0 +1,332 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/segmentio/kafka-go"
+)
+
+// replayBounds are the offset bounds of a partition replay.
+type replayBounds struct {
+	start int64 // first offset to read.
+	end   int64 // offset to stop at (exclusive). No offset bound if negative.
+}
+
+func (b replayBounds) isDone(offset int64) bool {
+	return b.end >= 0 && offset >= b.end
+}
+
+// limitEnd sets endOffset as end bound if lower than the current one.
+func (b *replayBounds) limitEnd(endOffset int64) {
+	if b.end < 0 || endOffset < b.end {
+		b.end = endOffset
+	}
+}
+
+// offsetLister lists partition offsets. Implemented by kafka.Client.
+type offsetLister interface {
+	ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error)
+}
+
+// replayArgs are the ReadTask arguments of a replay reader.
+type replayArgs struct {
+	startTime time.Time
+	endTime   time.Time
+	endOffset int64
+	partition int
+	// read only from partition instead of every topic partition.
+	isPartitioned bool
+}
+
+func newReplayArgs(task streams.ReadTask) (replayArgs, bool) {
+	args := replayArgs{
+		endOffset: -1,
+	}
+	startTime, hasStart := task.ExternalArgs[ReaderTaskStartTimeKey].(time.Time)
+	endTime, hasEnd := task.ExternalArgs[ReaderTaskEndTimeKey].(time.Time)
+	endOffset, hasEndOffset := task.ExternalArgs[ReaderTaskEndOffsetKey].(int64)
+	if !hasStart && !hasEnd && !hasEndOffset {
+		return args, false
+	}
+	args.startTime = startTime
+	args.endTime = endTime
+	if hasEndOffset {
+		args.endOffset = endOffset
+	}
+	args.partition, args.isPartitioned = task.ExternalArgs[ReaderTaskPartitionIDKey].(int)
+	return args, true
+}
+
+// readReplay reads messages from every partition (or from ReaderTaskPartitionIDKey) of the task stream within the
+// replay bounds, returning once every partition passed its end bound. Replay readers do not join consumer groups.
+func (r Reader) readReplay(ctx context.Context, task streams.ReadTask, args replayArgs) error {
+	client := r.newClient()
+	partitions := []int{args.partition}
+	if !args.isPartitioned {
+		var err error
+		if partitions, err = r.listPartitions(ctx, client, task.Stream); err != nil {
+			return err
+		}
+	}
+	bounds, err := r.resolveReplayBounds(ctx, client, task.Stream, partitions, args)
+	if err != nil {
+		return err
+	}
+
+	state := &groupReadState{}
+	state.ctx, state.cancel = context.WithCancel(ctx)
+	defer state.cancel()
+	inFlight := sync.WaitGroup{}
+	for _, partition := range partitions {
+		partitionBounds := bounds[partition]
+		if partitionBounds.isDone(partitionBounds.start) {
+			continue
+		}
+		inFlight.Add(1)
+		go func(partition int) {
+			defer inFlight.Done()
+			errRead := r.readReplayPartition(client, task, partition, partitionBounds, args.endTime, state)
+			if errRead != nil {
+				r.cfg.ErrorLogger.Printf("error occurred while replaying partition <%d>, %s", partition, errRead.Error())
+				state.stop(errRead)
+			}
+		}(partition)
+	}
+	inFlight.Wait()
+	return state.err
+}
+
+// readReplayPartition reads partition messages within bounds. As offsets of future end times are not available when
+// resolving bounds, partition end offset is resolved again once endTime is reached. Thus, fetches are not blocked
+// forever if no message is written after endTime.
+func (r Reader) readReplayPartition(client offsetLister, task streams.ReadTask, partition int, bounds replayBounds,
+	endTime time.Time, state *groupReadState) error {
+	cfg := r.cfg.ReaderConfig
+	cfg.GroupID = ""
+	cfg.GroupTopics = nil
+	cfg.Topic = task.Stream
+	cfg.Partition = partition
+	kReader := kafka.NewReader(cfg)
+	defer func() {
+		if errClosure := kReader.Close(); errClosure != nil {
+			r.cfg.ErrorLogger.Printf("error occurred closing partition reader, %s", errClosure.Error())
+		}
+	}()
+	if err := kReader.SetOffset(bounds.start); err != nil {
+		return err
+	}
+
+	fetchCtx := state.ctx
+	if endTime.After(time.Now()) {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithDeadline(state.ctx, endTime)
+		defer cancel()
+	}
+
+	attempt := 0
+	for {
+		kMsg, err := kReader.FetchMessage(fetchCtx)
+		if err != nil {
+			if state.ctx.Err() != nil {
+				return nil
+			} else if fetchCtx.Err() == nil {
+				return err
+			}
+
+			// end time reached, end offset is available now
+			endOffsets, errOffsets := listEndOffsets(state.ctx, client, task.Stream, []int{partition}, endTime)
+			if errOffsets != nil {
+				return errOffsets
+			}
+			bounds.limitEnd(endOffsets[partition])
+			if bounds.isDone(kReader.Offset()) {
+				return nil
+			}
+			fetchCtx = state.ctx
+			continue
+		}
+		if bounds.isDone(kMsg.Offset) || (!endTime.IsZero() && !kMsg.Time.Before(endTime)) {
+			return nil
+		}
+
+		msg := unmarshalMessage(kMsg)
+		msg.Headers[HeaderClientID] = kReader.Stats().ClientID
+		msg.Headers[HeaderGroupID] = ""
+		msg.Headers[HeaderInitialOffset] = strconv.Itoa(int(bounds.start))
+		if errHandler := r.handlePartitionMessage(state.ctx, task, kMsg, msg, state); errHandler != nil {
+			res := r.resolveFailure(state.ctx, r.cfg.FailurePolicy, attempt, msg, errHandler)
+			if res.err != nil {
+				return res.err
+			} else if res.retry {
+				attempt++
+				if errWait := waitBackoff(state.ctx, res.backoff); errWait != nil {
+					return nil
+				}
+				if err = kReader.SetOffset(kMsg.Offset); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		attempt = 0
+		if bounds.isDone(kMsg.Offset + 1) {
+			return nil
+		}
+	}
+}
+
+func (r Reader) newClient() *kafka.Client {
+	client := &kafka.Client{
+		Addr: kafka.TCP(r.cfg.Brokers...),
+	}
+	if r.cfg.Dialer != nil {
+		client.Transport = &kafka.Transport{
+			Dial:     r.cfg.Dialer.DialFunc,
+			ClientID: r.cfg.Dialer.ClientID,
+			TLS:      r.cfg.Dialer.TLS,
+			SASL:     r.cfg.Dialer.SASLMechanism,
+		}
+	}
+	return client
+}
+
+func (r Reader) listPartitions(ctx context.Context, client *kafka.Client, topic string) ([]int, error) {
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{
+		Topics: []string{topic},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var partitions []int
+	for _, t := range metadata.Topics {
+		if t.Error != nil {
+			return nil, t.Error
+		}
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+	}
+	return partitions, nil
+}
+
+// resolveReplayBounds resolves the offset bounds of each partition using ListOffsets requests (i.e. offsets for
+// times).
+func (r Reader) resolveReplayBounds(ctx context.Context, client offsetLister, topic string, partitions []int,
+	args replayArgs) (map[int]replayBounds, error) {
+	lastOffsets, err := listOffsets(ctx, client, topic, partitions, kafka.LastOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	var startOffsets map[int]int64
+	switch {
+	case !args.startTime.IsZero():
+		startOffsets, err = listOffsets(ctx, client, topic, partitions, args.startTime.UnixMilli())
+	case r.cfg.StartOffset > 0:
+		startOffsets = make(map[int]int64, len(partitions))
+		for _, partition := range partitions {
+			startOffsets[partition] = r.cfg.StartOffset
+		}
+	case r.cfg.StartOffset == kafka.LastOffset:
+		startOffsets = lastOffsets
+	default:
+		startOffsets, err = listOffsets(ctx, client, topic, partitions, kafka.FirstOffset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var endOffsets map[int]int64
+	if !args.endTime.IsZero() && !args.endTime.After(time.Now()) {
+		// future end times are resolved by partition readers once reached as offsets are not available yet
+		if endOffsets, err = resolveEndOffsets(ctx, client, topic, partitions, args.endTime, lastOffsets); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds := make(map[int]replayBounds, len(partitions))
+	for _, partition := range partitions {
+		b := replayBounds{
+			start: startOffsets[partition],
+			end:   args.endOffset,
+		}
+		if b.start < 0 {
+			// no message was written after the start time
+			b.start = lastOffsets[partition]
+		}
+		if endOffsets != nil {
+			b.limitEnd(endOffsets[partition])
+		}
+		bounds[partition] = b
+	}
+	return bounds, nil
+}
+
+// listEndOffsets retrieves the offset of each partition to stop a replay at if ended at endTime.
+func listEndOffsets(ctx context.Context, client offsetLister, topic string, partitions []int,
+	endTime time.Time) (map[int]int64, error) {
+	lastOffsets, err := listOffsets(ctx, client, topic, partitions, kafka.LastOffset)
+	if err != nil {
+		return nil, err
+	}
+	return resolveEndOffsets(ctx, client, topic, partitions, endTime, lastOffsets)
+}
+
+func resolveEndOffsets(ctx context.Context, client offsetLister, topic string, partitions []int, endTime time.Time,
+	lastOffsets map[int]int64) (map[int]int64, error) {
+	endOffsets, err := listOffsets(ctx, client, topic, partitions, endTime.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	for partition, offset := range endOffsets {
+		if offset < 0 {
+			// no message was written after the end time
+			endOffsets[partition] = lastOffsets[partition]
+		}
+	}
+	return endOffsets, nil
+}
+
+// listOffsets retrieves the offset of each partition for timestamp (milliseconds), kafka.FirstOffset or
+// kafka.LastOffset. Partitions with no message written after timestamp are resolved to -1.
+func listOffsets(ctx context.Context, client offsetLister, topic string, partitions []int,
+	timestamp int64) (map[int]int64, error) {
+	reqs := make([]kafka.OffsetRequest, 0, len(partitions))
+	for _, partition := range partitions {
+		reqs = append(reqs, kafka.OffsetRequest{
+			Partition: partition,
+			Timestamp: timestamp,
+		})
+	}
+	res, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{
+			topic: reqs,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int]int64, len(partitions))
+	for _, p := range res.Topics[topic] {
+		if p.Error != nil {
+			return nil, p.Error
+		}
+		switch timestamp {
+		case kafka.FirstOffset:
+			offsets[p.Partition] = p.FirstOffset
+		case kafka.LastOffset:
+			offsets[p.Partition] = p.LastOffset
+		default:
+			offsets[p.Partition] = -1
+			for offset := range p.Offsets {
+				offsets[p.Partition] = offset
+			}
+		}
+	}
+	return offsets, nil
+}
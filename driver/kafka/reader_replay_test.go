@@ -0,0 +1,216 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReplayArgs(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		inArgs   map[string]any
+		wantArgs replayArgs
+		wantOk   bool
+	}{
+		{
+			name:     "not a replay",
+			inArgs:   map[string]any{ReaderTaskPartitionIDKey: 1},
+			wantArgs: replayArgs{endOffset: -1},
+		},
+		{
+			name:   "start time",
+			inArgs: map[string]any{ReaderTaskStartTimeKey: now},
+			wantArgs: replayArgs{
+				startTime: now,
+				endOffset: -1,
+			},
+			wantOk: true,
+		},
+		{
+			name: "partitioned end bounds",
+			inArgs: map[string]any{
+				ReaderTaskEndTimeKey:     now,
+				ReaderTaskEndOffsetKey:   int64(10),
+				ReaderTaskPartitionIDKey: 2,
+			},
+			wantArgs: replayArgs{
+				endTime:       now,
+				endOffset:     10,
+				partition:     2,
+				isPartitioned: true,
+			},
+			wantOk: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, ok := newReplayArgs(streams.ReadTask{ExternalArgs: tt.inArgs})
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestReplayBounds_IsDone(t *testing.T) {
+	assert.False(t, replayBounds{start: 0, end: -1}.isDone(100))
+	assert.False(t, replayBounds{start: 0, end: 2}.isDone(1))
+	assert.True(t, replayBounds{start: 0, end: 2}.isDone(2))
+}
+
+type offsetListerStub struct {
+	firstOffsets map[int]int64
+	lastOffsets  map[int]int64
+	timeOffsets  map[int64]map[int]int64 // partitions with no message after the timestamp are omitted
+	err          error
+}
+
+func (s offsetListerStub) ListOffsets(_ context.Context,
+	req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	res := &kafka.ListOffsetsResponse{
+		Topics: make(map[string][]kafka.PartitionOffsets, len(req.Topics)),
+	}
+	for topic, reqs := range req.Topics {
+		for _, offsetReq := range reqs {
+			partitionOffsets := kafka.PartitionOffsets{
+				Partition:   offsetReq.Partition,
+				FirstOffset: s.firstOffsets[offsetReq.Partition],
+				LastOffset:  s.lastOffsets[offsetReq.Partition],
+				Offsets:     map[int64]time.Time{},
+			}
+			if offset, ok := s.timeOffsets[offsetReq.Timestamp][offsetReq.Partition]; ok {
+				partitionOffsets.Offsets[offset] = time.UnixMilli(offsetReq.Timestamp)
+			}
+			res.Topics[topic] = append(res.Topics[topic], partitionOffsets)
+		}
+	}
+	return res, nil
+}
+
+func TestReader_ResolveReplayBounds(t *testing.T) {
+	now := time.Now()
+	pastTime := now.Add(-time.Hour)
+	startTime := now.Add(-time.Hour * 2)
+	lister := offsetListerStub{
+		firstOffsets: map[int]int64{0: 0, 1: 5},
+		lastOffsets:  map[int]int64{0: 100, 1: 50},
+		timeOffsets: map[int64]map[int]int64{
+			startTime.UnixMilli(): {0: 10},
+			pastTime.UnixMilli():  {0: 80},
+		},
+	}
+	tests := []struct {
+		name          string
+		inStartOffset int64
+		inArgs        replayArgs
+		inLister      offsetLister
+		wantBounds    map[int]replayBounds
+		wantErr       error
+	}{
+		{
+			name:   "unbounded",
+			inArgs: replayArgs{endOffset: -1},
+			wantBounds: map[int]replayBounds{
+				0: {start: 0, end: -1},
+				1: {start: 5, end: -1},
+			},
+		},
+		{
+			name:   "start time",
+			inArgs: replayArgs{startTime: startTime, endOffset: -1},
+			wantBounds: map[int]replayBounds{
+				0: {start: 10, end: -1},
+				1: {start: 50, end: -1}, // no message after start time
+			},
+		},
+		{
+			name:   "past end time",
+			inArgs: replayArgs{endTime: pastTime, endOffset: -1},
+			wantBounds: map[int]replayBounds{
+				0: {start: 0, end: 80},
+				1: {start: 5, end: 50}, // no message after end time
+			},
+		},
+		{
+			name:   "future end time",
+			inArgs: replayArgs{endTime: now.Add(time.Hour), endOffset: -1},
+			wantBounds: map[int]replayBounds{
+				0: {start: 0, end: -1},
+				1: {start: 5, end: -1},
+			},
+		},
+		{
+			name:   "end offset lower than end time",
+			inArgs: replayArgs{endTime: pastTime, endOffset: 20},
+			wantBounds: map[int]replayBounds{
+				0: {start: 0, end: 20},
+				1: {start: 5, end: 20},
+			},
+		},
+		{
+			name:          "start offset",
+			inStartOffset: 3,
+			inArgs:        replayArgs{endOffset: 40},
+			wantBounds: map[int]replayBounds{
+				0: {start: 3, end: 40},
+				1: {start: 3, end: 40},
+			},
+		},
+		{
+			name:          "last offset",
+			inStartOffset: kafka.LastOffset,
+			inArgs:        replayArgs{endOffset: -1},
+			wantBounds: map[int]replayBounds{
+				0: {start: 100, end: -1},
+				1: {start: 50, end: -1},
+			},
+		},
+		{
+			name:     "list failed",
+			inArgs:   replayArgs{endOffset: -1},
+			inLister: offsetListerStub{err: kafka.UnknownTopicOrPartition},
+			wantErr:  kafka.UnknownTopicOrPartition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Reader{cfg: ReaderConfig{}}
+			r.cfg.StartOffset = tt.inStartOffset
+			client := tt.inLister
+			if client == nil {
+				client = lister
+			}
+			bounds, err := r.resolveReplayBounds(context.TODO(), client, "foo", []int{0, 1}, tt.inArgs)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantBounds, bounds)
+		})
+	}
+}
+
+func TestListEndOffsets(t *testing.T) {
+	endTime := time.Now()
+	offsets, err := listEndOffsets(context.TODO(), offsetListerStub{
+		lastOffsets: map[int]int64{0: 100, 1: 50},
+		timeOffsets: map[int64]map[int]int64{
+			endTime.UnixMilli(): {0: 80},
+		},
+	}, "foo", []int{0, 1}, endTime)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 80, 1: 50}, offsets)
+
+	bounds := replayBounds{start: 0, end: 90}
+	bounds.limitEnd(offsets[0])
+	assert.Equal(t, int64(80), bounds.end)
+	bounds.limitEnd(offsets[1] + 100)
+	assert.Equal(t, int64(80), bounds.end)
+}
@@ -4,6 +4,8 @@ package kafka_test
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -172,3 +174,167 @@ func (s *readerSuite) TestReader_Group() {
 	}()
 	wg.Wait()
 }
+
+func (s *readerSuite) TestReader_GroupPartitionHandlers() {
+	s.publishMessage(s.topicGroup, "the quick brown fox partition handlers")
+	assigned := make(chan []streamskafka.TopicPartition, 1)
+	revoked := make(chan []streamskafka.TopicPartition, 1)
+	reader := streamskafka.NewReader(streamskafka.ReaderConfig{
+		ReaderConfig: kafka.ReaderConfig{
+			Brokers: []string{s.address},
+		},
+		OnPartitionsAssigned: func(_ context.Context, partitions []streamskafka.TopicPartition) {
+			assigned <- partitions
+		},
+		OnPartitionsRevoked: func(_ context.Context, partitions []streamskafka.TopicPartition) {
+			revoked <- partitions
+		},
+		PartitionHandlers: true,
+	})
+
+	rootCtx, cancelCtx := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancelCtx()
+	readCtx, cancelRead := context.WithCancel(rootCtx)
+	defer cancelRead()
+	task := streams.ReadTask{
+		Stream: s.topicGroup,
+		Handler: func(ctx context.Context, msg streams.Message) error {
+			if string(msg.Data) != "the quick brown fox partition handlers" {
+				return nil
+			}
+			info, ok := streamskafka.PartitionFromContext(ctx)
+			assert.True(s.T(), ok)
+			assert.Equal(s.T(), s.topicGroup, info.Topic)
+			assert.Equal(s.T(), 0, info.Partition)
+			assert.Equal(s.T(), info.HighWaterMark-info.Offset-1, info.Lag)
+			assert.Equal(s.T(), "test_group_partition_handlers", msg.Headers[streamskafka.HeaderGroupID])
+			cancelRead()
+			return nil
+		},
+		ExternalArgs: map[string]any{
+			streamskafka.ReaderTaskInitialOffsetKey: kafka.FirstOffset,
+			streamskafka.ReaderTaskGroupIDKey:       "test_group_partition_handlers",
+		},
+	}
+	err := reader.Read(readCtx, task)
+	require.NoError(s.T(), err)
+
+	wantPartitions := []streamskafka.TopicPartition{{Topic: s.topicGroup, Partition: 0}}
+	assert.Equal(s.T(), wantPartitions, <-assigned)
+	assert.Equal(s.T(), wantPartitions, <-revoked)
+}
+
+func (s *readerSuite) TestReader_FailurePolicy() {
+	topic := "org.alexandria.integration_test.read_suite_failure_policy"
+	createTopic(s.T(), s.address, topic)
+	defer deleteTopic(s.T(), s.address, topic)
+	s.publishMessage(topic, "the quick brown fox offset 0")
+	s.publishMessage(topic, "the quick brown fox offset 1")
+	reader := streamskafka.NewReader(streamskafka.ReaderConfig{
+		ReaderConfig: kafka.ReaderConfig{
+			Brokers: []string{s.address},
+		},
+		RetryBackoff:         []time.Duration{time.Millisecond * 10},
+		RetryExhaustedPolicy: streamskafka.SkipOnFailure,
+	})
+
+	rootCtx, cancelCtx := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancelCtx()
+	readCtx, cancelRead := context.WithCancel(rootCtx)
+	defer cancelRead()
+	received := make([]string, 0, 3)
+	task := streams.ReadTask{
+		Stream: topic,
+		Handler: func(ctx context.Context, msg streams.Message) error {
+			received = append(received, string(msg.Data))
+			if msg.Headers[streamskafka.HeaderCurrentOffset] == "0" {
+				return errors.New("generic error")
+			}
+			cancelRead()
+			return nil
+		},
+		ExternalArgs: map[string]any{
+			streamskafka.ReaderTaskPartitionIDKey:   0,
+			streamskafka.ReaderTaskFailurePolicyKey: streamskafka.RetryOnFailure,
+		},
+	}
+	err := reader.Read(readCtx, task)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{
+		"the quick brown fox offset 0",
+		"the quick brown fox offset 0",
+		"the quick brown fox offset 1",
+	}, received)
+}
+
+func (s *readerSuite) TestReader_Replay() {
+	topic := "org.alexandria.integration_test.read_suite_replay"
+	createTopic(s.T(), s.address, topic)
+	defer deleteTopic(s.T(), s.address, topic)
+	baseTime := time.Now().Add(-time.Hour * 3).Truncate(time.Millisecond)
+	w := &kafka.Writer{
+		Addr:  kafka.TCP(s.address),
+		Topic: topic,
+	}
+	defer w.Close()
+	for i := 0; i < 4; i++ {
+		err := w.WriteMessages(context.Background(), kafka.Message{
+			Value: []byte(strconv.Itoa(i)),
+			Time:  baseTime.Add(time.Hour * time.Duration(i)),
+		})
+		require.NoError(s.T(), err)
+	}
+
+	tests := []struct {
+		name   string
+		inArgs map[string]any
+		want   []string
+	}{
+		{
+			name: "start and end time",
+			inArgs: map[string]any{
+				streamskafka.ReaderTaskStartTimeKey: baseTime.Add(time.Minute * 30),
+				streamskafka.ReaderTaskEndTimeKey:   baseTime.Add(time.Hour * 2),
+			},
+			want: []string{"1"},
+		},
+		{
+			name: "end offset",
+			inArgs: map[string]any{
+				streamskafka.ReaderTaskEndOffsetKey: int64(2),
+			},
+			want: []string{"0", "1"},
+		},
+		{
+			name: "end time before start",
+			inArgs: map[string]any{
+				streamskafka.ReaderTaskEndTimeKey: baseTime.Add(-time.Hour),
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			reader := streamskafka.NewReader(streamskafka.ReaderConfig{
+				ReaderConfig: kafka.ReaderConfig{
+					Brokers: []string{s.address},
+				},
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+			defer cancel()
+			received := make([]string, 0, len(tt.want))
+			err := reader.Read(ctx, streams.ReadTask{
+				Stream: topic,
+				Handler: func(_ context.Context, msg streams.Message) error {
+					received = append(received, string(msg.Data))
+					return nil
+				},
+				ExternalArgs: tt.inArgs,
+			})
+			require.NoError(s.T(), err)
+			assert.NoError(s.T(), ctx.Err())
+			assert.Equal(s.T(), tt.want, received)
+		})
+	}
+}
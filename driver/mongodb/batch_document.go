@@ -0,0 +1,34 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/alexandria-oss/streams/proxy/egress"
+)
+
+// batchDocument is the egress collection document schema.
+type batchDocument struct {
+	BatchID        string     `bson:"_id"`
+	MessageCount   int        `bson:"message_count"`
+	RawData        []byte     `bson:"raw_data"`
+	InsertTime     time.Time  `bson:"insert_time"`
+	WriteRetries   int        `bson:"write_retries"`
+	LastWriteError string     `bson:"last_write_error,omitempty"`
+	Parked         bool       `bson:"parked"`
+	PublishTime    *time.Time `bson:"publish_time,omitempty"`
+}
+
+func (d batchDocument) toBatch() egress.Batch {
+	batch := egress.Batch{
+		BatchID:           d.BatchID,
+		TransportBatchRaw: d.RawData,
+		InsertTime:        d.InsertTime,
+		WriteRetries:      d.WriteRetries,
+		LastWriteError:    d.LastWriteError,
+		IsParked:          d.Parked,
+	}
+	if d.PublishTime != nil {
+		batch.PublishTime = *d.PublishTime
+	}
+	return batch
+}
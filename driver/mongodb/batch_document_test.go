@@ -0,0 +1,42 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchDocument_ToBatch(t *testing.T) {
+	insertTime := time.Now().UTC()
+	doc := batchDocument{
+		BatchID:        "123",
+		MessageCount:   2,
+		RawData:        []byte("foo"),
+		InsertTime:     insertTime,
+		WriteRetries:   3,
+		LastWriteError: "generic error",
+		Parked:         true,
+	}
+	assert.Equal(t, egress.Batch{
+		BatchID:           "123",
+		TransportBatchRaw: []byte("foo"),
+		InsertTime:        insertTime,
+		WriteRetries:      3,
+		LastWriteError:    "generic error",
+		IsParked:          true,
+	}, doc.toBatch())
+
+	doc.PublishTime = &insertTime
+	assert.True(t, doc.toBatch().IsPublished())
+}
+
+func TestWriter_Write(t *testing.T) {
+	w := Writer{}
+	assert.ErrorIs(t, w.Write(context.TODO(), nil), streams.ErrEmptyMessage)
+	assert.ErrorIs(t, w.Write(context.TODO(), []streams.Message{{ID: "1"}}), persistence.ErrTransactionContextNotFound)
+}
@@ -0,0 +1,138 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A EgressStorage is a MongoDB implementation of egress.Storage. Enables interaction with
+// a stream egress collection (aka. outbox). egress.StorageConfig.TableName is used as collection name.
+type EgressStorage struct {
+	cfg        egress.StorageConfig
+	collection *mongo.Collection
+}
+
+var _ egress.Storage = EgressStorage{}
+
+// NewEgressStorage allocates a new EgressStorage instance with default configuration but open to apply any egress.StorageOption(s).
+func NewEgressStorage(db *mongo.Database, opts ...egress.StorageOption) EgressStorage {
+	baseOpts := egress.StorageConfig{
+		TableName: egress.DefaultEgressTableName,
+	}
+	for _, o := range opts {
+		o.Apply(&baseOpts)
+	}
+	return NewEgressStorageWithConfig(db, baseOpts)
+}
+
+// NewEgressStorageWithConfig allocates a new EgressStorage instance with a specific egress.StorageConfig.
+func NewEgressStorageWithConfig(db *mongo.Database, cfg egress.StorageConfig) EgressStorage {
+	return EgressStorage{
+		cfg:        cfg,
+		collection: db.Collection(cfg.TableName),
+	}
+}
+
+func (e EgressStorage) GetBatch(ctx context.Context, batchID string) (egress.Batch, error) {
+	doc := batchDocument{}
+	if err := e.collection.FindOne(ctx, bson.M{"_id": batchID}).Decode(&doc); err != nil {
+		return egress.Batch{}, err
+	}
+	return doc.toBatch(), nil
+}
+
+func (e EgressStorage) Commit(ctx context.Context, batchID string) error {
+	if e.cfg.RetainPublished {
+		_, err := e.collection.UpdateByID(ctx, batchID, bson.M{
+			"$set": bson.M{"publish_time": time.Now().UTC()},
+		})
+		return err
+	}
+
+	_, err := e.collection.DeleteOne(ctx, bson.M{"_id": batchID})
+	return err
+}
+
+func (e EgressStorage) RecordFailure(ctx context.Context, batchID string, failure error) (bool, error) {
+	var lastErr string
+	if failure != nil {
+		lastErr = failure.Error()
+	}
+	isUnrecoverable := errors.Is(failure, streams.ErrUnrecoverable)
+	writeRetries := bson.M{"$add": bson.A{"$write_retries", 1}}
+	// using an aggregation pipeline update, so attempts are evaluated atomically.
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"write_retries":    writeRetries,
+			"last_write_error": bson.M{"$literal": lastErr}, // error messages might start with $
+			"parked": bson.M{"$or": bson.A{
+				"$parked",
+				isUnrecoverable,
+				bson.M{"$and": bson.A{
+					e.cfg.MaxAttempts > 0,
+					bson.M{"$gte": bson.A{writeRetries, e.cfg.MaxAttempts}},
+				}},
+			}},
+		}}},
+	}
+
+	doc := batchDocument{}
+	err := e.collection.FindOneAndUpdate(ctx, bson.M{"_id": batchID}, pipeline,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&doc)
+	if err != nil {
+		return false, err
+	}
+	return doc.Parked, nil
+}
+
+func (e EgressStorage) PurgePublished(ctx context.Context, threshold time.Time) (int64, error) {
+	res, err := e.collection.DeleteMany(ctx, bson.M{
+		"publish_time": bson.M{"$lt": threshold},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// ListPending retrieves pending batches sorted by their identifier. Batch identifiers are expected to be
+// time-sortable (e.g. KSUID), so batches are listed roughly by insert time.
+func (e EgressStorage) ListPending(ctx context.Context, threshold time.Time, cursor string,
+	limit int) ([]egress.Batch, string, error) {
+	filter := bson.M{
+		"parked":       false,
+		"publish_time": bson.M{"$exists": false},
+		"insert_time":  bson.M{"$lt": threshold},
+	}
+	if cursor != "" {
+		filter["_id"] = bson.M{"$gt": cursor}
+	}
+	cur, err := e.collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	docs := make([]batchDocument, 0, limit)
+	if err = cur.All(ctx, &docs); err != nil {
+		return nil, "", err
+	}
+	batches := make([]egress.Batch, 0, len(docs))
+	for _, doc := range docs {
+		batches = append(batches, doc.toBatch())
+	}
+
+	var nextCursor string
+	if len(batches) == limit {
+		nextCursor = batches[len(batches)-1].BatchID
+	}
+	return batches, nextCursor, nil
+}
@@ -0,0 +1,90 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsmongo "github.com/alexandria-oss/streams/driver/mongodb"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type egressStorageSuite struct {
+	suite.Suite
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+func TestEgressStorage(t *testing.T) {
+	suite.Run(t, &egressStorageSuite{})
+}
+
+func (s *egressStorageSuite) SetupSuite() {
+	client, err := mongo.Connect(context.Background(),
+		options.Client().ApplyURI("mongodb://localhost:27017/?replicaSet=rs0&directConnection=true"))
+	s.Require().NoError(err)
+	s.client = client
+	s.db = client.Database("sample_database")
+}
+
+func (s *egressStorageSuite) TearDownSuite() {
+	s.Assert().NoError(s.db.Drop(context.Background()))
+	s.Assert().NoError(s.client.Disconnect(context.Background()))
+}
+
+func (s *egressStorageSuite) TestStorage() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+	// transactions cannot implicitly create collections on MongoDB < 4.4
+	_ = s.db.CreateCollection(ctx, egress.DefaultEgressTableName)
+
+	storage := streamsmongo.NewEgressStorage(s.db, egress.WithMaxAttempts(2), egress.WithPublishedRetention(true))
+	writer := streamsmongo.NewWriter(s.db, streamsmongo.WriterConfig{})
+
+	session, err := s.client.StartSession()
+	s.Require().NoError(err)
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		scopedCtx := persistence.SetTransactionContext(sessCtx, persistence.TransactionContext[mongo.SessionContext]{
+			TransactionID: "123",
+			Tx:            sessCtx,
+		})
+		return nil, writer.Write(scopedCtx, []streams.Message{{ID: "1", StreamName: "foo"}})
+	})
+	s.Require().NoError(err)
+
+	batch, err := storage.GetBatch(ctx, "123")
+	s.Require().NoError(err)
+	s.Assert().Equal("123", batch.BatchID)
+
+	pending, cursor, err := storage.ListPending(ctx, time.Now().Add(time.Minute), "", 10)
+	s.Require().NoError(err)
+	s.Assert().Len(pending, 1)
+	s.Assert().Empty(cursor)
+
+	isParked, err := storage.RecordFailure(ctx, "123", errors.New("$generic error"))
+	s.Require().NoError(err)
+	s.Assert().False(isParked)
+	isParked, err = storage.RecordFailure(ctx, "123", errors.New("generic error"))
+	s.Require().NoError(err)
+	s.Assert().True(isParked)
+	pending, _, err = storage.ListPending(ctx, time.Now().Add(time.Minute), "", 10)
+	s.Require().NoError(err)
+	s.Assert().Empty(pending)
+
+	s.Require().NoError(storage.Commit(ctx, "123"))
+	total, err := storage.PurgePublished(ctx, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.Assert().Equal(int64(1), total)
+
+	_, err = storage.GetBatch(ctx, "123")
+	s.Assert().ErrorIs(err, mongo.ErrNoDocuments)
+}
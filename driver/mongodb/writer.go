@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WriterConfig is the configuration schema for MongoDB Writer.
+type WriterConfig struct {
+	CollectionName string      // collection to write message batches to be later published (default egress.DefaultEgressTableName).
+	Codec          codec.Codec // used to encode message batches (default codec.ProtocolBuffers).
+}
+
+// A Writer is a MongoDB writer.
+// This specific kind of streams.Writer is used by systems implementing the transactional outbox messaging pattern.
+// More in depth, a Writer instance will attempt to execute a transactional write into an <<egress collection>> where
+// all messages generated by the system will be stored, so they may be later publish by an <<egress proxy agent>>.
+//
+// Writer instances MUST be used along transaction context functions
+// (persistence.SetTransactionContext, persistence.GetTransactionContext) holding a mongo.SessionContext instance
+// with a started transaction. If no context is found, then Writer.Write will fail.
+//
+// Note: MongoDB transactions require a replica set or a sharded cluster.
+type Writer struct {
+	cfg        WriterConfig
+	collection *mongo.Collection
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates a new Writer instance.
+func NewWriter(db *mongo.Database, cfg WriterConfig) Writer {
+	if cfg.CollectionName == "" {
+		cfg.CollectionName = egress.DefaultEgressTableName
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = codec.ProtocolBuffers{}
+	}
+	return Writer{
+		cfg:        cfg,
+		collection: db.Collection(cfg.CollectionName),
+	}
+}
+
+// Write inserts a new batch of messages into the egress collection within the session transaction.
+//
+// Batch identifier will be taken from TransactionContext.TransactionID.
+func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) error {
+	if len(msgBatch) == 0 {
+		return streams.ErrEmptyMessage
+	}
+
+	txCtx, err := persistence.GetTransactionContext[mongo.SessionContext](ctx)
+	if err != nil {
+		return err
+	}
+
+	var msgBatchAny any = msgBatch
+	if w.cfg.Codec.ApplicationType() == codec.ProtocolBuffersApplicationType {
+		msgBatchAny = persistence.NewTransportMessageBatch(msgBatch)
+	}
+	encodedData, err := w.cfg.Codec.Encode(msgBatchAny)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.collection.InsertOne(txCtx.Tx, batchDocument{
+		BatchID:      txCtx.TransactionID,
+		MessageCount: len(msgBatch),
+		RawData:      encodedData,
+		InsertTime:   time.Now().UTC(),
+	})
+	return err
+}
@@ -0,0 +1,22 @@
+package nats
+
+const (
+	HeaderStreamKey   = "Streams-Stream-Key"   // Key of the stream from a message.
+	HeaderContentType = "Streams-Content-Type" // Type of data of a content from a message.
+	HeaderMessageTime = "Streams-Message-Time" // Timestamp in Unix milliseconds when the message was published.
+)
+
+const (
+	// HeaderStream is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the JetStream stream name storing the message.
+	HeaderStream = "streams-nats-stream"
+	// HeaderConsumer is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the JetStream durable consumer name of the reader instance.
+	HeaderConsumer = "streams-nats-consumer"
+	// HeaderStreamSequence is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the sequence of the message within its JetStream stream.
+	HeaderStreamSequence = "streams-nats-stream-seq"
+	// HeaderNumDelivered is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the count of times the message was delivered (i.e. 1 on the first delivery).
+	HeaderNumDelivered = "streams-nats-num-delivered"
+)
@@ -0,0 +1,49 @@
+package nats
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/nats-io/nats.go"
+)
+
+const fixedHeaderReaderInjectCount = 4
+
+func marshalMessage(msg streams.Message) *nats.Msg {
+	natsMsg := nats.NewMsg(msg.StreamName)
+	natsMsg.Data = msg.Data
+	natsMsg.Header.Set(nats.MsgIdHdr, msg.ID)
+	natsMsg.Header.Set(HeaderStreamKey, msg.StreamKey)
+	natsMsg.Header.Set(HeaderContentType, msg.ContentType)
+	natsMsg.Header.Set(HeaderMessageTime, strconv.FormatInt(msg.Time.UnixMilli(), 10))
+	for k, v := range msg.Headers {
+		natsMsg.Header.Set(k, v)
+	}
+	return natsMsg
+}
+
+func unmarshalMessage(natsMsg *nats.Msg) streams.Message {
+	msg := streams.Message{
+		StreamName: natsMsg.Subject,
+		Headers:    make(map[string]string, len(natsMsg.Header)+fixedHeaderReaderInjectCount),
+		Data:       natsMsg.Data,
+	}
+	for k := range natsMsg.Header {
+		val := natsMsg.Header.Get(k)
+		switch k {
+		case nats.MsgIdHdr:
+			msg.ID = val
+		case HeaderStreamKey:
+			msg.StreamKey = val
+		case HeaderContentType:
+			msg.ContentType = val
+		case HeaderMessageTime:
+			timeMilli, _ := strconv.ParseInt(val, 10, 64)
+			msg.Time = time.UnixMilli(timeMilli)
+		default:
+			msg.Headers[k] = val
+		}
+	}
+	return msg
+}
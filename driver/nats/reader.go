@@ -0,0 +1,195 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/internal/genericutil"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// ReaderTaskDurableKey is the argument key to set up the durable consumer name of a reader, overriding
+	// ReaderConfig.Durable.
+	ReaderTaskDurableKey string = "nats-durable"
+	// ReaderTaskStreamKey is the argument key to set up the JetStream stream name a reader consumes from. If not set,
+	// the stream is looked up using the task stream (i.e. the subject).
+	ReaderTaskStreamKey string = "nats-stream"
+)
+
+// ErrMissingDurable the reader has no durable consumer name.
+var ErrMissingDurable = errors.New("streams.nats: missing durable consumer name")
+
+// ReaderConfig is the configuration schema for NATS JetStream streams.Reader implementation.
+type ReaderConfig struct {
+	Logger         *log.Logger   // Logging instance preferably with log level at <<info>>.
+	ErrorLogger    *log.Logger   // Logging instance preferably with log level at <<error>>.
+	Durable        string        // Durable consumer name of the reader. Created if not exists.
+	BatchSize      int           // Maximum count of messages for each pull request.
+	FetchTimeout   time.Duration // Maximum duration for a pull request to wait for messages.
+	HandlerTimeout time.Duration // Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	// Total time JetStream waits for a message acknowledgement before delivering it again. Only used when
+	// creating the durable consumer.
+	AckWait time.Duration
+	// Maximum count of deliveries of a message. Unlimited if <= 0. Only used when creating the durable consumer.
+	MaxDeliver int
+	// Time durations to wait before delivering a failed message again, indexed by delivery count (the last
+	// duration is used once exhausted). Failed messages are delivered again immediately if empty.
+	NakBackoff []time.Duration
+}
+
+// A Reader type is the concrete implementation of streams.Reader using NATS JetStream durable pull consumers.
+//
+// Handler results are mapped to acknowledgements: messages are acknowledged (Ack) if the handler succeeded,
+// terminated (Term) if the handler returned a streams.ErrUnrecoverable error or negatively acknowledged with
+// a delay (NakWithDelay) otherwise.
+type Reader struct {
+	cfg ReaderConfig
+	js  nats.JetStreamContext
+}
+
+var _ streams.Reader = Reader{}
+
+// NewReader allocates a Reader instance.
+func NewReader(cfg ReaderConfig, js nats.JetStreamContext) Reader {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.nats: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 10
+	}
+	if cfg.FetchTimeout <= 0 {
+		cfg.FetchTimeout = time.Second * 5
+	}
+	if cfg.HandlerTimeout == 0 {
+		cfg.HandlerTimeout = time.Second * 30
+	}
+	if cfg.AckWait == 0 {
+		cfg.AckWait = time.Second * 30
+	}
+	return Reader{
+		cfg: cfg,
+		js:  js,
+	}
+}
+
+func (r Reader) Read(ctx context.Context, task streams.ReadTask) error {
+	if durable := genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskDurableKey]); durable != "" {
+		r.cfg.Durable = durable
+	}
+	if r.cfg.Durable == "" {
+		return ErrMissingDurable
+	}
+	stream := genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskStreamKey])
+	if stream == "" {
+		var err error
+		if stream, err = r.js.StreamNameBySubject(task.Stream, nats.Context(ctx)); err != nil {
+			return err
+		}
+	}
+	if err := r.ensureConsumer(ctx, stream, task.Stream); err != nil {
+		return err
+	}
+
+	// binding to an existing consumer keeps the durable consumer after unsubscribing
+	sub, err := r.js.PullSubscribe(task.Stream, r.cfg.Durable, nats.Bind(stream, r.cfg.Durable))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errUnsub := sub.Unsubscribe(); errUnsub != nil {
+			r.cfg.ErrorLogger.Printf("error occurred while unsubscribing, %s", errUnsub.Error())
+		}
+	}()
+
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, r.cfg.FetchTimeout)
+		msgs, errFetch := sub.Fetch(r.cfg.BatchSize, nats.Context(fetchCtx))
+		cancel()
+		if ctx.Err() != nil {
+			r.cfg.Logger.Printf("stopping stream reading process")
+			return nil
+		} else if errors.Is(errFetch, context.DeadlineExceeded) || errors.Is(errFetch, nats.ErrTimeout) {
+			continue
+		} else if errFetch != nil {
+			return errFetch
+		}
+
+		for _, msg := range msgs {
+			r.handleMessage(ctx, task, msg)
+		}
+	}
+}
+
+func (r Reader) ensureConsumer(ctx context.Context, stream, subject string) error {
+	_, err := r.js.ConsumerInfo(stream, r.cfg.Durable, nats.Context(ctx))
+	if err == nil || !errors.Is(err, nats.ErrConsumerNotFound) {
+		return err
+	}
+
+	_, err = r.js.AddConsumer(stream, &nats.ConsumerConfig{
+		Durable:       r.cfg.Durable,
+		FilterSubject: subject,
+		AckPolicy:     nats.AckExplicitPolicy,
+		AckWait:       r.cfg.AckWait,
+		MaxDeliver:    r.cfg.MaxDeliver,
+	}, nats.Context(ctx))
+	if err != nil {
+		return err
+	}
+	r.cfg.Logger.Printf("created durable consumer <%s> on stream <%s>", r.cfg.Durable, stream)
+	return nil
+}
+
+func (r Reader) handleMessage(ctx context.Context, task streams.ReadTask, natsMsg *nats.Msg) {
+	msg := unmarshalMessage(natsMsg)
+	var numDelivered uint64
+	if meta, err := natsMsg.Metadata(); err == nil {
+		numDelivered = meta.NumDelivered
+		msg.Headers[HeaderStream] = meta.Stream
+		msg.Headers[HeaderConsumer] = meta.Consumer
+		msg.Headers[HeaderStreamSequence] = strconv.FormatUint(meta.Sequence.Stream, 10)
+		msg.Headers[HeaderNumDelivered] = strconv.FormatUint(meta.NumDelivered, 10)
+	}
+
+	scopedCtx, cancel := context.WithTimeout(ctx, r.cfg.HandlerTimeout)
+	errHandle := task.Handler(scopedCtx, msg)
+	cancel()
+
+	var errAck error
+	switch {
+	case errHandle == nil:
+		errAck = natsMsg.Ack()
+	case errors.Is(errHandle, streams.ErrUnrecoverable):
+		errAck = natsMsg.Term()
+	default:
+		errAck = natsMsg.NakWithDelay(r.nakDelay(numDelivered))
+	}
+	if errAck != nil {
+		r.cfg.ErrorLogger.Printf("failed to acknowledge message <%s>, %s", msg.ID, errAck.Error())
+	}
+}
+
+func (r Reader) nakDelay(numDelivered uint64) time.Duration {
+	if len(r.cfg.NakBackoff) == 0 {
+		return 0
+	}
+	idx := int(numDelivered) - 1
+	if idx < 0 {
+		idx = 0
+	} else if idx >= len(r.cfg.NakBackoff) {
+		idx = len(r.cfg.NakBackoff) - 1
+	}
+	return r.cfg.NakBackoff[idx]
+}
@@ -0,0 +1,103 @@
+package nats_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsnats "github.com/alexandria-oss/streams/driver/nats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_Read(t *testing.T) {
+	js := newTestJetStream(t, "foo")
+	w := streamsnats.NewWriter(js)
+	msgTime := time.UnixMilli(time.Now().UnixMilli())
+	err := w.Write(context.Background(), []streams.Message{
+		{ID: "1", StreamName: "foo", StreamKey: "foo-key", ContentType: "text/plain",
+			Headers: map[string]string{"foo": "bar"}, Data: []byte("1"), Time: msgTime},
+		{ID: "2", StreamName: "foo", Data: []byte("2")},
+		{ID: "3", StreamName: "foo", Data: []byte("3")},
+	})
+	require.NoError(t, err)
+
+	r := streamsnats.NewReader(streamsnats.ReaderConfig{
+		FetchTimeout: time.Millisecond * 100,
+		NakBackoff:   []time.Duration{time.Millisecond * 10},
+	}, js)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	mu := sync.Mutex{}
+	received := make([]streams.Message, 0, 4)
+	task := streams.ReadTask{
+		Stream: "foo",
+		Handler: func(_ context.Context, msg streams.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, msg)
+			if len(received) == 4 {
+				cancel()
+			}
+			switch {
+			case msg.ID == "2" && msg.Headers[streamsnats.HeaderNumDelivered] == "1":
+				// message is delivered again after NakBackoff
+				return errors.New("generic error")
+			case msg.ID == "3":
+				// message is never delivered again
+				return fmt.Errorf("%w: invalid message", streams.ErrUnrecoverable)
+			}
+			return nil
+		},
+		ExternalArgs: map[string]any{
+			streamsnats.ReaderTaskDurableKey: "foo-durable",
+		},
+	}
+	err = r.Read(ctx, task)
+	require.NoError(t, err)
+
+	require.Len(t, received, 4)
+	assert.Equal(t, "1", received[0].ID)
+	assert.Equal(t, "foo", received[0].StreamName)
+	assert.Equal(t, "foo-key", received[0].StreamKey)
+	assert.Equal(t, "text/plain", received[0].ContentType)
+	assert.Equal(t, []byte("1"), received[0].Data)
+	assert.True(t, msgTime.Equal(received[0].Time))
+	assert.Equal(t, "bar", received[0].Headers["foo"])
+	assert.Equal(t, "foo-stream", received[0].Headers[streamsnats.HeaderStream])
+	assert.Equal(t, "foo-durable", received[0].Headers[streamsnats.HeaderConsumer])
+	assert.Equal(t, "1", received[0].Headers[streamsnats.HeaderStreamSequence])
+	assert.Equal(t, "1", received[0].Headers[streamsnats.HeaderNumDelivered])
+
+	ids := []string{received[1].ID, received[2].ID, received[3].ID}
+	assert.ElementsMatch(t, []string{"2", "3", "2"}, ids)
+	assert.Equal(t, "2", received[3].ID)
+	assert.Equal(t, "2", received[3].Headers[streamsnats.HeaderNumDelivered])
+
+	// durable consumer is kept once the reader stops, acknowledgements are sent asynchronously
+	assert.Eventually(t, func() bool {
+		info, errInfo := js.ConsumerInfo("foo-stream", "foo-durable")
+		return errInfo == nil && info.NumAckPending == 0 && info.NumPending == 0
+	}, time.Second*5, time.Millisecond*20)
+}
+
+func TestReader_ReadMissingDurable(t *testing.T) {
+	js := newTestJetStream(t)
+	r := streamsnats.NewReader(streamsnats.ReaderConfig{}, js)
+	err := r.Read(context.Background(), streams.ReadTask{Stream: "foo"})
+	assert.ErrorIs(t, err, streamsnats.ErrMissingDurable)
+}
+
+func TestReader_ReadMissingStream(t *testing.T) {
+	js := newTestJetStream(t)
+	r := streamsnats.NewReader(streamsnats.ReaderConfig{
+		Durable: "foo-durable",
+	}, js)
+	err := r.Read(context.Background(), streams.ReadTask{Stream: "foo"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package nats
+
+import (
+	"context"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/hashicorp/go-multierror"
+	"github.com/nats-io/nats.go"
+)
+
+// A Writer type is the concrete implementation of streams.Writer using NATS JetStream.
+//
+// Messages are published asynchronously and acknowledged by JetStream before Writer.Write returns. Message.ID is
+// set as the Nats-Msg-Id header, so JetStream discards duplicates within the stream duplicate window.
+type Writer struct {
+	js nats.JetStreamContext
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates a Writer instance.
+func NewWriter(js nats.JetStreamContext) Writer {
+	return Writer{
+		js: js,
+	}
+}
+
+func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) error {
+	futures := make([]nats.PubAckFuture, 0, len(msgBatch))
+	errs := &multierror.Error{}
+	for _, msg := range msgBatch {
+		future, err := w.js.PublishMsgAsync(marshalMessage(msg))
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		futures = append(futures, future)
+	}
+
+	for _, future := range futures {
+		select {
+		case <-ctx.Done():
+			return multierror.Append(errs, ctx.Err())
+		case <-future.Ok():
+		case err := <-future.Err():
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,133 @@
+package nats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsnats "github.com/alexandria-oss/streams/driver/nats"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestJetStream starts an embedded NATS server with JetStream enabled, adding a stream for every subject.
+func newTestJetStream(t *testing.T, subjects ...string) nats.JetStreamContext {
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+	go srv.Start()
+	require.True(t, srv.ReadyForConnections(time.Second*5))
+	t.Cleanup(srv.Shutdown)
+
+	conn, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	js, err := conn.JetStream()
+	require.NoError(t, err)
+	for _, subject := range subjects {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     subject + "-stream",
+			Subjects: []string{subject},
+		})
+		require.NoError(t, err)
+	}
+	return js
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		inMsgs      []streams.Message
+		inCancelCtx bool
+		wantMsgs    uint64
+		wantErr     bool
+	}{
+		{
+			name: "single",
+			inMsgs: []streams.Message{
+				{ID: "1", StreamName: "foo", Data: []byte("1")},
+			},
+			wantMsgs: 1,
+		},
+		{
+			name: "deduplicated",
+			inMsgs: []streams.Message{
+				{ID: "1", StreamName: "foo", Data: []byte("1")},
+				{ID: "2", StreamName: "foo", Data: []byte("2")},
+				{ID: "1", StreamName: "foo", Data: []byte("1")},
+			},
+			wantMsgs: 2,
+		},
+		{
+			name: "no stream",
+			inMsgs: []streams.Message{
+				{ID: "1", StreamName: "bar", Data: []byte("1")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cancelled",
+			inMsgs: []streams.Message{
+				{ID: "1", StreamName: "foo", Data: []byte("1")},
+			},
+			inCancelCtx: true,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			js := newTestJetStream(t, "foo")
+			w := streamsnats.NewWriter(js)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			if tt.inCancelCtx {
+				cancel()
+			}
+			err := w.Write(ctx, tt.inMsgs)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.wantErr {
+				return
+			}
+
+			info, err := js.StreamInfo("foo-stream")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMsgs, info.State.Msgs)
+		})
+	}
+}
+
+func TestWriter_WriteHeaders(t *testing.T) {
+	js := newTestJetStream(t, "foo")
+	w := streamsnats.NewWriter(js)
+	msgTime := time.UnixMilli(time.Now().UnixMilli())
+	err := w.Write(context.Background(), []streams.Message{
+		{
+			ID:          "1",
+			StreamName:  "foo",
+			StreamKey:   "foo-key",
+			ContentType: "application/json",
+			Headers:     map[string]string{"foo": "bar"},
+			Data:        []byte(`{"foo":"bar"}`),
+			Time:        msgTime,
+		},
+	})
+	require.NoError(t, err)
+
+	rawMsg, err := js.GetMsg("foo-stream", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "1", rawMsg.Header.Get(nats.MsgIdHdr))
+	assert.Equal(t, "foo-key", rawMsg.Header.Get(streamsnats.HeaderStreamKey))
+	assert.Equal(t, "application/json", rawMsg.Header.Get(streamsnats.HeaderContentType))
+	assert.Equal(t, "bar", rawMsg.Header.Get("foo"))
+	assert.Equal(t, []byte(`{"foo":"bar"}`), rawMsg.Data)
+}
@@ -0,0 +1,150 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// dbPool is the subset of pgxpool.Pool routines used by EgressStorage.
+type dbPool interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var _ dbPool = &pgxpool.Pool{}
+
+// A EgressStorage is a Postgres (pgx) implementation of egress.Storage. Enables interaction with
+// a stream egress table (aka. outbox).
+type EgressStorage struct {
+	db  dbPool
+	cfg egress.StorageConfig
+}
+
+var _ egress.Storage = EgressStorage{}
+
+func newEgressStorageDefaults() egress.StorageConfig {
+	return egress.StorageConfig{
+		TableName: egress.DefaultEgressTableName,
+	}
+}
+
+// NewEgressStorage allocates a new EgressStorage instance with default configuration but open to apply any egress.StorageOption(s).
+func NewEgressStorage(pool *pgxpool.Pool, opts ...egress.StorageOption) EgressStorage {
+	baseOpts := newEgressStorageDefaults()
+	for _, o := range opts {
+		o.Apply(&baseOpts)
+	}
+	return NewEgressStorageWithConfig(pool, baseOpts)
+}
+
+// NewEgressStorageWithConfig allocates a new EgressStorage instance with a specific egress.StorageConfig.
+func NewEgressStorageWithConfig(pool *pgxpool.Pool, cfg egress.StorageConfig) EgressStorage {
+	return EgressStorage{
+		db:  pool,
+		cfg: cfg,
+	}
+}
+
+func (e EgressStorage) GetBatch(ctx context.Context, batchID string) (egress.Batch, error) {
+	query := fmt.Sprintf("SELECT batch_id,raw_data,insert_time,write_retries,last_write_error,parked,publish_time FROM %s WHERE batch_id = $1",
+		e.cfg.TableName)
+
+	return scanBatch(e.db.QueryRow(ctx, query, batchID))
+}
+
+// scanBatch reads an egress.Batch from row columns: batch_id, raw_data, insert_time, write_retries,
+// last_write_error, parked and publish_time.
+func scanBatch(row pgx.Row) (egress.Batch, error) {
+	var (
+		batch       egress.Batch
+		lastErr     *string
+		publishTime *time.Time
+	)
+	err := row.Scan(&batch.BatchID, &batch.TransportBatchRaw, &batch.InsertTime,
+		&batch.WriteRetries, &lastErr, &batch.IsParked, &publishTime)
+	if err != nil {
+		return egress.Batch{}, err
+	}
+	if lastErr != nil {
+		batch.LastWriteError = *lastErr
+	}
+	if publishTime != nil {
+		batch.PublishTime = *publishTime
+	}
+	return batch, nil
+}
+
+func (e EgressStorage) Commit(ctx context.Context, batchID string) error {
+	if e.cfg.RetainPublished {
+		query := fmt.Sprintf("UPDATE %s SET publish_time = $2 WHERE batch_id = $1", e.cfg.TableName)
+		_, err := e.db.Exec(ctx, query, batchID, time.Now().UTC())
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE batch_id = $1", e.cfg.TableName)
+	_, err := e.db.Exec(ctx, query, batchID)
+	return err
+}
+
+func (e EgressStorage) RecordFailure(ctx context.Context, batchID string, failure error) (isParked bool, err error) {
+	var lastErr string
+	if failure != nil {
+		lastErr = failure.Error()
+	}
+	isUnrecoverable := errors.Is(failure, streams.ErrUnrecoverable)
+	query := fmt.Sprintf("UPDATE %s SET write_retries = write_retries + 1, last_write_error = $2, "+
+		"parked = (parked OR $3 OR ($4 > 0 AND write_retries + 1 >= $4)) WHERE batch_id = $1 RETURNING parked",
+		e.cfg.TableName)
+	err = e.db.QueryRow(ctx, query, batchID, lastErr, isUnrecoverable, e.cfg.MaxAttempts).Scan(&isParked)
+	return
+}
+
+func (e EgressStorage) PurgePublished(ctx context.Context, threshold time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE publish_time IS NOT NULL AND publish_time < $1", e.cfg.TableName)
+	res, err := e.db.Exec(ctx, query, threshold)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// ListPending retrieves pending batches sorted by their identifier. Batch identifiers are expected to be
+// time-sortable (e.g. KSUID), so batches are listed roughly by insert time.
+func (e EgressStorage) ListPending(ctx context.Context, threshold time.Time, cursor string,
+	limit int) ([]egress.Batch, string, error) {
+	query := fmt.Sprintf("SELECT batch_id,raw_data,insert_time,write_retries,last_write_error,parked,publish_time FROM %s "+
+		"WHERE parked = FALSE AND publish_time IS NULL AND insert_time < $1 AND batch_id > $2 ORDER BY batch_id LIMIT $3",
+		e.cfg.TableName)
+	rows, err := e.db.Query(ctx, query, threshold, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	batches := make([]egress.Batch, 0, limit)
+	for rows.Next() {
+		batch, errScan := scanBatch(rows)
+		if errScan != nil {
+			return nil, "", errScan
+		}
+		batches = append(batches, batch)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(batches) == limit {
+		nextCursor = batches[len(batches)-1].BatchID
+	}
+	return batches, nextCursor, nil
+}
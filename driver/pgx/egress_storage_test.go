@@ -0,0 +1,175 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockStorage(t *testing.T, opts ...egress.StorageOption) (EgressStorage, pgxmock.PgxPoolIface) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mock.Close)
+
+	cfg := newEgressStorageDefaults()
+	for _, o := range opts {
+		o.Apply(&cfg)
+	}
+	return EgressStorage{db: mock, cfg: cfg}, mock
+}
+
+func TestEgressStorage_GetBatch(t *testing.T) {
+	storage, mock := newMockStorage(t)
+	insertTime := time.Now().UTC()
+	lastErr := "generic error"
+	mock.ExpectQuery("SELECT (.+) FROM streams_egress WHERE batch_id = (.+)").
+		WithArgs("123").
+		WillReturnRows(pgxmock.NewRows([]string{"batch_id", "raw_data", "insert_time", "write_retries",
+			"last_write_error", "parked", "publish_time"}).
+			AddRow("123", []byte("foo"), insertTime, 2, &lastErr, false, (*time.Time)(nil)))
+
+	batch, err := storage.GetBatch(context.TODO(), "123")
+	require.NoError(t, err)
+	assert.Equal(t, egress.Batch{
+		BatchID:           "123",
+		TransportBatchRaw: []byte("foo"),
+		InsertTime:        insertTime,
+		WriteRetries:      2,
+		LastWriteError:    "generic error",
+	}, batch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEgressStorage_Commit(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []egress.StorageOption
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "delete",
+			opts:      nil,
+			wantQuery: "DELETE FROM streams_egress WHERE batch_id = (.+)",
+			wantArgs:  []any{"123"},
+		},
+		{
+			name:      "retention",
+			opts:      []egress.StorageOption{egress.WithPublishedRetention(true)},
+			wantQuery: "UPDATE streams_egress SET publish_time = (.+) WHERE batch_id = (.+)",
+			wantArgs:  []any{"123", pgxmock.AnyArg()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, mock := newMockStorage(t, tt.opts...)
+			mock.ExpectExec(tt.wantQuery).WithArgs(tt.wantArgs...).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+			assert.NoError(t, storage.Commit(context.TODO(), "123"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEgressStorage_RecordFailure(t *testing.T) {
+	tests := []struct {
+		name            string
+		inFailure       error
+		inParked        bool
+		wantUnrecovered bool
+	}{
+		{
+			name:            "recoverable",
+			inFailure:       errors.New("generic error"),
+			inParked:        false,
+			wantUnrecovered: false,
+		},
+		{
+			name:            "unrecoverable",
+			inFailure:       streams.ErrUnrecoverableWrap{ParentErr: errors.New("generic error")},
+			inParked:        true,
+			wantUnrecovered: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, mock := newMockStorage(t, egress.WithMaxAttempts(5))
+			mock.ExpectQuery("UPDATE streams_egress SET write_retries = (.+) RETURNING parked").
+				WithArgs("123", tt.inFailure.Error(), tt.wantUnrecovered, 5).
+				WillReturnRows(pgxmock.NewRows([]string{"parked"}).AddRow(tt.inParked))
+
+			isParked, err := storage.RecordFailure(context.TODO(), "123", tt.inFailure)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.inParked, isParked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEgressStorage_PurgePublished(t *testing.T) {
+	storage, mock := newMockStorage(t)
+	threshold := time.Now()
+	mock.ExpectExec("DELETE FROM streams_egress WHERE publish_time IS NOT NULL AND publish_time < (.+)").
+		WithArgs(threshold).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+
+	total, err := storage.PurgePublished(context.TODO(), threshold)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEgressStorage_ListPending(t *testing.T) {
+	insertTime := time.Now().UTC()
+	tests := []struct {
+		name       string
+		inCursor   string
+		inLimit    int
+		inRows     []string
+		wantCursor string
+	}{
+		{
+			name:       "full page",
+			inCursor:   "",
+			inLimit:    2,
+			inRows:     []string{"1", "2"},
+			wantCursor: "2",
+		},
+		{
+			name:       "last page",
+			inCursor:   "2",
+			inLimit:    2,
+			inRows:     []string{"3"},
+			wantCursor: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, mock := newMockStorage(t)
+			rows := pgxmock.NewRows([]string{"batch_id", "raw_data", "insert_time", "write_retries",
+				"last_write_error", "parked", "publish_time"})
+			for _, batchID := range tt.inRows {
+				rows.AddRow(batchID, []byte("foo"), insertTime, 0, (*string)(nil), false, (*time.Time)(nil))
+			}
+			mock.ExpectQuery("SELECT (.+) FROM streams_egress WHERE parked = FALSE AND publish_time IS NULL AND "+
+				"insert_time < (.+) AND batch_id > (.+) ORDER BY batch_id LIMIT (.+)").
+				WithArgs(insertTime, tt.inCursor, tt.inLimit).
+				WillReturnRows(rows)
+
+			batches, cursor, err := storage.ListPending(context.TODO(), insertTime, tt.inCursor, tt.inLimit)
+			require.NoError(t, err)
+			assert.Len(t, batches, len(tt.inRows))
+			assert.Equal(t, tt.wantCursor, cursor)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
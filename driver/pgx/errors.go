@@ -0,0 +1,7 @@
+package pgx
+
+import "errors"
+
+var (
+	ErrUnableToWriteRows = errors.New("streams.pgx: unable to write rows")
+)
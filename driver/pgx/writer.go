@@ -0,0 +1,182 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultNotifyChannel default Postgres channel used by Writer to notify egress proxy agents.
+const DefaultNotifyChannel = "streams_egress"
+
+// A Writer is a Postgres writer built on top of the pgx driver.
+// This specific kind of streams.Writer is used by systems implementing the transactional outbox messaging pattern.
+// More in depth, a Writer instance will attempt to execute a transactional write into an <<egress table>> where all messages
+// generated by the system will be stored, so they may be later publish by an <<egress proxy agent>> (aka. log trailing).
+//
+// Writer instances MUST be used along transaction context functions
+// (persistence.SetTransactionContext, persistence.GetTransactionContext) holding a pgx.Tx instance.
+// If no context is found, then Writer.Write will fail.
+//
+// Moreover, Writer emits a notification (pg_notify) for each written batch, so a listening egress proxy agent
+// forwards traffic as soon as the transaction gets committed.
+//
+// Transactional outbox pattern reference: https://microservices.io/patterns/data/transactional-outbox.html
+type Writer struct {
+	cfg WriterConfig
+}
+
+var _ streams.Writer = Writer{}
+
+// A WriterConfig is the Writer configuration.
+type WriterConfig struct {
+	Codec             codec.Codec // used to encode message batches, so it can be stored on the database (default codec.ProtocolBuffers).
+	WriterEgressTable string      // table to write message batches to be later published.
+	NotifyChannel     string      // Postgres channel to emit notifications to. Leave empty to disable notifications.
+	// Maximum count of messages a single egress table row may hold. Large message batches are split in multiple rows.
+	// Leave 0 to write every message batch in a single row.
+	MaxBatchSize      int
+	IdentifierFactory streams.IdentifierFactory // used to generate batch identifiers of split message batches.
+}
+
+func newWriterDefaults() WriterConfig {
+	return WriterConfig{
+		Codec:             codec.ProtocolBuffers{},
+		WriterEgressTable: egress.DefaultEgressTableName,
+		NotifyChannel:     DefaultNotifyChannel,
+		MaxBatchSize:      0,
+		IdentifierFactory: streams.NewKSUID,
+	}
+}
+
+// NewWriter allocates a new Writer instance with default configuration but open to apply any WriterOption(s).
+func NewWriter(opts ...WriterOption) Writer {
+	baseOpts := newWriterDefaults()
+	for _, o := range opts {
+		o.apply(&baseOpts)
+	}
+	return Writer{
+		cfg: baseOpts,
+	}
+}
+
+// NewWriterWithConfig allocates a new Writer instance with passed configuration.
+func NewWriterWithConfig(cfg WriterConfig) Writer {
+	if cfg.IdentifierFactory == nil {
+		cfg.IdentifierFactory = streams.NewKSUID
+	}
+	return Writer{
+		cfg: cfg,
+	}
+}
+
+// Write append a new batch of messages into the egress table.
+//
+// A transaction context (persistence.SetTransactionContext) holding a pgx.Tx MUST be set before calling this routine.
+// If no context is found, then Writer.Write will fail.
+//
+// Batch identifier will be taken from TransactionContext.TransactionID. If the batch was split (see
+// WriterConfig.MaxBatchSize), then the rest of the batch identifiers are generated with WriterConfig.IdentifierFactory.
+func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) error {
+	if len(msgBatch) == 0 {
+		return streams.ErrEmptyMessage
+	}
+
+	txCtx, err := persistence.GetTransactionContext[pgx.Tx](ctx)
+	if err != nil {
+		return err
+	}
+
+	chunks := w.split(msgBatch)
+	batchIDs := make([]string, 0, len(chunks))
+	rows := make([][]any, 0, len(chunks))
+	insertTime := time.Now().UTC()
+	for i, chunk := range chunks {
+		batchID := txCtx.TransactionID
+		if i > 0 {
+			if batchID, err = w.cfg.IdentifierFactory(); err != nil {
+				return err
+			}
+		}
+		encodedData, errEncode := w.encode(chunk)
+		if errEncode != nil {
+			return errEncode
+		}
+		batchIDs = append(batchIDs, batchID)
+		rows = append(rows, []any{batchID, len(chunk), encodedData, insertTime})
+	}
+
+	if len(rows) == 1 {
+		err = w.insert(ctx, txCtx.Tx, rows[0])
+	} else {
+		err = w.copy(ctx, txCtx.Tx, rows)
+	}
+	if err != nil {
+		return err
+	}
+	return w.notify(ctx, txCtx.Tx, batchIDs)
+}
+
+func (w Writer) split(msgBatch []streams.Message) [][]streams.Message {
+	if w.cfg.MaxBatchSize <= 0 || len(msgBatch) <= w.cfg.MaxBatchSize {
+		return [][]streams.Message{msgBatch}
+	}
+
+	chunks := make([][]streams.Message, 0, (len(msgBatch)+w.cfg.MaxBatchSize-1)/w.cfg.MaxBatchSize)
+	for offset := 0; offset < len(msgBatch); offset += w.cfg.MaxBatchSize {
+		end := offset + w.cfg.MaxBatchSize
+		if end > len(msgBatch) {
+			end = len(msgBatch)
+		}
+		chunks = append(chunks, msgBatch[offset:end])
+	}
+	return chunks
+}
+
+func (w Writer) encode(msgBatch []streams.Message) ([]byte, error) {
+	var msgBatchAny any = msgBatch
+	if w.cfg.Codec.ApplicationType() == codec.ProtocolBuffersApplicationType {
+		msgBatchAny = persistence.NewTransportMessageBatch(msgBatch)
+	}
+	return w.cfg.Codec.Encode(msgBatchAny)
+}
+
+func (w Writer) insert(ctx context.Context, tx pgx.Tx, row []any) error {
+	query := fmt.Sprintf("INSERT INTO %s(batch_id,message_count,raw_data,insert_time) VALUES ($1,$2,$3,$4)",
+		w.cfg.WriterEgressTable)
+	res, err := tx.Exec(ctx, query, row...)
+	if err != nil {
+		return err
+	} else if res.RowsAffected() <= 0 {
+		return ErrUnableToWriteRows
+	}
+	return nil
+}
+
+func (w Writer) copy(ctx context.Context, tx pgx.Tx, rows [][]any) error {
+	total, err := tx.CopyFrom(ctx, pgx.Identifier(strings.Split(w.cfg.WriterEgressTable, ".")),
+		[]string{"batch_id", "message_count", "raw_data", "insert_time"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		return err
+	} else if total < int64(len(rows)) {
+		return ErrUnableToWriteRows
+	}
+	return nil
+}
+
+// notify emits a notification for each batch. Postgres delivers notifications only if the transaction gets committed.
+func (w Writer) notify(ctx context.Context, tx pgx.Tx, batchIDs []string) error {
+	if w.cfg.NotifyChannel == "" {
+		return nil
+	}
+	_, err := tx.Exec(ctx, "SELECT pg_notify($1, batch_id) FROM unnest($2::text[]) AS batch_id",
+		w.cfg.NotifyChannel, batchIDs)
+	return err
+}
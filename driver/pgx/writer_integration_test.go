@@ -0,0 +1,74 @@
+//go:build integration
+
+package pgx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamspgx "github.com/alexandria-oss/streams/driver/pgx"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type writerSuite struct {
+	suite.Suite
+
+	pool    *pgxpool.Pool
+	storage streamspgx.EgressStorage
+}
+
+func TestWriter_Integration(t *testing.T) {
+	suite.Run(t, &writerSuite{})
+}
+
+func (s *writerSuite) SetupSuite() {
+	pool, err := pgxpool.New(context.Background(),
+		"user=postgres password=root sslmode=disable port=6432 host=localhost database=sample_database")
+	s.Require().NoError(err)
+	s.pool = pool
+	s.storage = streamspgx.NewEgressStorage(pool)
+}
+
+func (s *writerSuite) TearDownSuite() {
+	_, _ = s.pool.Exec(context.Background(), "DELETE FROM streams_egress")
+	s.pool.Close()
+}
+
+func (s *writerSuite) TestWrite() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+	defer cancel()
+
+	conn, err := s.pool.Acquire(ctx)
+	s.Require().NoError(err)
+	defer conn.Release()
+	_, err = conn.Exec(ctx, "LISTEN "+streamspgx.DefaultNotifyChannel)
+	s.Require().NoError(err)
+
+	tx, err := s.pool.Begin(ctx)
+	s.Require().NoError(err)
+	batchID, _ := streams.NewKSUID()
+	scopedCtx := persistence.SetTransactionContext(ctx, persistence.TransactionContext[pgx.Tx]{
+		TransactionID: batchID,
+		Tx:            tx,
+	})
+	err = streamspgx.NewWriter(streamspgx.WithMaxBatchSize(1, streams.NewKSUID)).Write(scopedCtx, []streams.Message{
+		{ID: "1", StreamName: "foo", Data: []byte("bar")},
+		{ID: "2", StreamName: "foo", Data: []byte("baz")},
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(tx.Commit(ctx))
+
+	notification, err := conn.Conn().WaitForNotification(ctx)
+	s.Require().NoError(err)
+	s.Assert().Equal(batchID, notification.Payload)
+
+	batch, err := s.storage.GetBatch(ctx, batchID)
+	s.Require().NoError(err)
+	s.Assert().Equal(batchID, batch.BatchID)
+	s.Assert().NoError(s.storage.Commit(ctx, batchID))
+}
@@ -0,0 +1,80 @@
+package pgx
+
+import (
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+)
+
+// A WriterOption is used to configure a Writer instance in an idiomatic & fine-grained way.
+type WriterOption interface {
+	apply(*WriterConfig)
+}
+
+type egressTableOption struct {
+	table string
+}
+
+var _ WriterOption = egressTableOption{}
+
+func (e egressTableOption) apply(config *WriterConfig) {
+	config.WriterEgressTable = e.table
+}
+
+// WithEgressTable sets the name of the table to be used as <<message egress table>>. A <<message egress table>> is
+// a system database table used by `streams` mechanisms to write batch of messages to be published into a message stream.
+func WithEgressTable(table string) WriterOption {
+	return egressTableOption{table: table}
+}
+
+type codecOption struct {
+	codec codec.Codec
+}
+
+var _ WriterOption = codecOption{}
+
+func (o codecOption) apply(opts *WriterConfig) {
+	opts.Codec = o.codec
+}
+
+// WithCodec sets the codec.Codec to be used by Writer to encode message batches, so data may be stored into
+// a database efficiently.
+func WithCodec(c codec.Codec) WriterOption {
+	return codecOption{codec: c}
+}
+
+type notifyChannelOption struct {
+	channel string
+}
+
+var _ WriterOption = notifyChannelOption{}
+
+func (o notifyChannelOption) apply(opts *WriterConfig) {
+	opts.NotifyChannel = o.channel
+}
+
+// WithNotifyChannel sets the Postgres channel Writer will emit notifications (pg_notify) to. Use an empty string
+// to disable notifications, required if the egress table notification trigger is installed.
+func WithNotifyChannel(channel string) WriterOption {
+	return notifyChannelOption{channel: channel}
+}
+
+type maxBatchSizeOption struct {
+	size      int
+	idFactory streams.IdentifierFactory
+}
+
+var _ WriterOption = maxBatchSizeOption{}
+
+func (o maxBatchSizeOption) apply(opts *WriterConfig) {
+	opts.MaxBatchSize = o.size
+	if o.idFactory != nil {
+		opts.IdentifierFactory = o.idFactory
+	}
+}
+
+// WithMaxBatchSize sets the maximum count of messages a single egress table row may hold. Large message batches
+// are split in multiple rows, written using the Postgres COPY protocol. Additional batch identifiers are generated
+// using factory (default streams.NewKSUID).
+func WithMaxBatchSize(size int, factory streams.IdentifierFactory) WriterOption {
+	return maxBatchSizeOption{size: size, idFactory: factory}
+}
@@ -0,0 +1,169 @@
+package pgx
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/codec"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriter(t *testing.T) {
+	writerWithCfg := NewWriterWithConfig(WriterConfig{
+		WriterEgressTable: "foo_table",
+	})
+	assert.Equal(t, "foo_table", writerWithCfg.cfg.WriterEgressTable)
+	assert.NotNil(t, writerWithCfg.cfg.IdentifierFactory)
+
+	tests := []struct {
+		name         string
+		opts         []WriterOption
+		validateFunc func(t *testing.T, w Writer)
+	}{
+		{
+			name: "default values",
+			opts: nil,
+			validateFunc: func(t *testing.T, w Writer) {
+				assert.IsType(t, codec.ProtocolBuffers{}, w.cfg.Codec)
+				assert.Equal(t, "streams_egress", w.cfg.WriterEgressTable)
+				assert.Equal(t, DefaultNotifyChannel, w.cfg.NotifyChannel)
+				assert.Equal(t, 0, w.cfg.MaxBatchSize)
+			},
+		},
+		{
+			name: "with change",
+			opts: []WriterOption{
+				WithEgressTable("foo_table"),
+				WithCodec(codec.JSON{}),
+				WithNotifyChannel(""),
+				WithMaxBatchSize(10, nil),
+			},
+			validateFunc: func(t *testing.T, w Writer) {
+				assert.IsType(t, codec.JSON{}, w.cfg.Codec)
+				assert.Equal(t, "foo_table", w.cfg.WriterEgressTable)
+				assert.Equal(t, "", w.cfg.NotifyChannel)
+				assert.Equal(t, 10, w.cfg.MaxBatchSize)
+				assert.NotNil(t, w.cfg.IdentifierFactory)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter(tt.opts...)
+			tt.validateFunc(t, w)
+		})
+	}
+}
+
+func newMessages(n int) []streams.Message {
+	msgs := make([]streams.Message, 0, n)
+	for i := 0; i < n; i++ {
+		msgs = append(msgs, streams.Message{ID: strconv.Itoa(i), StreamName: "foo", Data: []byte("bar")})
+	}
+	return msgs
+}
+
+func TestWriter_Write(t *testing.T) {
+	idCount := 0
+	idFactory := func() (string, error) {
+		idCount++
+		return "gen-" + strconv.Itoa(idCount), nil
+	}
+
+	tests := []struct {
+		name      string
+		opts      []WriterOption
+		inMsgs    []streams.Message
+		noTx      bool
+		setupMock func(mock pgxmock.PgxPoolIface)
+		wantErr   error
+	}{
+		{
+			name:    "no messages",
+			inMsgs:  nil,
+			wantErr: streams.ErrEmptyMessage,
+		},
+		{
+			name:    "no transaction context",
+			inMsgs:  newMessages(1),
+			noTx:    true,
+			wantErr: persistence.ErrTransactionContextNotFound,
+		},
+		{
+			name:   "single batch",
+			inMsgs: newMessages(2),
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO streams_egress(.+) VALUES (.+)").
+					WithArgs("123", 2, pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec("SELECT pg_notify(.+)").
+					WithArgs(DefaultNotifyChannel, []string{"123"}).
+					WillReturnResult(pgxmock.NewResult("SELECT", 1))
+			},
+		},
+		{
+			name:   "no rows written",
+			inMsgs: newMessages(2),
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO streams_egress(.+) VALUES (.+)").
+					WithArgs("123", 2, pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 0))
+			},
+			wantErr: ErrUnableToWriteRows,
+		},
+		{
+			name:   "split batch",
+			opts:   []WriterOption{WithMaxBatchSize(2, idFactory), WithNotifyChannel("foo_channel")},
+			inMsgs: newMessages(5),
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectCopyFrom(pgx.Identifier{"streams_egress"},
+					[]string{"batch_id", "message_count", "raw_data", "insert_time"}).
+					WillReturnResult(3)
+				mock.ExpectExec("SELECT pg_notify(.+)").
+					WithArgs("foo_channel", []string{"123", "gen-1", "gen-2"}).
+					WillReturnResult(pgxmock.NewResult("SELECT", 3))
+			},
+		},
+		{
+			name:   "notifications disabled",
+			opts:   []WriterOption{WithNotifyChannel("")},
+			inMsgs: newMessages(1),
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO streams_egress(.+) VALUES (.+)").
+					WithArgs("123", 1, pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			ctx := context.TODO()
+			if !tt.noTx && tt.setupMock != nil {
+				mock.ExpectBegin()
+				tt.setupMock(mock)
+				tx, errTx := mock.Begin(ctx)
+				require.NoError(t, errTx)
+				ctx = persistence.SetTransactionContext(ctx, persistence.TransactionContext[pgx.Tx]{
+					TransactionID: "123",
+					Tx:            tx,
+				})
+			}
+
+			err = NewWriter(tt.opts...).Write(ctx, tt.inMsgs)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package rabbitmq
+
+const (
+	HeaderStreamKey = "streams-stream-key" // Key of the stream from a message.
+)
+
+const (
+	// HeaderExchange is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the exchange the message was published to (empty for the default exchange).
+	HeaderExchange = "streams-rabbitmq-exchange"
+	// HeaderQueue is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the queue the message was consumed from.
+	HeaderQueue = "streams-rabbitmq-queue"
+	// HeaderConsumerTag is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the consumer tag of the reader instance.
+	HeaderConsumerTag = "streams-rabbitmq-consumer-tag"
+	// HeaderDeliveryTag is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the delivery tag of the message within the reader channel.
+	HeaderDeliveryTag = "streams-rabbitmq-delivery-tag"
+	// HeaderRedelivered is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents whether the message was delivered before (i.e. requeued) or not.
+	HeaderRedelivered = "streams-rabbitmq-redelivered"
+)
@@ -0,0 +1,57 @@
+package rabbitmq
+
+import (
+	"fmt"
+
+	"github.com/alexandria-oss/streams"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const fixedHeaderReaderInjectCount = 5
+
+func marshalMessage(msg streams.Message, deliveryMode uint8) amqp.Publishing {
+	headers := make(amqp.Table, len(msg.Headers)+1)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	if msg.StreamKey != "" {
+		headers[HeaderStreamKey] = msg.StreamKey
+	}
+	return amqp.Publishing{
+		Headers:      headers,
+		ContentType:  msg.ContentType,
+		DeliveryMode: deliveryMode,
+		MessageId:    msg.ID,
+		Timestamp:    msg.Time,
+		Body:         msg.Data,
+	}
+}
+
+func unmarshalMessage(delivery amqp.Delivery) streams.Message {
+	msg := streams.Message{
+		ID:          delivery.MessageId,
+		StreamName:  delivery.RoutingKey,
+		ContentType: delivery.ContentType,
+		Headers:     make(map[string]string, len(delivery.Headers)+fixedHeaderReaderInjectCount),
+		Data:        delivery.Body,
+		Time:        delivery.Timestamp,
+	}
+	for k, v := range delivery.Headers {
+		var val string
+		switch vt := v.(type) {
+		case string:
+			val = vt
+		case []byte:
+			val = string(vt)
+		default:
+			// headers set by other publishers or the broker (e.g. x-death)
+			val = fmt.Sprint(vt)
+		}
+		if k == HeaderStreamKey {
+			msg.StreamKey = val
+			continue
+		}
+		msg.Headers[k] = val
+	}
+	return msg
+}
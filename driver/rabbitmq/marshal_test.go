@@ -0,0 +1,68 @@
+package rabbitmq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalMessage(t *testing.T) {
+	now := time.Now()
+	pub := marshalMessage(streams.Message{
+		ID:          "123",
+		StreamName:  "foo",
+		StreamKey:   "foo-key",
+		ContentType: "application/json",
+		Headers:     map[string]string{"bar": "baz"},
+		Data:        []byte(`{"foo":"bar"}`),
+		Time:        now,
+	}, amqp.Persistent)
+	assert.Equal(t, amqp.Publishing{
+		Headers: amqp.Table{
+			"bar":           "baz",
+			HeaderStreamKey: "foo-key",
+		},
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    "123",
+		Timestamp:    now,
+		Body:         []byte(`{"foo":"bar"}`),
+	}, pub)
+
+	pub = marshalMessage(streams.Message{ID: "456"}, amqp.Transient)
+	assert.Empty(t, pub.Headers)
+	assert.Equal(t, amqp.Transient, pub.DeliveryMode)
+}
+
+func TestUnmarshalMessage(t *testing.T) {
+	now := time.Now()
+	msg := unmarshalMessage(amqp.Delivery{
+		Headers: amqp.Table{
+			"bar":           "baz",
+			"raw":           []byte("qux"),
+			"x-delivery":    int64(2),
+			HeaderStreamKey: "foo-key",
+		},
+		ContentType: "application/json",
+		MessageId:   "123",
+		Timestamp:   now,
+		RoutingKey:  "foo",
+		Body:        []byte(`{"foo":"bar"}`),
+	})
+	assert.Equal(t, streams.Message{
+		ID:          "123",
+		StreamName:  "foo",
+		StreamKey:   "foo-key",
+		ContentType: "application/json",
+		Headers: map[string]string{
+			"bar":        "baz",
+			"raw":        "qux",
+			"x-delivery": "2",
+		},
+		Data: []byte(`{"foo":"bar"}`),
+		Time: now,
+	}, msg)
+}
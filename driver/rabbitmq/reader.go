@@ -0,0 +1,152 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/internal/genericutil"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ReaderTaskQueueKey is the argument key to set up the queue a reader consumes from. If not set, the queue named
+// after the task stream is used.
+const ReaderTaskQueueKey string = "rabbitmq-queue"
+
+// ErrConsumerCancelled the reader consumer was cancelled (e.g. its queue was deleted) or its channel was closed.
+var ErrConsumerCancelled = errors.New("streams.rabbitmq: consumer was cancelled")
+
+// FailurePolicy is the action a Reader takes on a message once its handler failed.
+type FailurePolicy uint8
+
+const (
+	// RequeueOnFailure requeues (basic.reject with requeue) failed messages, so they are delivered again. Use a
+	// quorum queue delivery limit (x-delivery-limit) to stop requeueing poisoned messages.
+	RequeueOnFailure FailurePolicy = iota
+	// RejectOnFailure rejects (basic.reject without requeue) failed messages, so they are either dropped or routed
+	// to the queue dead-letter exchange (see TopologyConfig.DeadLetter).
+	RejectOnFailure
+)
+
+// ReaderConfig is the configuration schema for RabbitMQ streams.Reader implementation.
+type ReaderConfig struct {
+	Logger         *log.Logger   // Logging instance preferably with log level at <<info>>.
+	ErrorLogger    *log.Logger   // Logging instance preferably with log level at <<error>>.
+	ConsumerTag    string        // Consumer tag of the reader. Generated by the client if empty.
+	PrefetchCount  int           // Maximum count of unacknowledged messages delivered to the reader (basic.qos).
+	HandlerTimeout time.Duration // Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	// Action taken on a message once its handler failed. Messages failed with streams.ErrUnrecoverable errors are
+	// always rejected without requeue.
+	FailurePolicy FailurePolicy
+}
+
+// A Reader type is the concrete implementation of streams.Reader using RabbitMQ (AMQP 0-9-1) queues.
+//
+// Messages are acknowledged manually (basic.ack) once their handler succeeded, otherwise they are either requeued or
+// rejected depending on ReaderConfig.FailurePolicy. Each Reader.Read call opens its own channel, so unacknowledged
+// messages are requeued by the broker once the reader stops.
+type Reader struct {
+	cfg  ReaderConfig
+	conn *amqp.Connection
+}
+
+var _ streams.Reader = Reader{}
+
+// NewReader allocates a Reader instance.
+func NewReader(cfg ReaderConfig, conn *amqp.Connection) Reader {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.rabbitmq: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.PrefetchCount <= 0 {
+		cfg.PrefetchCount = 10
+	}
+	if cfg.HandlerTimeout == 0 {
+		cfg.HandlerTimeout = time.Second * 30
+	}
+	return Reader{
+		cfg:  cfg,
+		conn: conn,
+	}
+}
+
+func (r Reader) Read(ctx context.Context, task streams.ReadTask) error {
+	queue := genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskQueueKey])
+	if queue == "" {
+		queue = task.Stream
+	}
+
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errClosure := ch.Close(); errClosure != nil && !errors.Is(errClosure, amqp.ErrClosed) {
+			r.cfg.ErrorLogger.Printf("error occurred closing channel, %s", errClosure.Error())
+		}
+	}()
+	if err = ch.Qos(r.cfg.PrefetchCount, 0, false); err != nil {
+		return err
+	}
+	closeNotify := ch.NotifyClose(make(chan *amqp.Error, 1))
+	deliveries, err := ch.Consume(queue, r.cfg.ConsumerTag, false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.cfg.Logger.Printf("stopping queue consuming process")
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				// abnormal channel closures are notified before closing deliveries
+				select {
+				case errClose := <-closeNotify:
+					if errClose != nil {
+						return errClose
+					}
+				default:
+				}
+				return ErrConsumerCancelled
+			}
+			r.handleDelivery(ctx, task, queue, delivery)
+		}
+	}
+}
+
+func (r Reader) handleDelivery(ctx context.Context, task streams.ReadTask, queue string, delivery amqp.Delivery) {
+	msg := unmarshalMessage(delivery)
+	msg.Headers[HeaderExchange] = delivery.Exchange
+	msg.Headers[HeaderQueue] = queue
+	msg.Headers[HeaderConsumerTag] = delivery.ConsumerTag
+	msg.Headers[HeaderDeliveryTag] = strconv.FormatUint(delivery.DeliveryTag, 10)
+	msg.Headers[HeaderRedelivered] = strconv.FormatBool(delivery.Redelivered)
+
+	scopedCtx, cancel := context.WithTimeout(ctx, r.cfg.HandlerTimeout)
+	errHandle := task.Handler(scopedCtx, msg)
+	cancel()
+
+	var errAck error
+	switch {
+	case errHandle == nil:
+		errAck = delivery.Ack(false)
+	case errors.Is(errHandle, streams.ErrUnrecoverable) || r.cfg.FailurePolicy == RejectOnFailure:
+		errAck = delivery.Reject(false)
+	default:
+		errAck = delivery.Reject(true)
+	}
+	if errAck != nil {
+		r.cfg.ErrorLogger.Printf("failed to acknowledge message <%s>, %s", msg.ID, errAck.Error())
+	}
+}
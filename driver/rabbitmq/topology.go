@@ -0,0 +1,90 @@
+package rabbitmq
+
+import (
+	"errors"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetterQueueSuffix is the suffix of dead-letter queue names (and their routing keys), matching the stream names
+// written by streams.WithDeadLetterQueue.
+const DeadLetterQueueSuffix = ".dlq"
+
+const (
+	queueDeadLetterExchangeArg   = "x-dead-letter-exchange"
+	queueDeadLetterRoutingKeyArg = "x-dead-letter-routing-key"
+)
+
+// ErrMissingExchange the topology has no exchange.
+var ErrMissingExchange = errors.New("streams.rabbitmq: missing exchange")
+
+// TopologyConfig is the configuration schema of the exchanges, queues and bindings declared by DeclareTopology.
+type TopologyConfig struct {
+	Exchange     string // Exchange messages are published to (WriterConfig.Exchange).
+	ExchangeKind string // Type of Exchange (e.g. direct, topic). Default is topic.
+	// Streams to declare a queue for. Each queue is named after its stream and bound to Exchange using the stream
+	// name as binding key.
+	Streams []string
+	// Declare a dead-letter queue for each stream (stream name with DeadLetterQueueSuffix), so messages rejected
+	// without requeue (see RejectOnFailure) or expired are routed to it through DeadLetterExchange.
+	DeadLetter bool
+	// Exchange rejected messages are routed to, using the dead-letter queue name as routing key. Default is Exchange,
+	// so streams.WithDeadLetterQueue writers publishing to Exchange also route messages to dead-letter queues.
+	DeadLetterExchange string
+	// Additional arguments of stream queues (e.g. x-queue-type, x-message-ttl, x-delivery-limit).
+	QueueArgs amqp.Table
+}
+
+// DeclareTopology declares (idempotently) the durable exchanges, queues and bindings of cfg using ch.
+func DeclareTopology(ch *amqp.Channel, cfg TopologyConfig) error {
+	if cfg.Exchange == "" {
+		return ErrMissingExchange
+	}
+	if cfg.ExchangeKind == "" {
+		cfg.ExchangeKind = amqp.ExchangeTopic
+	}
+	if cfg.DeadLetterExchange == "" {
+		cfg.DeadLetterExchange = cfg.Exchange
+	}
+
+	if err := ch.ExchangeDeclare(cfg.Exchange, cfg.ExchangeKind, true, false, false, false, nil); err != nil {
+		return err
+	}
+	if cfg.DeadLetter && cfg.DeadLetterExchange != cfg.Exchange {
+		err := ch.ExchangeDeclare(cfg.DeadLetterExchange, cfg.ExchangeKind, true, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, stream := range cfg.Streams {
+		if cfg.DeadLetter {
+			deadLetterQueue := stream + DeadLetterQueueSuffix
+			if _, err := ch.QueueDeclare(deadLetterQueue, true, false, false, false, nil); err != nil {
+				return err
+			}
+			if err := ch.QueueBind(deadLetterQueue, deadLetterQueue, cfg.DeadLetterExchange, false, nil); err != nil {
+				return err
+			}
+		}
+		if _, err := ch.QueueDeclare(stream, true, false, false, false, newQueueArgs(cfg, stream)); err != nil {
+			return err
+		}
+		if err := ch.QueueBind(stream, stream, cfg.Exchange, false, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newQueueArgs(cfg TopologyConfig, stream string) amqp.Table {
+	args := make(amqp.Table, len(cfg.QueueArgs)+2)
+	for k, v := range cfg.QueueArgs {
+		args[k] = v
+	}
+	if cfg.DeadLetter {
+		args[queueDeadLetterExchangeArg] = cfg.DeadLetterExchange
+		args[queueDeadLetterRoutingKeyArg] = stream + DeadLetterQueueSuffix
+	}
+	return args
+}
@@ -0,0 +1,46 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclareTopology_MissingExchange(t *testing.T) {
+	err := DeclareTopology(nil, TopologyConfig{Streams: []string{"foo"}})
+	assert.ErrorIs(t, err, ErrMissingExchange)
+}
+
+func TestNewQueueArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		inCfg    TopologyConfig
+		wantArgs amqp.Table
+	}{
+		{
+			name:     "no dead-letter",
+			inCfg:    TopologyConfig{Exchange: "events"},
+			wantArgs: amqp.Table{},
+		},
+		{
+			name: "dead-letter",
+			inCfg: TopologyConfig{
+				DeadLetter:         true,
+				DeadLetterExchange: "events",
+				QueueArgs:          amqp.Table{"x-queue-type": "quorum"},
+			},
+			wantArgs: amqp.Table{
+				"x-queue-type":               "quorum",
+				queueDeadLetterExchangeArg:   "events",
+				queueDeadLetterRoutingKeyArg: "foo.dlq",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantArgs, newQueueArgs(tt.inCfg, "foo"))
+		})
+	}
+}
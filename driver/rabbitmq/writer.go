@@ -0,0 +1,78 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/hashicorp/go-multierror"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrPublishNotConfirmed the broker negatively acknowledged (basic.nack) a published message.
+var ErrPublishNotConfirmed = errors.New("streams.rabbitmq: message publishing was not confirmed")
+
+// WriterConfig is the configuration schema for RabbitMQ streams.Writer implementation.
+type WriterConfig struct {
+	// Exchange messages are published to using Message.StreamName as routing key. If empty, the default exchange
+	// is used, routing messages to the queue named after Message.StreamName.
+	Exchange string
+	// Unroutable messages are returned by the broker (see amqp.Channel.NotifyReturn) instead of being dropped.
+	Mandatory bool
+	// Messages are not persisted by the broker (i.e. transient delivery mode).
+	Transient bool
+}
+
+// A Writer type is the concrete implementation of streams.Writer using RabbitMQ (AMQP 0-9-1).
+//
+// Messages are published using Message.StreamName as routing key. The channel is set in confirm mode, so
+// Writer.Write returns once every message was confirmed by the broker (publisher confirms).
+type Writer struct {
+	cfg WriterConfig
+	ch  *amqp.Channel
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates a Writer instance, putting ch in confirm mode. Do not share ch with non-confirming publishers.
+func NewWriter(cfg WriterConfig, ch *amqp.Channel) (Writer, error) {
+	if err := ch.Confirm(false); err != nil {
+		return Writer{}, err
+	}
+	return Writer{
+		cfg: cfg,
+		ch:  ch,
+	}, nil
+}
+
+func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) error {
+	deliveryMode := amqp.Persistent
+	if w.cfg.Transient {
+		deliveryMode = amqp.Transient
+	}
+
+	confirms := make([]*amqp.DeferredConfirmation, 0, len(msgBatch))
+	confirmIDs := make([]string, 0, len(msgBatch))
+	errs := &multierror.Error{}
+	for _, msg := range msgBatch {
+		confirm, err := w.ch.PublishWithDeferredConfirmWithContext(ctx, w.cfg.Exchange, msg.StreamName,
+			w.cfg.Mandatory, false, marshalMessage(msg, deliveryMode))
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		confirms = append(confirms, confirm)
+		confirmIDs = append(confirmIDs, msg.ID)
+	}
+
+	for i, confirm := range confirms {
+		ok, err := confirm.WaitContext(ctx)
+		if err != nil {
+			return multierror.Append(errs, err)
+		} else if !ok {
+			errs = multierror.Append(errs, fmt.Errorf("%w, message <%s>", ErrPublishNotConfirmed, confirmIDs[i]))
+		}
+	}
+	return errs.ErrorOrNil()
+}
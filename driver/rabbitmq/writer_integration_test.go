@@ -0,0 +1,39 @@
+//go:build integration
+
+package rabbitmq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsrabbitmq "github.com/alexandria-oss/streams/driver/rabbitmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Write(t *testing.T) {
+	const streamName = "org.alexandria.integration_test.writer"
+	_, ch := newTestChannel(t)
+	declareTestTopology(t, ch, streamName)
+
+	w, err := streamsrabbitmq.NewWriter(streamsrabbitmq.WriterConfig{Exchange: testExchange}, ch)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	err = w.Write(ctx, []streams.Message{
+		{ID: "1", StreamName: streamName, Data: []byte("1")},
+		{ID: "2", StreamName: streamName, Data: []byte("2")},
+		// messages written by streams.WithDeadLetterQueue are routed to dead-letter queues
+		{ID: "3", StreamName: streamName + streamsrabbitmq.DeadLetterQueueSuffix, Data: []byte("3")},
+	})
+	require.NoError(t, err)
+
+	queue, err := ch.QueueInspect(streamName)
+	require.NoError(t, err)
+	assert.Equal(t, 2, queue.Messages)
+	queue, err = ch.QueueInspect(streamName + streamsrabbitmq.DeadLetterQueueSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, 1, queue.Messages)
+}
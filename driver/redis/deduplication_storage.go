@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	deduplicationStorageKeyPrefix    = "streams:dedupe"
+	deduplicationStorageKeyDelimiter = ":"
+)
+
+// DeduplicationStorageConfig is the configuration schema for Redis streams.DeduplicationStorage implementation.
+type DeduplicationStorageConfig struct {
+	Logger       *log.Logger
+	ErrorLogger  *log.Logger
+	KeyPrefix    string        // Prefix of every key written by the storage. Default is streams:dedupe.
+	KeyDelimiter string        // Delimiter between key segments. Default is colon (:).
+	KeyTTL       time.Duration // Total duration for a key to be available; Redis removes the key automatically.
+}
+
+// DeduplicationStorage is the Redis streams.DeduplicationStorage implementation. Processed messages are stored as
+// keys with an expiration time (SET NX EX).
+type DeduplicationStorage struct {
+	client redis.UniversalClient
+	cfg    DeduplicationStorageConfig
+}
+
+var _ streams.DeduplicationStorage = DeduplicationStorage{}
+
+// NewDeduplicationStorage allocates a DeduplicationStorage instance.
+func NewDeduplicationStorage(cfg DeduplicationStorageConfig, client redis.UniversalClient) DeduplicationStorage {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.redis: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = deduplicationStorageKeyPrefix
+	}
+	if cfg.KeyDelimiter == "" {
+		cfg.KeyDelimiter = deduplicationStorageKeyDelimiter
+	}
+	if cfg.KeyTTL == 0 {
+		cfg.KeyTTL = time.Minute * 60
+	}
+	return DeduplicationStorage{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+func (d DeduplicationStorage) newKey(workerID, messageID string) string {
+	return strings.Join([]string{d.cfg.KeyPrefix, workerID, messageID}, d.cfg.KeyDelimiter)
+}
+
+func (d DeduplicationStorage) Commit(ctx context.Context, workerID, messageID string) {
+	if err := d.client.SetNX(ctx, d.newKey(workerID, messageID), 1, d.cfg.KeyTTL).Err(); err != nil {
+		d.cfg.ErrorLogger.Printf("failed to commit message, error %s", err.Error())
+		return
+	}
+
+	d.cfg.Logger.Printf("committed message with id <%s> and worker id <%s>", messageID, workerID)
+}
+
+func (d DeduplicationStorage) IsDuplicated(ctx context.Context, workerID, messageID string) (bool, error) {
+	count, err := d.client.Exists(ctx, d.newKey(workerID, messageID)).Result()
+	if err != nil {
+		d.cfg.ErrorLogger.Printf("failed to get message commit, error %s", err.Error())
+		return false, err
+	}
+	return count > 0, nil
+}
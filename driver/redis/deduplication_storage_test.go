@@ -0,0 +1,42 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	streamsredis "github.com/alexandria-oss/streams/driver/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduplicationStorage(t *testing.T) {
+	srv, client := newTestClient(t)
+	storage := streamsredis.NewDeduplicationStorage(streamsredis.DeduplicationStorageConfig{
+		KeyTTL: time.Minute,
+	}, client)
+	ctx := context.Background()
+
+	isDupe, err := storage.IsDuplicated(ctx, "worker-a", "123")
+	require.NoError(t, err)
+	assert.False(t, isDupe)
+
+	storage.Commit(ctx, "worker-a", "123")
+	isDupe, err = storage.IsDuplicated(ctx, "worker-a", "123")
+	require.NoError(t, err)
+	assert.True(t, isDupe)
+	assert.Equal(t, time.Minute, srv.TTL("streams:dedupe:worker-a:123"))
+
+	isDupe, err = storage.IsDuplicated(ctx, "worker-b", "123")
+	require.NoError(t, err)
+	assert.False(t, isDupe)
+
+	srv.FastForward(time.Minute)
+	isDupe, err = storage.IsDuplicated(ctx, "worker-a", "123")
+	require.NoError(t, err)
+	assert.False(t, isDupe)
+
+	srv.Close()
+	_, err = storage.IsDuplicated(ctx, "worker-a", "123")
+	assert.Error(t, err)
+}
@@ -0,0 +1,16 @@
+package redis
+
+const (
+	// HeaderEntryID is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the identifier of the Redis stream entry (e.g. 1526919030474-55).
+	HeaderEntryID = "streams-redis-entry-id"
+	// HeaderGroup is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the consumer group the reader instance is in.
+	HeaderGroup = "streams-redis-group"
+	// HeaderConsumer is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key represents the consumer name of the reader instance within its consumer group.
+	HeaderConsumer = "streams-redis-consumer"
+	// HeaderClaimed is a header key passed to reader handlers -through streams.Message's Headers field-.
+	// This key is set to "true" if the entry was claimed from another consumer (XAUTOCLAIM).
+	HeaderClaimed = "streams-redis-claimed"
+)
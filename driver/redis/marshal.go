@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	fixedFieldCount        = 5
+	fixedHeaderInjectCount = 4
+	messageIDField         = "message_id"
+	streamKeyField         = "stream_key"
+	contentTypeField       = "content_type"
+	dataField              = "data"
+	timeField              = "message_time" // Unix milliseconds.
+	headerFieldPrefix      = "header:"
+)
+
+func marshalMessage(msg streams.Message) map[string]any {
+	values := make(map[string]any, fixedFieldCount+len(msg.Headers))
+	values[messageIDField] = msg.ID
+	values[streamKeyField] = msg.StreamKey
+	values[contentTypeField] = msg.ContentType
+	values[dataField] = msg.Data
+	values[timeField] = strconv.FormatInt(msg.Time.UnixMilli(), 10)
+	for k, v := range msg.Headers {
+		values[headerFieldPrefix+k] = v
+	}
+	return values
+}
+
+func unmarshalMessage(stream string, entry redis.XMessage) streams.Message {
+	msg := streams.Message{
+		StreamName: stream,
+		Headers:    make(map[string]string, len(entry.Values)+fixedHeaderInjectCount),
+	}
+	msg.Headers[HeaderEntryID] = entry.ID
+	for k, v := range entry.Values {
+		val, _ := v.(string)
+		switch k {
+		case messageIDField:
+			msg.ID = val
+		case streamKeyField:
+			msg.StreamKey = val
+		case contentTypeField:
+			msg.ContentType = val
+		case dataField:
+			msg.Data = []byte(val)
+		case timeField:
+			timeMilli, _ := strconv.ParseInt(val, 10, 64)
+			msg.Time = time.UnixMilli(timeMilli)
+		default:
+			if strings.HasPrefix(k, headerFieldPrefix) {
+				msg.Headers[strings.TrimPrefix(k, headerFieldPrefix)] = val
+			}
+		}
+	}
+	return msg
+}
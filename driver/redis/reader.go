@@ -0,0 +1,222 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/internal/genericutil"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ReaderTaskGroupKey is the argument key to set up a reader to be placed in a Redis stream consumer group,
+	// overriding ReaderConfig.Group.
+	ReaderTaskGroupKey string = "redis-group"
+	// ReaderTaskConsumerKey is the argument key to set up the consumer name of a reader within its consumer group,
+	// overriding ReaderConfig.Consumer.
+	ReaderTaskConsumerKey string = "redis-consumer"
+)
+
+// ErrMissingGroup the reader has no consumer group.
+var ErrMissingGroup = errors.New("streams.redis: missing consumer group")
+
+// ReaderConfig is the configuration schema for Redis streams.Reader implementation.
+type ReaderConfig struct {
+	Logger      *log.Logger // Logging instance preferably with log level at <<info>>.
+	ErrorLogger *log.Logger // Logging instance preferably with log level at <<error>>.
+	Group       string      // Consumer group of the reader. Created (along the stream) if not exists.
+	Consumer    string      // Consumer name of the reader within its consumer group. Default is the host name.
+	// Stream entry identifier the consumer group starts to read from once created. Default is $ (new entries only),
+	// use 0 to read the whole stream.
+	GroupStartID   string
+	BatchSize      int64         // Maximum count of entries for each read (XREADGROUP COUNT).
+	BlockDuration  time.Duration // Maximum duration for a read to wait for entries (XREADGROUP BLOCK).
+	HandlerTimeout time.Duration // Maximum duration for message handler processes (streams.ReaderHandleFunc).
+	// Minimum duration an entry must be pending (i.e. delivered but not acknowledged) before it gets claimed from
+	// another consumer (XAUTOCLAIM). Set it greater than HandlerTimeout.
+	ClaimMinIdle time.Duration
+	// Time duration between each claim of pending entries. Entries are not claimed if negative.
+	ClaimInterval time.Duration
+}
+
+// A Reader type is the concrete implementation of streams.Reader using Redis Streams consumer groups.
+//
+// Entries are acknowledged (XACK) once their handler succeeded. Failed entries remain pending, so they are delivered
+// again once claimed by a consumer of the group (XAUTOCLAIM) after ReaderConfig.ClaimMinIdle. Pending entries of
+// the reader consumer (e.g. written before a crash) are read again on start.
+type Reader struct {
+	cfg    ReaderConfig
+	client redis.UniversalClient
+}
+
+var _ streams.Reader = Reader{}
+
+// NewReader allocates a Reader instance.
+func NewReader(cfg ReaderConfig, client redis.UniversalClient) Reader {
+	if cfg.Logger == nil || cfg.ErrorLogger == nil {
+		logger := log.New(os.Stdout, "streams.redis: ", 0)
+		if cfg.Logger == nil {
+			cfg.Logger = logger
+		}
+		if cfg.ErrorLogger == nil {
+			cfg.ErrorLogger = logger
+		}
+	}
+	if cfg.Consumer == "" {
+		cfg.Consumer, _ = os.Hostname()
+	}
+	if cfg.GroupStartID == "" {
+		cfg.GroupStartID = "$"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 10
+	}
+	if cfg.BlockDuration <= 0 {
+		cfg.BlockDuration = time.Second * 5
+	}
+	if cfg.HandlerTimeout == 0 {
+		cfg.HandlerTimeout = time.Second * 30
+	}
+	if cfg.ClaimMinIdle == 0 {
+		cfg.ClaimMinIdle = time.Minute
+	}
+	if cfg.ClaimInterval == 0 {
+		cfg.ClaimInterval = time.Second * 30
+	}
+	return Reader{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+func (r Reader) Read(ctx context.Context, task streams.ReadTask) error {
+	if group := genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskGroupKey]); group != "" {
+		r.cfg.Group = group
+	}
+	if consumer := genericutil.SafeCast[string](task.ExternalArgs[ReaderTaskConsumerKey]); consumer != "" {
+		r.cfg.Consumer = consumer
+	}
+	if r.cfg.Group == "" {
+		return ErrMissingGroup
+	}
+
+	err := r.client.XGroupCreateMkStream(ctx, task.Stream, r.cfg.Group, r.cfg.GroupStartID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	// read pending entries of this consumer first, then new entries
+	lastID := "0"
+	lastClaim := time.Time{}
+	for {
+		if ctx.Err() != nil {
+			r.cfg.Logger.Printf("stopping stream reading process")
+			return nil
+		}
+		if r.cfg.ClaimInterval > 0 && time.Since(lastClaim) >= r.cfg.ClaimInterval {
+			if err = r.claimPending(ctx, task); err != nil && ctx.Err() == nil {
+				r.cfg.ErrorLogger.Printf("error occurred while claiming pending entries, %s", err.Error())
+			}
+			lastClaim = time.Now()
+		}
+
+		res, errRead := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    r.cfg.Group,
+			Consumer: r.cfg.Consumer,
+			Streams:  []string{task.Stream, lastID},
+			Count:    r.cfg.BatchSize,
+			Block:    r.cfg.BlockDuration,
+		}).Result()
+		if errors.Is(errRead, redis.Nil) {
+			continue
+		} else if errRead != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return errRead
+		}
+
+		entries := 0
+		for _, stream := range res {
+			entries += len(stream.Messages)
+			r.handleEntries(ctx, task, stream.Messages, false)
+		}
+		if lastID != ">" && entries == 0 {
+			// every pending entry of this consumer was read
+			lastID = ">"
+		} else if lastID != ">" {
+			lastID = res[0].Messages[len(res[0].Messages)-1].ID
+		}
+	}
+}
+
+// claimPending claims every entry pending for longer than ReaderConfig.ClaimMinIdle (e.g. delivered to a dead
+// consumer) and handles them.
+func (r Reader) claimPending(ctx context.Context, task streams.ReadTask) error {
+	start := "0-0"
+	for {
+		entries, next, err := r.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   task.Stream,
+			Group:    r.cfg.Group,
+			MinIdle:  r.cfg.ClaimMinIdle,
+			Start:    start,
+			Count:    r.cfg.BatchSize,
+			Consumer: r.cfg.Consumer,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			r.cfg.Logger.Printf("claimed <%d> pending entries from stream <%s>", len(entries), task.Stream)
+			r.handleEntries(ctx, task, entries, true)
+		}
+		if next == "0-0" || next == "" {
+			return nil
+		}
+		start = next
+	}
+}
+
+// handleEntries executes the task handler for each entry in order, acknowledging succeeded entries.
+func (r Reader) handleEntries(ctx context.Context, task streams.ReadTask, entries []redis.XMessage, isClaimed bool) {
+	ackBuffer := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Values == nil {
+			// entry was deleted while pending, acknowledge to remove it from the pending entries list
+			ackBuffer = append(ackBuffer, entry.ID)
+			continue
+		}
+		msg := unmarshalMessage(task.Stream, entry)
+		msg.Headers[HeaderGroup] = r.cfg.Group
+		msg.Headers[HeaderConsumer] = r.cfg.Consumer
+		if isClaimed {
+			msg.Headers[HeaderClaimed] = "true"
+		}
+
+		scopedCtx, cancel := context.WithTimeout(ctx, r.cfg.HandlerTimeout)
+		errHandle := task.Handler(scopedCtx, msg)
+		cancel()
+		if errHandle != nil {
+			// do nothing as developers are able to wrap message handler with middleware functions.
+			//
+			// This will avoid acknowledging the entry and thus, the entry will be claimed once ClaimMinIdle ends.
+			continue
+		}
+		ackBuffer = append(ackBuffer, entry.ID)
+	}
+	if len(ackBuffer) == 0 {
+		return
+	}
+
+	// acknowledge even if ctx was cancelled as entries were processed already
+	scopedCtx, cancel := context.WithTimeout(context.Background(), r.cfg.HandlerTimeout)
+	defer cancel()
+	if err := r.client.XAck(scopedCtx, task.Stream, r.cfg.Group, ackBuffer...).Err(); err != nil {
+		r.cfg.ErrorLogger.Printf("failed to acknowledge <%d> entries, %s", len(ackBuffer), err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsredis "github.com/alexandria-oss/streams/driver/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_Read(t *testing.T) {
+	_, client := newTestClient(t)
+	w := streamsredis.NewWriter(streamsredis.WriterConfig{}, client)
+	err := w.Write(context.Background(), []streams.Message{
+		{ID: "1", StreamName: "foo", StreamKey: "foo-key", Headers: map[string]string{"foo": "bar"}, Data: []byte("1")},
+		{ID: "2", StreamName: "foo", Data: []byte("2")},
+	})
+	require.NoError(t, err)
+
+	r := streamsredis.NewReader(streamsredis.ReaderConfig{
+		Consumer:      "consumer-a",
+		GroupStartID:  "0",
+		BlockDuration: time.Millisecond * 50,
+		ClaimMinIdle:  time.Millisecond * 100,
+		ClaimInterval: time.Millisecond * 20,
+	}, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	mu := sync.Mutex{}
+	received := make([]streams.Message, 0, 3)
+	task := streams.ReadTask{
+		Stream: "foo",
+		Handler: func(_ context.Context, msg streams.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, msg)
+			if len(received) == 3 {
+				cancel()
+			}
+			if msg.ID == "2" && msg.Headers[streamsredis.HeaderClaimed] == "" {
+				// entry is claimed once ClaimMinIdle ends
+				return errors.New("generic error")
+			}
+			return nil
+		},
+		ExternalArgs: map[string]any{
+			streamsredis.ReaderTaskGroupKey: "foo-group",
+		},
+	}
+	err = r.Read(ctx, task)
+	require.NoError(t, err)
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "1", received[0].ID)
+	assert.Equal(t, "foo", received[0].StreamName)
+	assert.Equal(t, "foo-key", received[0].StreamKey)
+	assert.Equal(t, "bar", received[0].Headers["foo"])
+	assert.Equal(t, "foo-group", received[0].Headers[streamsredis.HeaderGroup])
+	assert.Equal(t, "consumer-a", received[0].Headers[streamsredis.HeaderConsumer])
+	assert.NotEmpty(t, received[0].Headers[streamsredis.HeaderEntryID])
+	assert.Equal(t, "2", received[1].ID)
+	assert.Equal(t, "2", received[2].ID)
+	assert.Equal(t, "true", received[2].Headers[streamsredis.HeaderClaimed])
+
+	pending, err := client.XPending(context.Background(), "foo", "foo-group").Result()
+	require.NoError(t, err)
+	assert.Zero(t, pending.Count)
+}
+
+func TestReader_ReadMissingGroup(t *testing.T) {
+	_, client := newTestClient(t)
+	r := streamsredis.NewReader(streamsredis.ReaderConfig{}, client)
+	err := r.Read(context.Background(), streams.ReadTask{Stream: "foo"})
+	assert.ErrorIs(t, err, streamsredis.ErrMissingGroup)
+}
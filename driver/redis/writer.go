@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/hashicorp/go-multierror"
+	"github.com/redis/go-redis/v9"
+)
+
+// WriterConfig is the configuration schema for Redis streams.Writer implementation.
+type WriterConfig struct {
+	MaxLen int64 // Maximum count of entries kept by each stream (XADD MAXLEN). Streams are not trimmed if <= 0.
+	// Trim streams using the almost exact matcher (~), which is significantly more efficient than the exact one.
+	ApproxMaxLen bool
+}
+
+// A Writer type is the concrete implementation of streams.Writer using Redis Streams.
+//
+// Every message of a batch is appended to its stream (XADD) within a single pipeline.
+type Writer struct {
+	cfg    WriterConfig
+	client redis.UniversalClient
+}
+
+var _ streams.Writer = Writer{}
+
+// NewWriter allocates a Writer instance.
+func NewWriter(cfg WriterConfig, client redis.UniversalClient) Writer {
+	return Writer{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) error {
+	cmds, err := w.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, msg := range msgBatch {
+			args := &redis.XAddArgs{
+				Stream: msg.StreamName,
+				Values: marshalMessage(msg),
+			}
+			if w.cfg.MaxLen > 0 {
+				args.MaxLen = w.cfg.MaxLen
+				args.Approx = w.cfg.ApproxMaxLen
+			}
+			pipe.XAdd(ctx, args)
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+
+	errs := &multierror.Error{}
+	for _, cmd := range cmds {
+		if errCmd := cmd.Err(); errCmd != nil {
+			errs = multierror.Append(errs, errCmd)
+		}
+	}
+	if errs.Len() == 0 {
+		return err
+	}
+	return errs.ErrorOrNil()
+}
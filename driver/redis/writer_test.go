@@ -0,0 +1,100 @@
+package redis_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	streamsredis "github.com/alexandria-oss/streams/driver/redis"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr: srv.Addr(),
+	})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return srv, client
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		inMaxLen    int64
+		wantFooLen  int64
+		wantErr     bool
+		inCancelCtx bool
+	}{
+		{
+			name:       "unlimited",
+			wantFooLen: 3,
+		},
+		{
+			name:       "trimmed",
+			inMaxLen:   2,
+			wantFooLen: 2,
+		},
+		{
+			name:        "cancelled",
+			inCancelCtx: true,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestClient(t)
+			w := streamsredis.NewWriter(streamsredis.WriterConfig{
+				MaxLen: tt.inMaxLen,
+			}, client)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.inCancelCtx {
+				cancel()
+			}
+
+			msgTime := time.UnixMilli(time.Now().UnixMilli())
+			err := w.Write(ctx, []streams.Message{
+				{ID: "1", StreamName: "foo", Data: []byte("1")},
+				{ID: "2", StreamName: "bar", Data: []byte("2")},
+				{ID: "3", StreamName: "foo", Data: []byte("3")},
+				{
+					ID:          "4",
+					StreamName:  "foo",
+					StreamKey:   "foo-key",
+					Headers:     map[string]string{"foo": "bar"},
+					ContentType: "application/text",
+					Data:        []byte("4"),
+					Time:        msgTime,
+				},
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFooLen, client.XLen(context.Background(), "foo").Val())
+			assert.Equal(t, int64(1), client.XLen(context.Background(), "bar").Val())
+
+			entries, err := client.XRevRangeN(context.Background(), "foo", "+", "-", 1).Result()
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, map[string]any{
+				"message_id":   "4",
+				"stream_key":   "foo-key",
+				"content_type": "application/text",
+				"data":         "4",
+				"message_time": strconv.FormatInt(msgTime.UnixMilli(), 10),
+				"header:foo":   "bar",
+			}, entries[0].Values)
+		})
+	}
+}
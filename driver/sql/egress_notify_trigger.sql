@@ -0,0 +1,16 @@
+-- Notifies egress proxy agents (postgres_notify listener) every time a batch is written into the egress table.
+--
+-- The trigger argument is the notification channel, it MUST match the agent postgres.notify.channel configuration.
+-- Do not combine this trigger with driver/pgx.Writer notifications (disable them using WithNotifyChannel("")),
+-- otherwise every batch gets notified -thus forwarded- twice.
+CREATE OR REPLACE FUNCTION streams_egress_notify() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify(COALESCE(TG_ARGV[0], 'streams_egress'), NEW.batch_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS streams_egress_notify ON streams_egress;
+CREATE TRIGGER streams_egress_notify
+    AFTER INSERT ON streams_egress
+    FOR EACH ROW EXECUTE FUNCTION streams_egress_notify('streams_egress');
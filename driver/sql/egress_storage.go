@@ -3,8 +3,11 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/alexandria-oss/streams"
 	"github.com/alexandria-oss/streams/proxy/egress"
 )
 
@@ -51,14 +54,31 @@ func (e EgressStorage) GetBatch(ctx context.Context, batchID string) (egress.Bat
 		}
 	}()
 
-	query := fmt.Sprintf("SELECT batch_id,raw_data,insert_time FROM %s WHERE batch_id = $1", e.cfg.TableName)
+	query := fmt.Sprintf("SELECT batch_id,raw_data,insert_time,write_retries,last_write_error,parked,publish_time FROM %s WHERE batch_id = $1",
+		e.cfg.TableName)
 	row := conn.QueryRowContext(ctx, query, batchID)
 	if err = row.Err(); err != nil {
 		return egress.Batch{}, err
 	}
 
-	batch := egress.Batch{}
-	return batch, row.Scan(&batch.BatchID, &batch.TransportBatchRaw, &batch.InsertTime)
+	return scanBatch(row)
+}
+
+// scanBatch reads an egress.Batch from row columns: batch_id, raw_data, insert_time, write_retries,
+// last_write_error, parked and publish_time.
+func scanBatch(row interface{ Scan(dest ...any) error }) (egress.Batch, error) {
+	var (
+		batch       egress.Batch
+		lastErr     sql.NullString
+		publishTime sql.NullTime
+	)
+	if err := row.Scan(&batch.BatchID, &batch.TransportBatchRaw, &batch.InsertTime, &batch.WriteRetries, &lastErr,
+		&batch.IsParked, &publishTime); err != nil {
+		return egress.Batch{}, err
+	}
+	batch.LastWriteError = lastErr.String
+	batch.PublishTime = publishTime.Time
+	return batch, nil
 }
 
 func (e EgressStorage) Commit(ctx context.Context, batchID string) error {
@@ -72,7 +92,95 @@ func (e EgressStorage) Commit(ctx context.Context, batchID string) error {
 		}
 	}()
 
+	if e.cfg.RetainPublished {
+		query := fmt.Sprintf("UPDATE %s SET publish_time = $2 WHERE batch_id = $1", e.cfg.TableName)
+		_, err = conn.ExecContext(ctx, query, batchID, time.Now().UTC())
+		return err
+	}
+
 	query := fmt.Sprintf("DELETE FROM %s WHERE batch_id = $1", e.cfg.TableName)
 	_, err = conn.ExecContext(ctx, query, batchID)
 	return err
 }
+
+func (e EgressStorage) RecordFailure(ctx context.Context, batchID string, failure error) (isParked bool, err error) {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if errConn := conn.Close(); errConn != nil {
+			err = errConn
+		}
+	}()
+
+	var lastErr string
+	if failure != nil {
+		lastErr = failure.Error()
+	}
+	isUnrecoverable := errors.Is(failure, streams.ErrUnrecoverable)
+	query := fmt.Sprintf("UPDATE %s SET write_retries = write_retries + 1, last_write_error = $2, "+
+		"parked = (parked OR $3 OR ($4 > 0 AND write_retries + 1 >= $4)) WHERE batch_id = $1 RETURNING parked",
+		e.cfg.TableName)
+	err = conn.QueryRowContext(ctx, query, batchID, lastErr, isUnrecoverable, e.cfg.MaxAttempts).Scan(&isParked)
+	return
+}
+
+func (e EgressStorage) PurgePublished(ctx context.Context, threshold time.Time) (total int64, err error) {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if errConn := conn.Close(); errConn != nil {
+			err = errConn
+		}
+	}()
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE publish_time IS NOT NULL AND publish_time < $1", e.cfg.TableName)
+	res, err := conn.ExecContext(ctx, query, threshold)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ListPending retrieves pending batches sorted by their identifier. Batch identifiers are expected to be
+// time-sortable (e.g. KSUID), so batches are listed roughly by insert time.
+func (e EgressStorage) ListPending(ctx context.Context, threshold time.Time, cursor string,
+	limit int) (batches []egress.Batch, nextCursor string, err error) {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if errConn := conn.Close(); errConn != nil {
+			err = errConn
+		}
+	}()
+
+	query := fmt.Sprintf("SELECT batch_id,raw_data,insert_time,write_retries,last_write_error,parked,publish_time FROM %s "+
+		"WHERE parked = FALSE AND publish_time IS NULL AND insert_time < $1 AND batch_id > $2 ORDER BY batch_id LIMIT $3",
+		e.cfg.TableName)
+	rows, err := conn.QueryContext(ctx, query, threshold, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	batches = make([]egress.Batch, 0, limit)
+	for rows.Next() {
+		batch, errScan := scanBatch(rows)
+		if errScan != nil {
+			return nil, "", errScan
+		}
+		batches = append(batches, batch)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	if len(batches) == limit {
+		nextCursor = batches[len(batches)-1].BatchID
+	}
+	return batches, nextCursor, nil
+}
@@ -5,8 +5,10 @@ package sql_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	streamsql "github.com/alexandria-oss/streams/driver/sql"
 	"github.com/alexandria-oss/streams/proxy/egress"
@@ -62,13 +64,43 @@ func (s *egressStorageIntegrationTestSuite) TestGetBatch() {
 
 	b, err = s.storage.GetBatch(context.TODO(), "123")
 	assert.NoError(s.T(), err)
-	assert.EqualValues(s.T(), egress.Batch{
-		BatchID:           "123",
-		TransportBatchRaw: []byte("the quick brown fox"),
-	}, b)
+	assert.Equal(s.T(), "123", b.BatchID)
+	assert.Equal(s.T(), []byte("the quick brown fox"), b.TransportBatchRaw)
+	assert.Zero(s.T(), b.WriteRetries)
+	assert.False(s.T(), b.IsParked)
+	assert.False(s.T(), b.IsPublished())
+}
+
+func (s *egressStorageIntegrationTestSuite) TestRecordFailure() {
+	storage := streamsql.NewEgressStorage(s.db, egress.WithMaxAttempts(2))
+	isParked, err := storage.RecordFailure(context.TODO(), "123", errors.New("generic error"))
+	s.Require().NoError(err)
+	s.Assert().False(isParked)
+
+	isParked, err = storage.RecordFailure(context.TODO(), "123", errors.New("generic error 2"))
+	s.Require().NoError(err)
+	s.Assert().True(isParked)
+
+	b, err := storage.GetBatch(context.TODO(), "123")
+	s.Require().NoError(err)
+	s.Assert().Equal(2, b.WriteRetries)
+	s.Assert().Equal("generic error 2", b.LastWriteError)
+	s.Assert().True(b.IsParked)
 }
 
 func (s *egressStorageIntegrationTestSuite) TestCommit() {
-	err := s.storage.Commit(context.TODO(), "456")
+	storage := streamsql.NewEgressStorage(s.db, egress.WithPublishedRetention(true))
+	err := storage.Commit(context.TODO(), "456")
+	s.Require().NoError(err)
+
+	b, err := storage.GetBatch(context.TODO(), "456")
+	s.Require().NoError(err)
+	s.Assert().True(b.IsPublished())
+
+	total, err := storage.PurgePublished(context.TODO(), time.Now().UTC().Add(time.Hour))
+	s.Require().NoError(err)
+	s.Assert().EqualValues(1, total)
+
+	err = s.storage.Commit(context.TODO(), "456")
 	assert.NoError(s.T(), err)
 }
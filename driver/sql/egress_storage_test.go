@@ -0,0 +1,151 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressStorage_Commit(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []egress.StorageOption
+		wantQuery string
+	}{
+		{
+			name:      "delete",
+			opts:      nil,
+			wantQuery: "DELETE FROM streams_egress WHERE batch_id = (.+)",
+		},
+		{
+			name:      "retention",
+			opts:      []egress.StorageOption{egress.WithPublishedRetention(true)},
+			wantQuery: "UPDATE streams_egress SET publish_time = (.+) WHERE batch_id = (.+)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(tt.wantQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+			storage := NewEgressStorage(db, tt.opts...)
+			assert.NoError(t, storage.Commit(context.TODO(), "123"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEgressStorage_RecordFailure(t *testing.T) {
+	tests := []struct {
+		name            string
+		inFailure       error
+		inParked        bool
+		wantUnrecovered bool
+	}{
+		{
+			name:            "recoverable",
+			inFailure:       errors.New("generic error"),
+			inParked:        false,
+			wantUnrecovered: false,
+		},
+		{
+			name:            "unrecoverable",
+			inFailure:       streams.ErrUnrecoverableWrap{ParentErr: errors.New("generic error")},
+			inParked:        true,
+			wantUnrecovered: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery("UPDATE streams_egress SET write_retries = (.+) RETURNING parked").
+				WithArgs("123", tt.inFailure.Error(), tt.wantUnrecovered, 5).
+				WillReturnRows(sqlmock.NewRows([]string{"parked"}).AddRow(tt.inParked))
+			storage := NewEgressStorage(db, egress.WithMaxAttempts(5))
+			isParked, err := storage.RecordFailure(context.TODO(), "123", tt.inFailure)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.inParked, isParked)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestEgressStorage_PurgePublished(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	threshold := time.Now().UTC()
+	mock.ExpectExec("DELETE FROM streams_egress WHERE publish_time IS NOT NULL AND publish_time < (.+)").
+		WithArgs(threshold).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	storage := NewEgressStorage(db, egress.WithPublishedRetention(true))
+	total, err := storage.PurgePublished(context.TODO(), threshold)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEgressStorage_ListPending(t *testing.T) {
+	insertTime := time.Now().UTC()
+	tests := []struct {
+		name       string
+		inCursor   string
+		inLimit    int
+		inRows     []string
+		wantCursor string
+	}{
+		{
+			name:       "full page",
+			inCursor:   "",
+			inLimit:    2,
+			inRows:     []string{"1", "2"},
+			wantCursor: "2",
+		},
+		{
+			name:       "last page",
+			inCursor:   "2",
+			inLimit:    2,
+			inRows:     []string{"3"},
+			wantCursor: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			rows := sqlmock.NewRows([]string{"batch_id", "raw_data", "insert_time", "write_retries",
+				"last_write_error", "parked", "publish_time"})
+			for _, batchID := range tt.inRows {
+				rows.AddRow(batchID, []byte("foo"), insertTime, 0, nil, false, nil)
+			}
+			mock.ExpectQuery("SELECT (.+) FROM streams_egress WHERE parked = FALSE AND publish_time IS NULL AND "+
+				"insert_time < (.+) AND batch_id > (.+) ORDER BY batch_id LIMIT (.+)").
+				WithArgs(insertTime, tt.inCursor, tt.inLimit).
+				WillReturnRows(rows)
+			storage := NewEgressStorage(db)
+			batches, cursor, err := storage.ListPending(context.TODO(), insertTime, tt.inCursor, tt.inLimit)
+			require.NoError(t, err)
+			assert.Len(t, batches, len(tt.inRows))
+			assert.Equal(t, tt.wantCursor, cursor)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
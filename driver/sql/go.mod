@@ -7,6 +7,7 @@ replace github.com/alexandria-oss/streams => ../../
 require (
 	github.com/DATA-DOG/go-sqlmock v1.5.0
 	github.com/alexandria-oss/streams v0.0.1-alpha.7
+	github.com/hashicorp/go-multierror v1.1.1
 	github.com/lib/pq v1.10.7
 	github.com/stretchr/testify v1.8.2
 )
@@ -17,7 +18,6 @@ require (
 	github.com/eapache/go-resiliency v1.3.0 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/hashicorp/errwrap v1.0.0 // indirect
-	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
@@ -0,0 +1,41 @@
+package sql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/ingress"
+	"github.com/hashicorp/go-multierror"
+)
+
+// WithInbox appends to streams.ReaderHandleFunc(s) the inbox messaging pattern. For each message, a sql.Tx is started
+// through txManager (TxManager.WithinTx) and shared with the handler through a transaction context
+// (persistence.SetTransactionContext). Then, the message is registered into the ingress table (ingress.Storage) and
+// the handler is executed within the same transaction.
+//
+// Thus, handler effects and the message registry are committed atomically, giving exactly-once effects on
+// message consumption. Messages already registered by workerID are skipped.
+//
+// Handlers MUST use the transaction from the context (persistence.GetTransactionContext) for their effects to be
+// part of the transaction. Messages written by handlers (Writer) are batched using an identifier generated by
+// txManager, and the egress proxy agent is notified after commit as in any other TxManager transaction.
+func WithInbox(txManager TxManager, workerID string, storage ingress.Storage) streams.ReaderMiddlewareFunc {
+	return func(next streams.ReaderHandleFunc) streams.ReaderHandleFunc {
+		return func(ctx context.Context, msg streams.Message) error {
+			err := txManager.WithinTx(ctx, func(scopedCtx context.Context) error {
+				if err := storage.Commit(scopedCtx, workerID, msg.ID); err != nil {
+					return err
+				}
+				return next(scopedCtx, msg)
+			})
+
+			// a failed rollback is reported as a multi error
+			var errRollback *multierror.Error
+			if errors.Is(err, ingress.ErrMessageAlreadyProcessed) && !errors.As(err, &errRollback) {
+				return nil // ensure idempotency
+			}
+			return err
+		}
+	}
+}
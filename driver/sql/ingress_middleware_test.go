@@ -0,0 +1,151 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithInbox(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		inHandleErr error
+		wantHandled bool
+		wantErr     error
+	}{
+		{
+			name: "new message",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO streams_ingress(.+) ON CONFLICT DO NOTHING").
+					WithArgs("123", "worker-0", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantHandled: true,
+		},
+		{
+			name: "duplicated message",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO streams_ingress(.+) ON CONFLICT DO NOTHING").
+					WithArgs("123", "worker-0", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantHandled: false,
+		},
+		{
+			name: "handler failure",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO streams_ingress(.+) ON CONFLICT DO NOTHING").
+					WithArgs("123", "worker-0", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			inHandleErr: errors.New("handler error"),
+			wantHandled: true,
+			wantErr:     errors.New("handler error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			tt.setupMock(mock)
+
+			wasHandled := false
+			txManager := newTestTxManager(db, nil)
+			handler := WithInbox(txManager, "worker-0", NewIngressStorage())(func(ctx context.Context, msg streams.Message) error {
+				wasHandled = true
+				txCtx, errTx := persistence.GetTransactionContext[*sql.Tx](ctx)
+				assert.NoError(t, errTx)
+				assert.Equal(t, "batch-0", txCtx.TransactionID) // message identifiers are not used as batch identifiers
+				return tt.inHandleErr
+			})
+			err = handler(context.TODO(), streams.Message{ID: "123"})
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantHandled, wasHandled)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWithInbox_RollbackFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	errHandler := errors.New("handler error")
+	errRollback := errors.New("rollback error")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO streams_ingress(.+) ON CONFLICT DO NOTHING").
+		WithArgs("123", "worker-0", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback().WillReturnError(errRollback)
+
+	handler := WithInbox(newTestTxManager(db, nil), "worker-0",
+		NewIngressStorage())(func(_ context.Context, _ streams.Message) error {
+		return errHandler
+	})
+	err = handler(context.TODO(), streams.Message{ID: "123"})
+	assert.ErrorIs(t, err, errHandler)
+	assert.ErrorIs(t, err, errRollback)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func newTestTxManager(db *sql.DB, notifier *notifierSpy) TxManager {
+	cfg := TxManagerConfig{
+		DB: db,
+		IdentifierFactory: func() (string, error) {
+			return "batch-0", nil
+		},
+	}
+	if notifier != nil {
+		cfg.Notifier = notifier
+	}
+	return NewTxManager(cfg)
+}
+
+func TestWithInbox_Publish(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO streams_ingress(.+) ON CONFLICT DO NOTHING").
+		WithArgs("a-consumed-message-identifier-longer-than-27-chars", "worker-0", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("INSERT INTO streams_egress(.+) VALUES (.+)").WillBeClosed().
+		ExpectExec().WithArgs("batch-0", 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	notifier := &notifierSpy{}
+	handler := WithInbox(newTestTxManager(db, notifier), "worker-0",
+		NewIngressStorage())(func(ctx context.Context, msg streams.Message) error {
+		return NewWriter().Write(ctx, []streams.Message{{ID: "abc", StreamName: "bar", Data: msg.Data}})
+	})
+	err = handler(context.TODO(), streams.Message{
+		ID:         "a-consumed-message-identifier-longer-than-27-chars",
+		StreamName: "foo",
+		Data:       []byte("the quick brown fox"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"batch-0"}, notifier.batchIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngressStorage_Commit(t *testing.T) {
+	err := NewIngressStorage().Commit(context.TODO(), "worker-0", "123")
+	assert.ErrorIs(t, err, persistence.ErrTransactionContextNotFound)
+}
@@ -0,0 +1,65 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/ingress"
+)
+
+// A IngressStorage is a SQL implementation of ingress.Storage. Enables interaction with
+// a stream ingress table (aka. inbox).
+//
+// IngressStorage instances MUST be used along transaction context functions
+// (persistence.SetTransactionContext, persistence.GetTransactionContext). This is because IngressStorage instances
+// obtain the sql.Tx instance from the context. If no context is found, then IngressStorage.Commit will fail.
+type IngressStorage struct {
+	cfg ingress.StorageConfig
+}
+
+var _ ingress.Storage = IngressStorage{}
+
+func newIngressStorageDefaults() ingress.StorageConfig {
+	return ingress.StorageConfig{
+		TableName: ingress.DefaultIngressTableName,
+	}
+}
+
+// NewIngressStorage allocates a new IngressStorage instance with default configuration but open to apply any ingress.StorageOption(s).
+func NewIngressStorage(opts ...ingress.StorageOption) IngressStorage {
+	baseOpts := newIngressStorageDefaults()
+	for _, o := range opts {
+		o.Apply(&baseOpts)
+	}
+	return NewIngressStorageWithConfig(baseOpts)
+}
+
+// NewIngressStorageWithConfig allocates a new IngressStorage instance with a specific ingress.StorageConfig.
+func NewIngressStorageWithConfig(cfg ingress.StorageConfig) IngressStorage {
+	return IngressStorage{
+		cfg: cfg,
+	}
+}
+
+// Commit registers a message as processed by a worker into the ingress table.
+//
+// A transaction context (persistence.SetTransactionContext) MUST be set before calling this routine.
+func (i IngressStorage) Commit(ctx context.Context, workerID, messageID string) error {
+	txCtx, err := persistence.GetTransactionContext[*sql.Tx](ctx)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s(message_id,worker_id,insert_time) VALUES ($1,$2,$3) ON CONFLICT DO NOTHING",
+		i.cfg.TableName)
+	res, err := txCtx.Tx.ExecContext(ctx, query, messageID, workerID, time.Now().UTC())
+	if err != nil {
+		return err
+	} else if writeRowCount, _ := res.RowsAffected(); writeRowCount <= 0 {
+		return ingress.ErrMessageAlreadyProcessed
+	}
+	return nil
+}
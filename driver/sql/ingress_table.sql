@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS streams_ingress(
+    message_id VARCHAR(128) NOT NULL,
+    worker_id VARCHAR(128) NOT NULL,
+    insert_time TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (message_id, worker_id)
+);
@@ -0,0 +1,108 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/hashicorp/go-multierror"
+)
+
+// A TxManagerConfig is the TxManager configuration.
+type TxManagerConfig struct {
+	DB                *sql.DB                   // Database to begin transactions from.
+	TxOptions         *sql.TxOptions            // Options used to begin transactions (optional).
+	IdentifierFactory streams.IdentifierFactory // Used to generate batch identifiers (default streams.NewKSUID).
+	// Notifies the egress proxy agent after a transaction with message batches was committed. Leave nil if
+	// notifications are not required (e.g. a CDC agent is listening to the egress table).
+	Notifier egress.Notifier
+}
+
+// A TxManager is a helper component used by systems implementing the transactional outbox messaging pattern. It
+// manages sql.Tx lifecycles and shares them with Writer instances through transaction contexts
+// (persistence.SetTransactionContext).
+//
+// Zero-value IS NOT ready to use, please call NewTxManager routine instead.
+type TxManager struct {
+	cfg TxManagerConfig
+}
+
+// NewTxManager allocates a TxManager instance.
+func NewTxManager(cfg TxManagerConfig) TxManager {
+	if cfg.IdentifierFactory == nil {
+		cfg.IdentifierFactory = streams.NewKSUID
+	}
+	return TxManager{
+		cfg: cfg,
+	}
+}
+
+type txStateContextKeyType struct{}
+
+var txStateContextKey = txStateContextKeyType{}
+
+// txState is the state of a transaction managed by a TxManager. Writer instances flag the state whenever a message
+// batch is written, so TxManager skips agent notifications for transactions with no message batches.
+type txState struct {
+	hasBatch int32 // accessed atomically as Writer(s) might run concurrently
+}
+
+func markBatchWritten(ctx context.Context) {
+	if state, ok := ctx.Value(txStateContextKey).(*txState); ok {
+		atomic.StoreInt32(&state.hasBatch, 1)
+	}
+}
+
+// WithinTx executes fn within a sql.Tx. The transaction is set into fn's context (persistence.SetTransactionContext)
+// using a batch identifier generated by TxManagerConfig.IdentifierFactory as TransactionContext.TransactionID.
+//
+// The transaction is rolled back if fn returns an error or panics; it is committed otherwise. After a successful
+// commit, the egress proxy agent is notified (TxManagerConfig.Notifier) if a Writer wrote a message batch. A
+// notification error is returned even though the transaction was committed.
+//
+// Nested calls (i.e. ctx already holds a sql.Tx transaction context) reuse the outer transaction. Thus, commit,
+// rollback and notification are left to the outer call.
+func (m TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, errTx := persistence.GetTransactionContext[*sql.Tx](ctx); errTx == nil {
+		return fn(ctx)
+	}
+
+	batchID, err := m.cfg.IdentifierFactory()
+	if err != nil {
+		return err
+	}
+	tx, err := m.cfg.DB.BeginTx(ctx, m.cfg.TxOptions)
+	if err != nil {
+		return err
+	}
+
+	state := &txState{}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		} else if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				err = multierror.Append(err, errRollback)
+			}
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			return
+		} else if m.cfg.Notifier != nil && atomic.LoadInt32(&state.hasBatch) == 1 {
+			err = m.cfg.Notifier.NotifyAgent(batchID)
+		}
+	}()
+
+	scopedCtx := context.WithValue(ctx, txStateContextKey, state)
+	scopedCtx = persistence.SetTransactionContext(scopedCtx, persistence.TransactionContext[*sql.Tx]{
+		TransactionID: batchID,
+		Tx:            tx,
+	})
+	err = fn(scopedCtx)
+	return
+}
@@ -0,0 +1,185 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notifierSpy struct {
+	batchIDs []string
+	err      error
+}
+
+func (n *notifierSpy) NotifyAgent(batchID string) error {
+	n.batchIDs = append(n.batchIDs, batchID)
+	return n.err
+}
+
+func TestTxManager_WithinTx(t *testing.T) {
+	idFactory := func() (string, error) {
+		return "123", nil
+	}
+	expectWrite := func(mock sqlmock.Sqlmock) {
+		mock.ExpectPrepare("INSERT INTO streams_egress(.+) VALUES (.+)").WillBeClosed().
+			ExpectExec().WithArgs("123", 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	writeFunc := func(ctx context.Context) error {
+		return NewWriter().Write(ctx, []streams.Message{{ID: "abc", StreamName: "foo"}})
+	}
+
+	tests := []struct {
+		name          string
+		setupMock     func(mock sqlmock.Sqlmock)
+		inFunc        func(m TxManager) func(ctx context.Context) error
+		inNotifierErr error
+		wantErr       error
+		wantNotified  []string
+		wantPanic     bool
+	}{
+		{
+			name: "commit and notify",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectWrite(mock)
+				mock.ExpectCommit()
+			},
+			inFunc: func(_ TxManager) func(ctx context.Context) error {
+				return writeFunc
+			},
+			wantNotified: []string{"123"},
+		},
+		{
+			name: "commit without batches",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			inFunc: func(_ TxManager) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					return nil
+				}
+			},
+		},
+		{
+			name: "notification failure",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectWrite(mock)
+				mock.ExpectCommit()
+			},
+			inFunc: func(_ TxManager) func(ctx context.Context) error {
+				return writeFunc
+			},
+			inNotifierErr: errors.New("agent unavailable"),
+			wantErr:       errors.New("agent unavailable"),
+			wantNotified:  []string{"123"},
+		},
+		{
+			name: "rollback on error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectWrite(mock)
+				mock.ExpectRollback()
+			},
+			inFunc: func(_ TxManager) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					_ = writeFunc(ctx)
+					return errors.New("generic error")
+				}
+			},
+			wantErr: errors.New("generic error"),
+		},
+		{
+			name: "rollback on panic",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			inFunc: func(_ TxManager) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					panic("generic panic")
+				}
+			},
+			wantPanic: true,
+		},
+		{
+			name: "nested calls",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectWrite(mock)
+				mock.ExpectCommit()
+			},
+			inFunc: func(m TxManager) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					outerTx, err := persistence.GetTransactionContext[*sql.Tx](ctx)
+					require.NoError(t, err)
+					return m.WithinTx(ctx, func(ctx context.Context) error {
+						innerTx, errInner := persistence.GetTransactionContext[*sql.Tx](ctx)
+						require.NoError(t, errInner)
+						assert.Equal(t, outerTx, innerTx)
+						return writeFunc(ctx)
+					})
+				}
+			},
+			wantNotified: []string{"123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			tt.setupMock(mock)
+
+			notifier := &notifierSpy{err: tt.inNotifierErr}
+			m := NewTxManager(TxManagerConfig{
+				DB:                db,
+				IdentifierFactory: idFactory,
+				Notifier:          notifier,
+			})
+			if tt.wantPanic {
+				assert.Panics(t, func() {
+					_ = m.WithinTx(context.TODO(), tt.inFunc(m))
+				})
+			} else {
+				err = m.WithinTx(context.TODO(), tt.inFunc(m))
+				assert.Equal(t, tt.wantErr, err)
+			}
+			assert.Equal(t, tt.wantNotified, notifier.batchIDs)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTxManager_WithinTx_RollbackFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	errFunc := errors.New("generic error")
+	errRollback := errors.New("rollback error")
+	mock.ExpectBegin()
+	mock.ExpectRollback().WillReturnError(errRollback)
+
+	notifier := &notifierSpy{}
+	m := NewTxManager(TxManagerConfig{
+		DB:       db,
+		Notifier: notifier,
+	})
+	err = m.WithinTx(context.TODO(), func(_ context.Context) error {
+		return errFunc
+	})
+	assert.ErrorIs(t, err, errFunc)
+	assert.ErrorIs(t, err, errRollback)
+	assert.Empty(t, notifier.batchIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -102,7 +102,9 @@ func (w Writer) Write(ctx context.Context, msgBatch []streams.Message) (err erro
 		return err
 	} else if writeRowCount, _ := res.RowsAffected(); writeRowCount <= 0 {
 		err = ErrUnableToWriteRows
+		return
 	}
 
+	markBatchWritten(ctx)
 	return
 }
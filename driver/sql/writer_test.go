@@ -65,7 +65,7 @@ func TestWriter_Write(t *testing.T) {
 	require.NoError(t, errTx)
 
 	mock.ExpectPrepare("INSERT INTO (.+) VALUES (.+)").WillBeClosed().
-		ExpectExec().WithArgs("1", 2, sqlmock.AnyArg()).
+		ExpectExec().WithArgs("1", 2, sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(123, 1))
 
 	tests := []struct {
@@ -11,6 +11,7 @@ var (
 	ErrUnrecoverable          = errors.New("streams: unrecoverable error")
 	ErrEventNotFound          = errors.New("streams: event not found")
 	ErrNoSubscriberRegistered = errors.New("streams: subscriber scheduler has no subscriber tasks")
+	ErrRouteNotFound          = errors.New("streams: no route found for message")
 )
 
 // A ErrUnrecoverableWrap is a special wrapper for certain type of errors with no recoverable action.
@@ -61,8 +61,11 @@ func main() {
 
 	repo := storage.PaymentSQL{}
 	var cmdBus domain.SyncCommandBus = domain.NewMemoryCommandBus()
-	_ = cmdBus.Register(payment.CreateCommand{},
-		storage.WithEmbeddedEgressAgent(notifier, storage.WithSQLTransaction(db, payment.HandleCreateCommand)))
+	txManager := streamsql.NewTxManager(streamsql.TxManagerConfig{
+		DB:       db,
+		Notifier: notifier,
+	})
+	_ = cmdBus.Register(payment.CreateCommand{}, storage.WithSQLTransaction(txManager, payment.HandleCreateCommand))
 	egressWriter := streamsql.NewWriter(streamsql.WithEgressTable("streams_egress"))
 	payment.DefaultService = payment.NewService(repo, egressWriter)
 
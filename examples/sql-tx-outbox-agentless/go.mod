@@ -3,7 +3,7 @@ module sample
 go 1.18
 
 require (
-	github.com/alexandria-oss/streams v0.0.1-alpha.5
+	github.com/alexandria-oss/streams v0.0.1-alpha.7
 	github.com/alexandria-oss/streams/driver/kafka v0.0.0-20230320031154-f7c183d65d17
 	github.com/alexandria-oss/streams/driver/sql v0.0.0-00010101000000-000000000000
 	github.com/google/uuid v1.3.0
@@ -14,6 +14,7 @@ require (
 )
 
 require (
+	github.com/allegro/bigcache/v3 v3.1.0 // indirect
 	github.com/eapache/go-resiliency v1.3.0 // indirect
 	github.com/hashicorp/errwrap v1.0.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
@@ -13,5 +13,9 @@ CREATE TABLE IF NOT EXISTS streams_egress(
     batch_id CHAR(27) PRIMARY KEY,
     message_count INTEGER DEFAULT 0,
     raw_data BYTEA NOT NULL,
-    insert_time TIMESTAMP DEFAULT NOW()
+    insert_time TIMESTAMP DEFAULT NOW(),
+    write_retries INTEGER DEFAULT 0,
+    last_write_error TEXT,
+    parked BOOLEAN DEFAULT FALSE,
+    publish_time TIMESTAMP
 );
@@ -0,0 +1,7 @@
+package egress
+
+import "errors"
+
+var (
+	ErrBatchParked = errors.New("streams.egress: batch has been parked")
+)
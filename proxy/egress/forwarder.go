@@ -3,8 +3,12 @@ package egress
 import (
 	"context"
 	"errors"
+	"hash/fnv"
 	"log"
 	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexandria-oss/streams"
@@ -26,6 +30,12 @@ type Forwarder struct {
 	workerSchedBus *chanbuf.Bus
 	schedReader    streams.Reader
 	schedWriter    streams.Writer
+	retry          *retrier.Retrier
+	pool           *forwardPool // runs forward jobs with MaxConcurrentJobs workers, nil if unlimited or if lanes are used.
+	lanes          []*forwardLane
+	lanesStop      chan struct{}
+	lanesWg        *sync.WaitGroup
+	state          *forwarderState
 }
 
 // A ForwarderConfig is the configuration used by a Forwarder.
@@ -39,6 +49,19 @@ type ForwarderConfig struct {
 	ForwardJobTotalRetries    int            // Maximum count a forward job will be retried.
 	ForwardJobRetryBackoff    time.Duration  // Initial time duration between each retry process.
 	ForwardJobRetryBackoffMax time.Duration  // Maximum time duration between each retry process.
+	// Maximum count of forward jobs running at the same time. Unlimited if <= 0 and unordered forwarding without
+	// coalescing is used; runtime.NumCPU otherwise.
+	MaxConcurrentJobs int
+	// Maximum count of forward jobs waiting for a worker if unordered forwarding without coalescing is used along
+	// MaxConcurrentJobs > 0. Forward calls block while the queue is full.
+	MaxQueuedJobs int
+	// Strategy used to schedule forward jobs. Ordered strategies forward batches sequentially through job lanes.
+	Ordering ForwardOrdering
+	// Maximum count of batches coalesced into a single streams.Writer.Write call. Coalescing is disabled if <= 1.
+	MaxCoalescedBatches int
+	// Time duration a job lane waits for more batches to coalesce before writing them. Only queued batches are
+	// coalesced if <= 0.
+	CoalesceWindow time.Duration
 }
 
 func NewForwarderDefaultConfig() ForwarderConfig {
@@ -52,6 +75,11 @@ func NewForwarderDefaultConfig() ForwarderConfig {
 		ForwardJobTotalRetries:    3,
 		ForwardJobRetryBackoff:    time.Second * 5,
 		ForwardJobRetryBackoffMax: time.Second * 10,
+		MaxConcurrentJobs:         0,
+		MaxQueuedJobs:             1024,
+		Ordering:                  UnorderedForwarding,
+		MaxCoalescedBatches:       1,
+		CoalesceWindow:            0,
 	}
 }
 
@@ -64,27 +92,61 @@ func NewForwarder(cfg ForwarderConfig) Forwarder {
 		ReaderHandlerTimeout: cfg.ForwardJobTimeout,
 		Logger:               cfg.Logger,
 	})
-	return Forwarder{
+	retry := retrier.New(retrier.LimitedExponentialBackoff(
+		cfg.ForwardJobTotalRetries, cfg.ForwardJobRetryBackoff, cfg.ForwardJobRetryBackoffMax),
+		retrier.BlacklistClassifier{
+			streams.ErrUnrecoverable,
+		},
+	)
+	retry.SetJitter(0.75)
+	fwd := Forwarder{
 		cfg:            cfg,
 		workerSchedBus: bus,
 		schedReader:    chanbuf.NewReader(bus),
 		schedWriter:    chanbuf.NewWriter(bus),
+		retry:          retry,
+		state:          newForwarderState(),
+	}
+	if !fwd.useLanes() {
+		if cfg.MaxConcurrentJobs > 0 {
+			fwd.pool = newForwardPool(cfg.MaxQueuedJobs)
+		}
+		return fwd
 	}
+
+	totalLanes := cfg.MaxConcurrentJobs
+	if cfg.Ordering == InsertTimeForwarding {
+		totalLanes = 1
+	} else if totalLanes <= 0 {
+		totalLanes = runtime.NumCPU()
+	}
+	fwd.lanes = make([]*forwardLane, 0, totalLanes)
+	for i := 0; i < totalLanes; i++ {
+		fwd.lanes = append(fwd.lanes, newForwardLane())
+	}
+	fwd.lanesStop = make(chan struct{})
+	fwd.lanesWg = &sync.WaitGroup{}
+	return fwd
+}
+
+// useLanes indicates if forward jobs are scheduled through job lanes (i.e. ordered forwarding or coalescing).
+func (f Forwarder) useLanes() bool {
+	return f.cfg.Ordering != UnorderedForwarding || f.cfg.MaxCoalescedBatches > 1
 }
 
 // Start initializes the Forwarder instance, blocking the I/O.
 // The instance contains internal job scheduling mechanisms for asynchronous job processing.
 func (f Forwarder) Start() error {
-	retry := retrier.New(retrier.LimitedExponentialBackoff(
-		f.cfg.ForwardJobTotalRetries, f.cfg.ForwardJobRetryBackoff, f.cfg.ForwardJobRetryBackoffMax),
-		retrier.BlacklistClassifier{
-			streams.ErrUnrecoverable,
-		},
-	)
-	retry.SetJitter(0.75)
+	// Job lanes retry forward jobs by themselves, so ordering is kept between retries.
+	middleware := streams.WithReaderRetry(f.retry)
+	if f.useLanes() {
+		middleware = func(next streams.ReaderHandleFunc) streams.ReaderHandleFunc {
+			return next
+		}
+	}
 	err := f.schedReader.Read(context.Background(), streams.ReadTask{
 		Stream:       forwarderRawWorkerStream,
-		Handler:      streams.WithReaderRetry(retry)(streams.WithReaderErrorLogger(f.cfg.Logger)(f.scheduleRawJob)),
+		Handler:      middleware(streams.WithReaderErrorLogger(f.cfg.Logger)(f.scheduleRawJob)),
 		ExternalArgs: nil,
 	})
 	if err != nil {
@@ -92,28 +154,74 @@ func (f Forwarder) Start() error {
 	}
 	err = f.schedReader.Read(context.Background(), streams.ReadTask{
 		Stream:       forwarderWorkerStream,
-		Handler:      streams.WithReaderRetry(retry)(streams.WithReaderErrorLogger(f.cfg.Logger)(f.scheduleJob)),
+		Handler:      middleware(streams.WithReaderErrorLogger(f.cfg.Logger)(f.scheduleJob)),
 		ExternalArgs: nil,
 	})
 	if err != nil {
 		return err
 	}
 	f.cfg.Logger.Printf("starting forwarder")
+	for _, lane := range f.lanes {
+		f.lanesWg.Add(1)
+		go f.runLane(lane)
+	}
+	if f.pool != nil {
+		f.pool.start(f.cfg.MaxConcurrentJobs, f.runPoolJob)
+	}
+	atomic.StoreInt32(&f.state.isRunning, 1)
 	f.workerSchedBus.Start()
 	return nil
 }
 
-// Shutdown gracefully shuts down the Forwarder instance.
+// Shutdown gracefully shuts down the Forwarder instance, waiting for every scheduled job to finish.
 func (f Forwarder) Shutdown() {
-	f.cfg.Logger.Print("shutting forwarder down")
-	f.workerSchedBus.Shutdown()
-	f.cfg.Logger.Print("forwarder has been terminated")
+	f.state.shutdownOnce.Do(func() {
+		f.cfg.Logger.Print("shutting forwarder down")
+		atomic.StoreInt32(&f.state.isRunning, 0)
+		f.workerSchedBus.Shutdown()
+		if f.useLanes() {
+			close(f.lanesStop)
+			f.lanesWg.Wait()
+		} else if f.pool != nil {
+			f.pool.close()
+		}
+		f.state.cancelJobs()
+		f.cfg.Logger.Print("forwarder has been terminated")
+	})
+}
+
+// Drain gracefully shuts down the Forwarder instance within ctx deadline. Jobs not started yet are skipped, so their
+// batches remain in the Storage and may be forwarded later (e.g. on next agent start). In-flight jobs are cancelled
+// if ctx is done before they finish.
+func (f Forwarder) Drain(ctx context.Context) error {
+	f.cfg.Logger.Print("draining forwarder")
+	f.state.startDrain()
+	done := make(chan struct{})
+	go func() {
+		f.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		f.state.cancelJobs()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Stats retrieves a snapshot of the Forwarder runtime statistics.
+func (f Forwarder) Stats() ForwarderStats {
+	return f.state.stats()
 }
 
 // Forward triggers a new forward job for the specified batch.
 func (f Forwarder) Forward(batchID string) error {
 	if len(batchID) == 0 {
 		return streams.ErrEmptyMessage
+	} else if f.pool != nil {
+		return f.pushJob(Batch{BatchID: batchID})
 	}
 	return f.schedWriter.Write(context.Background(), []streams.Message{
 		{
@@ -125,6 +233,12 @@ func (f Forwarder) Forward(batchID string) error {
 
 // ForwardBatch triggers a new forward job for the specified batch.
 func (f Forwarder) ForwardBatch(batch Batch) error {
+	if f.pool != nil {
+		if len(batch.BatchID) == 0 {
+			return streams.ErrEmptyMessage
+		}
+		return f.pushJob(batch)
+	}
 	return f.schedWriter.Write(context.Background(), []streams.Message{
 		{
 			StreamName:  forwarderWorkerStream,
@@ -136,7 +250,7 @@ func (f Forwarder) ForwardBatch(batch Batch) error {
 
 func (f Forwarder) scheduleRawJob(ctx context.Context, msg streams.Message) error {
 	batchID := string(msg.Data)
-	return f.sendBatch(ctx, Batch{BatchID: batchID})
+	return f.runJob(ctx, Batch{BatchID: batchID})
 }
 
 func (f Forwarder) scheduleJob(ctx context.Context, msg streams.Message) error {
@@ -144,9 +258,243 @@ func (f Forwarder) scheduleJob(ctx context.Context, msg streams.Message) error {
 	if !ok {
 		return errors.New("forwarder: invalid batch")
 	}
+	return f.runJob(ctx, batch)
+}
+
+func (f Forwarder) runJob(ctx context.Context, batch Batch) (err error) {
+	if f.state.isDraining() {
+		f.skipJob(batch.BatchID)
+		return nil
+	}
+	ctx, cancel := f.state.newJobContext(ctx)
+	defer cancel()
+	atomic.AddInt64(&f.state.queuedJobs, 1)
+	if f.useLanes() {
+		return f.enqueueJob(ctx, batch)
+	}
+
+	atomic.AddInt64(&f.state.queuedJobs, -1)
+	atomic.AddInt64(&f.state.inFlightJobs, 1)
+	defer func() {
+		atomic.AddInt64(&f.state.inFlightJobs, -1)
+		if err != nil {
+			atomic.AddUint64(&f.state.failedJobs, 1)
+		}
+	}()
 	return f.sendBatch(ctx, batch)
 }
 
+// pushJob schedules batch into the forwardPool, blocking the I/O while the pool queue is full.
+func (f Forwarder) pushJob(batch Batch) error {
+	atomic.AddInt64(&f.state.queuedJobs, 1)
+	isQueued, err := f.pool.push(batch, f.state.drain)
+	if isQueued {
+		return nil
+	}
+	atomic.AddInt64(&f.state.queuedJobs, -1)
+	if err == nil {
+		f.skipJob(batch.BatchID)
+	}
+	return err
+}
+
+// runPoolJob executes a forward job taken from the forwardPool. The job is retried by itself as it does not go
+// through the internal bus.
+func (f Forwarder) runPoolJob(batch Batch) {
+	atomic.AddInt64(&f.state.queuedJobs, -1)
+	if f.state.isDraining() {
+		f.skipJob(batch.BatchID)
+		return
+	}
+	atomic.AddInt64(&f.state.inFlightJobs, 1)
+	defer atomic.AddInt64(&f.state.inFlightJobs, -1)
+
+	ctx, cancel := context.WithTimeout(f.state.jobsCtx, f.cfg.ForwardJobTimeout)
+	defer cancel()
+	err := f.retry.RunCtx(ctx, func(ctx context.Context) error {
+		return f.sendBatch(ctx, batch)
+	})
+	if err != nil {
+		atomic.AddUint64(&f.state.failedJobs, 1)
+		f.cfg.Logger.Print(err)
+	}
+}
+
+func (f Forwarder) skipJob(batchID string) {
+	atomic.AddUint64(&f.state.skippedJobs, 1)
+	f.cfg.Logger.Printf("skipping batch_id <%s>, forwarder is draining", batchID)
+}
+
+// enqueueJob schedules batch into a job lane, blocking the I/O until the job is done.
+func (f Forwarder) enqueueJob(ctx context.Context, batch Batch) error {
+	if len(batch.TransportBatchRaw) == 0 {
+		err := f.retry.RunCtx(ctx, func(ctx context.Context) (err error) {
+			batch, err = f.cfg.Storage.GetBatch(ctx, batch.BatchID)
+			return
+		})
+		if err != nil {
+			atomic.AddInt64(&f.state.queuedJobs, -1) // job never reached a lane
+			return err
+		}
+	}
+
+	job := forwardJob{
+		batch: batch,
+		done:  make(chan error, 1),
+	}
+	transportBatch := &persistence.TransportMessageBatch{}
+	if job.decodeErr = f.cfg.Codec.Decode(batch.TransportBatchRaw, transportBatch); job.decodeErr == nil {
+		job.msgs = persistence.NewMessages(transportBatch)
+	}
+
+	f.lanes[f.laneIndex(job)].push(job)
+	select {
+	case err := <-job.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// laneIndex selects the job lane for job. Batches are distributed by their identifier unless stream key ordering is
+// used.
+func (f Forwarder) laneIndex(job forwardJob) int {
+	if len(f.lanes) == 1 {
+		return 0
+	}
+	hash := fnv.New32a()
+	if f.cfg.Ordering == StreamKeyForwarding && len(job.msgs) > 0 {
+		_, _ = hash.Write([]byte(job.msgs[0].StreamName + "/" + job.msgs[0].StreamKey))
+	} else {
+		_, _ = hash.Write([]byte(job.batch.BatchID))
+	}
+	return int(hash.Sum32() % uint32(len(f.lanes)))
+}
+
+// runLane executes jobs from lane sequentially, coalescing up to ForwarderConfig.MaxCoalescedBatches batches into
+// a single write.
+func (f Forwarder) runLane(lane *forwardLane) {
+	defer f.lanesWg.Done()
+	limit := f.cfg.MaxCoalescedBatches
+	if limit <= 0 {
+		limit = 1
+	}
+	drain := f.state.drain
+	for {
+		select {
+		case <-f.lanesStop:
+			f.skipLaneJobs(lane.pop(lane.len()))
+			return
+		case <-drain:
+			drain = nil // avoids busy loops as drain is a closed channel from now on.
+		case <-lane.signal:
+		}
+
+		for {
+			if f.state.isDraining() {
+				f.skipLaneJobs(lane.pop(lane.len()))
+				break
+			} else if lane.len() == 0 {
+				break
+			}
+			if limit > 1 && f.cfg.CoalesceWindow > 0 {
+				lane.await(f.state.drain, f.cfg.CoalesceWindow, limit)
+			}
+			jobs := lane.pop(limit)
+			if len(jobs) == 0 {
+				break
+			}
+			f.runLaneJobs(jobs)
+		}
+	}
+}
+
+func (f Forwarder) skipLaneJobs(jobs []forwardJob) {
+	atomic.AddInt64(&f.state.queuedJobs, -int64(len(jobs)))
+	for _, job := range jobs {
+		f.skipJob(job.batch.BatchID)
+		job.done <- nil
+	}
+}
+
+func (f Forwarder) runLaneJobs(jobs []forwardJob) {
+	totalJobs := int64(len(jobs))
+	atomic.AddInt64(&f.state.queuedJobs, -totalJobs)
+	atomic.AddInt64(&f.state.inFlightJobs, totalJobs)
+	defer atomic.AddInt64(&f.state.inFlightJobs, -totalJobs)
+
+	ctx, cancel := context.WithTimeout(f.state.jobsCtx, f.cfg.ForwardJobTimeout)
+	defer cancel()
+	err := f.retry.RunCtx(ctx, func(ctx context.Context) (errSend error) {
+		jobs, errSend = f.sendJobs(ctx, jobs)
+		return
+	})
+	if err != nil {
+		f.cfg.Logger.Print(err)
+	}
+	for _, job := range jobs {
+		f.finishLaneJob(job, err)
+	}
+}
+
+func (f Forwarder) finishLaneJob(job forwardJob, err error) {
+	if err != nil {
+		atomic.AddUint64(&f.state.failedJobs, 1)
+	}
+	job.done <- err
+}
+
+// sendJobs forwards batches from jobs through a single streams.Writer.Write call. Jobs with a final result (i.e.
+// forwarded, skipped or parked) are notified. Remaining jobs are returned along the failure, so they can be retried.
+func (f Forwarder) sendJobs(ctx context.Context, jobs []forwardJob) ([]forwardJob, error) {
+	pending := make([]forwardJob, 0, len(jobs))
+	msgs := make([]streams.Message, 0, len(jobs))
+	for _, job := range jobs {
+		if job.batch.IsParked || job.batch.IsPublished() {
+			f.cfg.Logger.Printf("skipping batch_id <%s>, batch was already parked or published", job.batch.BatchID)
+			f.finishLaneJob(job, nil)
+			continue
+		} else if job.decodeErr != nil {
+			f.finishLaneJob(job, f.recordFailure(ctx, job.batch.BatchID, streams.ErrUnrecoverableWrap{ParentErr: job.decodeErr}))
+			continue
+		}
+		pending = append(pending, job)
+		msgs = append(msgs, job.msgs...)
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	if err := f.cfg.Writer.Write(ctx, msgs); err != nil {
+		retryable := make([]forwardJob, 0, len(pending))
+		for _, job := range pending {
+			if errFailure := f.recordFailure(ctx, job.batch.BatchID, err); errors.Is(errFailure, streams.ErrUnrecoverable) {
+				f.finishLaneJob(job, errFailure)
+				continue
+			}
+			retryable = append(retryable, job)
+		}
+		if len(retryable) == 0 {
+			return nil, nil
+		}
+		return retryable, err
+	}
+
+	var errCommit error
+	uncommitted := make([]forwardJob, 0)
+	for _, job := range pending {
+		if err := f.cfg.Storage.Commit(ctx, job.batch.BatchID); err != nil {
+			errCommit = err
+			uncommitted = append(uncommitted, job)
+			continue
+		}
+		f.state.recordForward(job.batch.BatchID)
+		f.cfg.Logger.Printf("forwarded traffic from batch_id <%s>", job.batch.BatchID)
+		f.finishLaneJob(job, nil)
+	}
+	return uncommitted, errCommit
+}
+
 func (f Forwarder) sendBatch(ctx context.Context, batch Batch) (err error) {
 	if len(batch.TransportBatchRaw) == 0 {
 		batch, err = f.cfg.Storage.GetBatch(ctx, batch.BatchID)
@@ -154,14 +502,20 @@ func (f Forwarder) sendBatch(ctx context.Context, batch Batch) (err error) {
 			return err
 		}
 	}
+	if batch.IsParked || batch.IsPublished() {
+		f.cfg.Logger.Printf("skipping batch_id <%s>, batch was already parked or published", batch.BatchID)
+		return nil
+	}
 	defer func() {
 		if err != nil {
+			err = f.recordFailure(ctx, batch.BatchID, err)
 			return
 		} else if errCommit := f.cfg.Storage.Commit(ctx, batch.BatchID); errCommit != nil {
 			err = errCommit
 			return
 		}
 
+		f.state.recordForward(batch.BatchID)
 		f.cfg.Logger.Printf("forwarded traffic from batch_id <%s>", batch.BatchID)
 	}()
 
@@ -172,3 +526,17 @@ func (f Forwarder) sendBatch(ctx context.Context, batch Batch) (err error) {
 
 	return f.cfg.Writer.Write(ctx, persistence.NewMessages(transportBatch))
 }
+
+// recordFailure keeps track of failed forward attempts. Returns an unrecoverable error if batch got parked, so
+// retry mechanisms stop.
+func (f Forwarder) recordFailure(ctx context.Context, batchID string, failure error) error {
+	isParked, err := f.cfg.Storage.RecordFailure(ctx, batchID, failure)
+	if err != nil {
+		f.cfg.Logger.Printf("failed to record failure from batch_id <%s>, error %s", batchID, err.Error())
+		return failure
+	} else if isParked {
+		f.cfg.Logger.Printf("parked batch_id <%s>, last error %s", batchID, failure.Error())
+		return streams.ErrUnrecoverableWrap{ParentErr: ErrBatchParked}
+	}
+	return failure
+}
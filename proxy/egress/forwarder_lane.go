@@ -0,0 +1,129 @@
+package egress
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+)
+
+// A ForwardOrdering is the strategy used by a Forwarder to schedule forward jobs.
+type ForwardOrdering uint8
+
+const (
+	// UnorderedForwarding forwards batches as soon as their jobs are scheduled.
+	UnorderedForwarding ForwardOrdering = iota
+	// InsertTimeForwarding forwards batches sequentially, queued batches with the oldest insert time go first.
+	InsertTimeForwarding
+	// StreamKeyForwarding forwards batches sharing the same stream name and key (from their first message)
+	// sequentially, queued batches with the oldest insert time go first. Batches with different stream keys are
+	// forwarded concurrently.
+	StreamKeyForwarding
+)
+
+// A forwardJob is a batch scheduled into a forwardLane.
+type forwardJob struct {
+	batch     Batch
+	msgs      []streams.Message
+	decodeErr error
+	seq       uint64     // keeps FIFO order for batches with the same insert time.
+	done      chan error // receives the final result of the job.
+}
+
+// jobQueue is a priority queue (container/heap) of forwardJob(s) sorted by batch insert time.
+type jobQueue []forwardJob
+
+var _ heap.Interface = &jobQueue{}
+
+func (q jobQueue) Len() int {
+	return len(q)
+}
+
+func (q jobQueue) Less(i, j int) bool {
+	if q[i].batch.InsertTime.Equal(q[j].batch.InsertTime) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].batch.InsertTime.Before(q[j].batch.InsertTime)
+}
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *jobQueue) Push(x any) {
+	*q = append(*q, x.(forwardJob))
+}
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	n := len(old)
+	job := old[n-1]
+	old[n-1] = forwardJob{}
+	*q = old[:n-1]
+	return job
+}
+
+// A forwardLane is a sequential forward job queue. A Forwarder runs a single worker per lane, so jobs from the same
+// lane never run concurrently.
+type forwardLane struct {
+	mu     sync.Mutex
+	queue  jobQueue
+	seq    uint64
+	signal chan struct{}
+}
+
+func newForwardLane() *forwardLane {
+	return &forwardLane{
+		queue:  make(jobQueue, 0),
+		signal: make(chan struct{}, 1),
+	}
+}
+
+func (l *forwardLane) push(job forwardJob) {
+	l.mu.Lock()
+	l.seq++
+	job.seq = l.seq
+	heap.Push(&l.queue, job)
+	l.mu.Unlock()
+
+	select {
+	case l.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (l *forwardLane) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.queue.Len()
+}
+
+// pop removes up to limit jobs from the lane, oldest first.
+func (l *forwardLane) pop(limit int) []forwardJob {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit > l.queue.Len() {
+		limit = l.queue.Len()
+	}
+	jobs := make([]forwardJob, 0, limit)
+	for i := 0; i < limit; i++ {
+		jobs = append(jobs, heap.Pop(&l.queue).(forwardJob))
+	}
+	return jobs
+}
+
+// await blocks until the lane holds size jobs, window time duration elapses or stop is closed.
+func (l *forwardLane) await(stop <-chan struct{}, window time.Duration, size int) {
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	for l.len() < size {
+		select {
+		case <-l.signal:
+		case <-timer.C:
+			return
+		case <-stop:
+			return
+		}
+	}
+}
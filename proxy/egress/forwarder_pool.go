@@ -0,0 +1,86 @@
+package egress
+
+import (
+	"sync"
+
+	"github.com/alexandria-oss/streams"
+)
+
+// A forwardPool is a bounded forward job queue drained by a fixed count of workers. Unlike jobs scheduled through
+// the internal bus, which runs a routine per job, both queued and running jobs are bounded.
+type forwardPool struct {
+	mu       sync.RWMutex
+	isClosed bool
+	jobs     chan Batch
+	ready    chan struct{} // closed when workers are started.
+	stop     chan struct{} // closed when the pool starts closing, unblocks producers waiting for queue room.
+	wg       sync.WaitGroup
+}
+
+func newForwardPool(queueSize int) *forwardPool {
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &forwardPool{
+		jobs:  make(chan Batch, queueSize),
+		ready: make(chan struct{}),
+		stop:  make(chan struct{}),
+	}
+}
+
+// start spins up totalWorkers workers executing run for each queued job. Closed pools are not started.
+func (p *forwardPool) start(totalWorkers int, run func(Batch)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.isClosed {
+		return
+	}
+	defer close(p.ready)
+	for i := 0; i < totalWorkers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for batch := range p.jobs {
+				run(batch)
+			}
+		}()
+	}
+}
+
+// push enqueues batch, blocking the I/O until the pool is started and its queue has room for it. Returns false if
+// skip is closed before batch was enqueued.
+//
+// Returns streams.ErrBusIsShutdown if the pool is closed.
+func (p *forwardPool) push(batch Batch, skip <-chan struct{}) (bool, error) {
+	select {
+	case <-p.ready:
+	case <-p.stop:
+		return false, streams.ErrBusIsShutdown
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.isClosed {
+		return false, streams.ErrBusIsShutdown
+	}
+	select {
+	case p.jobs <- batch:
+		return true, nil
+	case <-skip:
+		return false, nil
+	case <-p.stop:
+		return false, streams.ErrBusIsShutdown
+	}
+}
+
+// close stops accepting jobs and waits for workers to execute every queued job.
+//
+// Must be called once.
+func (p *forwardPool) close() {
+	close(p.stop)
+	p.mu.Lock()
+	p.isClosed = true
+	close(p.jobs)
+	p.mu.Unlock()
+	p.wg.Wait()
+}
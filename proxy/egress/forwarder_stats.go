@@ -0,0 +1,124 @@
+package egress
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ForwarderStats is a snapshot of Forwarder runtime statistics.
+type ForwarderStats struct {
+	IsRunning        bool      // Indicates if the Forwarder is accepting forward jobs.
+	IsDraining       bool      // Indicates if the Forwarder is shutting down, skipping every job not started yet.
+	QueuedJobs       int64     // Count of forward jobs waiting for a job slot or job lane.
+	InFlightJobs     int64     // Count of forward jobs running.
+	ForwardedBatches uint64    // Total count of forwarded batches.
+	FailedJobs       uint64    // Total count of failed forward jobs.
+	SkippedJobs      uint64    // Total count of forward jobs skipped during a drain.
+	LastBatchID      string    // Identifier of the latest forwarded batch.
+	LastForwardTime  time.Time // Timestamp of the latest forwarded batch.
+}
+
+// forwarderState is the mutable state shared by Forwarder copies.
+type forwarderState struct {
+	isRunning        int32
+	queuedJobs       int64
+	inFlightJobs     int64
+	forwardedBatches uint64
+	failedJobs       uint64
+	skippedJobs      uint64
+	lastForwardTime  int64 // unix nanoseconds.
+	lastBatchID      atomic.Value
+
+	drain        chan struct{} // closed when the Forwarder starts draining.
+	drainOnce    sync.Once
+	shutdownOnce sync.Once
+	jobsCtx      context.Context // cancelled when the drain deadline is exceeded.
+	jobsCancel   context.CancelFunc
+	jobsMu       sync.Mutex
+	jobSeq       uint64
+	jobCancels   map[uint64]context.CancelFunc // job contexts not derived from jobsCtx (see newJobContext).
+}
+
+func newForwarderState() *forwarderState {
+	jobsCtx, jobsCancel := context.WithCancel(context.Background())
+	return &forwarderState{
+		drain:      make(chan struct{}),
+		jobsCtx:    jobsCtx,
+		jobsCancel: jobsCancel,
+		jobCancels: make(map[uint64]context.CancelFunc),
+	}
+}
+
+func (s *forwarderState) isDraining() bool {
+	select {
+	case <-s.drain:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *forwarderState) startDrain() {
+	s.drainOnce.Do(func() {
+		close(s.drain)
+	})
+}
+
+// newJobContext derives a job context from parent which is also cancelled if the drain deadline is exceeded
+// (see cancelJobs).
+func (s *forwarderState) newJobContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	if s.jobsCtx.Err() != nil {
+		cancel()
+		return ctx, cancel
+	}
+	s.jobSeq++
+	jobID := s.jobSeq
+	s.jobCancels[jobID] = cancel
+	return ctx, func() {
+		cancel()
+		s.jobsMu.Lock()
+		delete(s.jobCancels, jobID)
+		s.jobsMu.Unlock()
+	}
+}
+
+// cancelJobs cancels jobsCtx and every job context (newJobContext).
+func (s *forwarderState) cancelJobs() {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	s.jobsCancel()
+	for jobID, cancel := range s.jobCancels {
+		cancel()
+		delete(s.jobCancels, jobID)
+	}
+}
+
+func (s *forwarderState) recordForward(batchID string) {
+	atomic.AddUint64(&s.forwardedBatches, 1)
+	atomic.StoreInt64(&s.lastForwardTime, time.Now().UnixNano())
+	s.lastBatchID.Store(batchID)
+}
+
+func (s *forwarderState) stats() ForwarderStats {
+	stats := ForwarderStats{
+		IsRunning:        atomic.LoadInt32(&s.isRunning) == 1,
+		IsDraining:       s.isDraining(),
+		QueuedJobs:       atomic.LoadInt64(&s.queuedJobs),
+		InFlightJobs:     atomic.LoadInt64(&s.inFlightJobs),
+		ForwardedBatches: atomic.LoadUint64(&s.forwardedBatches),
+		FailedJobs:       atomic.LoadUint64(&s.failedJobs),
+		SkippedJobs:      atomic.LoadUint64(&s.skippedJobs),
+	}
+	if batchID, ok := s.lastBatchID.Load().(string); ok {
+		stats.LastBatchID = batchID
+	}
+	if lastForward := atomic.LoadInt64(&s.lastForwardTime); lastForward > 0 {
+		stats.LastForwardTime = time.Unix(0, lastForward)
+	}
+	return stats
+}
@@ -1,6 +1,11 @@
 package egress_test
 
 import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -103,3 +108,422 @@ func TestEgress(t *testing.T) {
 		})
 	}
 }
+
+type failureStorageSpy struct {
+	egress.NoopStorage
+	failures chan error
+}
+
+func (s failureStorageSpy) RecordFailure(_ context.Context, _ string, failure error) (bool, error) {
+	s.failures <- failure
+	return s.WantRecordFailure, s.WantRecordFailureErr
+}
+
+func TestForwarder_RecordFailure(t *testing.T) {
+	batchProto := persistence.NewTransportMessageBatch([]streams.Message{
+		{
+			ID:          "abc",
+			StreamName:  "foo",
+			ContentType: "application/text",
+			Data:        []byte("the quick brown fox"),
+		},
+	})
+	batchBytes, errMarshal := proto.Marshal(batchProto)
+	require.NoError(t, errMarshal)
+
+	tests := []struct {
+		name         string
+		inBatch      egress.Batch
+		inParked     bool
+		inWriterErr  error
+		wantFailures int
+	}{
+		{
+			name: "parked batch",
+			inBatch: egress.Batch{
+				BatchID:           "123",
+				TransportBatchRaw: batchBytes,
+			},
+			inParked:     true,
+			inWriterErr:  errors.New("generic error"),
+			wantFailures: 1,
+		},
+		{
+			name: "retried batch",
+			inBatch: egress.Batch{
+				BatchID:           "123",
+				TransportBatchRaw: batchBytes,
+			},
+			inParked:     false,
+			inWriterErr:  errors.New("generic error"),
+			wantFailures: 3,
+		},
+		{
+			name: "poisoned batch",
+			inBatch: egress.Batch{
+				BatchID:           "123",
+				TransportBatchRaw: []byte("invalid protobuf"),
+			},
+			inParked:     false,
+			wantFailures: 1,
+		},
+		{
+			name: "skip published batch",
+			inBatch: egress.Batch{
+				BatchID:           "123",
+				TransportBatchRaw: batchBytes,
+				PublishTime:       time.Now(),
+			},
+			inWriterErr:  errors.New("generic error"),
+			wantFailures: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := failureStorageSpy{
+				NoopStorage: egress.NoopStorage{
+					WantRecordFailure: tt.inParked,
+				},
+				failures: make(chan error, 10),
+			}
+			cfg := egress.NewForwarderDefaultConfig()
+			cfg.Storage = spy
+			cfg.Writer = streams.NoopWriter{WantWriterErr: tt.inWriterErr}
+			cfg.ForwardJobTotalRetries = 2
+			cfg.ForwardJobRetryBackoff = time.Nanosecond
+			cfg.ForwardJobRetryBackoffMax = time.Nanosecond
+			fwd := egress.NewForwarder(cfg)
+			go fwd.Start()
+
+			require.NoError(t, fwd.ForwardBatch(tt.inBatch))
+			fwd.Shutdown()
+			assert.Len(t, spy.failures, tt.wantFailures)
+		})
+	}
+}
+
+type writerSpy struct {
+	mu          sync.Mutex
+	writes      [][]streams.Message
+	inFlight    int32
+	maxInFlight int32
+	delay       time.Duration
+	err         error
+}
+
+func (w *writerSpy) Write(_ context.Context, msgs []streams.Message) error {
+	current := atomic.AddInt32(&w.inFlight, 1)
+	defer atomic.AddInt32(&w.inFlight, -1)
+	w.mu.Lock()
+	if current > w.maxInFlight {
+		w.maxInFlight = current
+	}
+	w.writes = append(w.writes, msgs)
+	w.mu.Unlock()
+	time.Sleep(w.delay)
+	return w.err
+}
+
+func newTestBatch(t *testing.T, batchID, streamKey string, insertTime time.Time) egress.Batch {
+	batchProto := persistence.NewTransportMessageBatch([]streams.Message{
+		{
+			ID:          batchID,
+			StreamName:  "foo",
+			StreamKey:   streamKey,
+			ContentType: "application/text",
+			Data:        []byte("the quick brown fox"),
+		},
+	})
+	batchBytes, err := proto.Marshal(batchProto)
+	require.NoError(t, err)
+	return egress.Batch{
+		BatchID:           batchID,
+		TransportBatchRaw: batchBytes,
+		InsertTime:        insertTime,
+	}
+}
+
+func TestForwarder_MaxConcurrentJobs(t *testing.T) {
+	tests := []struct {
+		name          string
+		inMaxJobs     int
+		inOrdering    egress.ForwardOrdering
+		wantMaxFlight int32
+	}{
+		{
+			name:          "unordered",
+			inMaxJobs:     2,
+			inOrdering:    egress.UnorderedForwarding,
+			wantMaxFlight: 2,
+		},
+		{
+			name:          "insert time",
+			inMaxJobs:     4,
+			inOrdering:    egress.InsertTimeForwarding,
+			wantMaxFlight: 1,
+		},
+		{
+			name:          "stream key",
+			inMaxJobs:     2,
+			inOrdering:    egress.StreamKeyForwarding,
+			wantMaxFlight: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &writerSpy{delay: time.Millisecond * 5}
+			cfg := egress.NewForwarderDefaultConfig()
+			cfg.Storage = egress.NoopStorage{}
+			cfg.Writer = writer
+			cfg.MaxConcurrentJobs = tt.inMaxJobs
+			cfg.Ordering = tt.inOrdering
+			fwd := egress.NewForwarder(cfg)
+			go fwd.Start()
+
+			now := time.Now()
+			for i := 0; i < 10; i++ {
+				batch := newTestBatch(t, strconv.Itoa(i), strconv.Itoa(i%4), now.Add(time.Duration(i)))
+				require.NoError(t, fwd.ForwardBatch(batch))
+			}
+			fwd.Shutdown()
+			assert.Len(t, writer.writes, 10)
+			assert.LessOrEqual(t, writer.maxInFlight, tt.wantMaxFlight)
+		})
+	}
+}
+
+// gatedWriter blocks writes until release is closed.
+type gatedWriter struct {
+	release chan struct{}
+	writes  int32
+}
+
+func (w *gatedWriter) Write(ctx context.Context, _ []streams.Message) error {
+	select {
+	case <-w.release:
+		atomic.AddInt32(&w.writes, 1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestForwarder_MaxQueuedJobs(t *testing.T) {
+	writer := &gatedWriter{release: make(chan struct{})}
+	cfg := egress.NewForwarderDefaultConfig()
+	cfg.Storage = egress.NoopStorage{}
+	cfg.Writer = writer
+	cfg.MaxConcurrentJobs = 2
+	cfg.MaxQueuedJobs = 2
+	fwd := egress.NewForwarder(cfg)
+	go fwd.Start()
+
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, fwd.ForwardBatch(newTestBatch(t, strconv.Itoa(i), "", now)))
+	}
+	require.Eventually(t, func() bool {
+		stats := fwd.Stats()
+		return stats.InFlightJobs == 2 && stats.QueuedJobs == 2
+	}, time.Second, time.Millisecond)
+
+	// queue is full, intake must block until a worker is released
+	errPush := make(chan error, 1)
+	go func() {
+		errPush <- fwd.ForwardBatch(newTestBatch(t, "4", "", now))
+	}()
+	select {
+	case <-errPush:
+		t.Fatal("expected forward to block while job queue is full")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(writer.release)
+	select {
+	case err := <-errPush:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("expected forward to be unblocked")
+	}
+	fwd.Shutdown()
+	assert.EqualValues(t, 5, atomic.LoadInt32(&writer.writes))
+	assert.Equal(t, uint64(5), fwd.Stats().ForwardedBatches)
+	assert.ErrorIs(t, fwd.ForwardBatch(newTestBatch(t, "5", "", now)), streams.ErrBusIsShutdown)
+}
+
+func TestForwarder_Coalescing(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		inBatches    []egress.Batch
+		inWriterErr  error
+		wantMsgIDs   []string
+		wantFailures int
+	}{
+		{
+			name: "ordered by insert time",
+			inBatches: []egress.Batch{
+				newTestBatch(t, "c", "", now.Add(time.Second*2)),
+				newTestBatch(t, "a", "", now),
+				newTestBatch(t, "b", "", now.Add(time.Second)),
+			},
+			wantMsgIDs: []string{"a", "b", "c"},
+		},
+		{
+			name: "skip poisoned batch",
+			inBatches: []egress.Batch{
+				newTestBatch(t, "b", "", now.Add(time.Second)),
+				{BatchID: "poison", TransportBatchRaw: []byte("invalid protobuf"), InsertTime: now},
+				newTestBatch(t, "a", "", now),
+			},
+			wantMsgIDs:   []string{"a", "b"},
+			wantFailures: 1,
+		},
+		{
+			name: "write failure",
+			inBatches: []egress.Batch{
+				newTestBatch(t, "a", "", now),
+				newTestBatch(t, "b", "", now.Add(time.Second)),
+				newTestBatch(t, "c", "", now.Add(time.Second*2)),
+			},
+			inWriterErr:  streams.ErrUnrecoverableWrap{ParentErr: errors.New("generic error")},
+			wantMsgIDs:   []string{"a", "b", "c"},
+			wantFailures: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := failureStorageSpy{
+				failures: make(chan error, 10),
+			}
+			writer := &writerSpy{err: tt.inWriterErr}
+			cfg := egress.NewForwarderDefaultConfig()
+			cfg.Storage = spy
+			cfg.Writer = writer
+			cfg.Ordering = egress.InsertTimeForwarding
+			cfg.MaxCoalescedBatches = len(tt.inBatches)
+			cfg.CoalesceWindow = time.Second * 5
+			fwd := egress.NewForwarder(cfg)
+			go fwd.Start()
+
+			for _, batch := range tt.inBatches {
+				require.NoError(t, fwd.ForwardBatch(batch))
+			}
+			fwd.Shutdown()
+			require.Len(t, writer.writes, 1)
+			msgIDs := make([]string, 0, len(writer.writes[0]))
+			for _, msg := range writer.writes[0] {
+				msgIDs = append(msgIDs, msg.ID)
+			}
+			assert.Equal(t, tt.wantMsgIDs, msgIDs)
+			assert.Len(t, spy.failures, tt.wantFailures)
+		})
+	}
+}
+
+func TestForwarder_GetBatchFailureWithLanes(t *testing.T) {
+	cfg := egress.NewForwarderDefaultConfig()
+	cfg.Storage = egress.NoopStorage{
+		WantGetBatchErr: streams.ErrUnrecoverableWrap{ParentErr: errors.New("generic error")},
+	}
+	cfg.Writer = streams.NoopWriter{}
+	cfg.Ordering = egress.InsertTimeForwarding
+	cfg.ForwardJobTotalRetries = 0
+	fwd := egress.NewForwarder(cfg)
+	go fwd.Start()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, fwd.Forward(strconv.Itoa(i)))
+	}
+	fwd.Shutdown()
+	stats := fwd.Stats()
+	assert.Zero(t, stats.QueuedJobs)
+	assert.Zero(t, stats.InFlightJobs)
+	assert.Zero(t, stats.ForwardedBatches)
+}
+
+type blockingWriter struct{}
+
+func (w blockingWriter) Write(ctx context.Context, _ []streams.Message) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestForwarder_Drain(t *testing.T) {
+	tests := []struct {
+		name          string
+		inOrdering    egress.ForwardOrdering
+		inWriter      streams.Writer
+		inTotal       int
+		wantErr       error
+		wantInFlight  int64
+		wantForwarded uint64
+		wantFailed    uint64
+		wantSkipped   uint64
+	}{
+		{
+			name:          "drained",
+			inOrdering:    egress.UnorderedForwarding,
+			inWriter:      streams.NoopWriter{},
+			inTotal:       2,
+			wantForwarded: 2,
+		},
+		{
+			name:         "deadline exceeded",
+			inOrdering:   egress.UnorderedForwarding,
+			inWriter:     blockingWriter{},
+			inTotal:      3,
+			wantErr:      context.DeadlineExceeded,
+			wantInFlight: 1,
+			wantFailed:   1,
+			wantSkipped:  2,
+		},
+		{
+			name:         "deadline exceeded with job lanes",
+			inOrdering:   egress.InsertTimeForwarding,
+			inWriter:     blockingWriter{},
+			inTotal:      3,
+			wantErr:      context.DeadlineExceeded,
+			wantInFlight: 1,
+			wantFailed:   1,
+			wantSkipped:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := egress.NewForwarderDefaultConfig()
+			cfg.Storage = egress.NoopStorage{}
+			cfg.Writer = tt.inWriter
+			cfg.MaxConcurrentJobs = 1
+			cfg.Ordering = tt.inOrdering
+			cfg.ForwardJobTotalRetries = 0
+			fwd := egress.NewForwarder(cfg)
+			go fwd.Start()
+
+			now := time.Now()
+			for i := 0; i < tt.inTotal; i++ {
+				require.NoError(t, fwd.ForwardBatch(newTestBatch(t, strconv.Itoa(i), "", now.Add(time.Duration(i)))))
+			}
+			require.Eventually(t, func() bool {
+				stats := fwd.Stats()
+				return stats.InFlightJobs == tt.wantInFlight && stats.ForwardedBatches == tt.wantForwarded
+			}, time.Second, time.Millisecond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+			defer cancel()
+			assert.ErrorIs(t, fwd.Drain(ctx), tt.wantErr)
+			stats := fwd.Stats()
+			assert.False(t, stats.IsRunning)
+			assert.True(t, stats.IsDraining)
+			assert.Zero(t, stats.QueuedJobs)
+			assert.Zero(t, stats.InFlightJobs)
+			assert.Equal(t, tt.wantForwarded, stats.ForwardedBatches)
+			assert.Equal(t, tt.wantFailed, stats.FailedJobs)
+			assert.Equal(t, tt.wantSkipped, stats.SkippedJobs)
+		})
+	}
+}
@@ -1,7 +1,14 @@
 package egress
 
 import (
+	"errors"
+	"log"
 	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alexandria-oss/streams"
 )
 
 // A Notifier is an egress proxy component used by a system to notify the egress proxy agent
@@ -23,15 +30,180 @@ func (n EmbeddedNotifier) NotifyAgent(batchID string) error {
 	return n.Forwarder.Forward(batchID)
 }
 
+const egressNetworkNotifierName = "streams.proxy.egress.notifier"
+
+// A NetworkNotifierConfig is the configuration used by a NetworkNotifier.
+type NetworkNotifierConfig struct {
+	Network           string        // Network type of the agent socket (e.g. tcp, unix). Default is tcp.
+	Address           string        // Address of the agent socket.
+	DialTimeout       time.Duration // Maximum time duration to wait a connection to be established.
+	AckTimeout        time.Duration // Maximum time duration to wait the agent to acknowledge a notification.
+	ReconnectAttempts int           // Maximum count a notification will be retried after a connection failure.
+	ReconnectBackoff  time.Duration // Time duration between each reconnection process.
+	Logger            *log.Logger   // Logger to write information to.
+}
+
+func NewNetworkNotifierDefaultConfig() NetworkNotifierConfig {
+	return NetworkNotifierConfig{
+		Network:           "tcp",
+		Address:           "",
+		DialTimeout:       time.Second * 5,
+		AckTimeout:        time.Second * 5,
+		ReconnectAttempts: 3,
+		ReconnectBackoff:  time.Millisecond * 250,
+		Logger:            nil,
+	}
+}
+
+// NetworkNotifier a Notifier implementation used by a system to notify an egress proxy agent through
+// OSI Level 4 sockets (e.g. TCP, UNIX) using the egress notification protocol (see WriteNotificationFrame).
+//
+// The underlying connection is re-established automatically if a transport failure is detected.
+//
+// Zero-value IS NOT ready to use, please call NewNetworkNotifier routine instead.
 type NetworkNotifier struct {
+	cfg  NetworkNotifierConfig
+	mu   sync.Mutex
 	conn net.Conn
 }
 
-var _ Notifier = NetworkNotifier{}
+var _ Notifier = &NetworkNotifier{}
+
+// NewNetworkNotifier allocates a NetworkNotifier instance and dials the egress proxy agent.
+func NewNetworkNotifier(cfg NetworkNotifierConfig) (*NetworkNotifier, error) {
+	defCfg := NewNetworkNotifierDefaultConfig()
+	if cfg.Network == "" {
+		cfg.Network = defCfg.Network
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defCfg.DialTimeout
+	}
+	if cfg.AckTimeout <= 0 {
+		cfg.AckTimeout = defCfg.AckTimeout
+	}
+	if cfg.ReconnectAttempts < 0 {
+		cfg.ReconnectAttempts = 0
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(os.Stdout, egressNetworkNotifierName+": ", 0)
+	}
+
+	n := &NetworkNotifier{
+		cfg: cfg,
+	}
+	conn, err := n.dial()
+	if err != nil {
+		return nil, err
+	}
+	n.conn = conn
+	return n, nil
+}
+
+func (n *NetworkNotifier) dial() (net.Conn, error) {
+	return net.DialTimeout(n.cfg.Network, n.cfg.Address, n.cfg.DialTimeout)
+}
 
-func (n NetworkNotifier) NotifyAgent(batchID string) error {
-	if _, err := n.conn.Write([]byte(batchID)); err != nil {
+// connect dials the agent if no connection is established. Dialing is performed outside the lock so concurrent
+// notifications are not blocked by a downed agent.
+func (n *NetworkNotifier) connect() error {
+	n.mu.Lock()
+	isConnected := n.conn != nil
+	n.mu.Unlock()
+	if isConnected {
+		return nil
+	}
+
+	conn, err := n.dial()
+	if err != nil {
 		return err
 	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn != nil {
+		// another notification re-established the connection first
+		return conn.Close()
+	}
+	n.conn = conn
 	return nil
 }
+
+// closeConn closes the underlying connection. Must be called with the lock held.
+func (n *NetworkNotifier) closeConn() {
+	if n.conn == nil {
+		return
+	}
+	if err := n.conn.Close(); err != nil {
+		n.cfg.Logger.Printf("failed to close connection, error %s", err.Error())
+	}
+	n.conn = nil
+}
+
+// NotifyAgent triggers egress proxy agent to forward traffic buffer (batch) into a stream. This routine blocks
+// the I/O until the agent acknowledges the notification.
+//
+// Notifications share a single connection, hence the lock is held only to write the notification and read its
+// acknowledgement; reconnection backoffs and dials are performed outside of it.
+//
+// Returns ErrNotificationRejected if the agent rejected the notification.
+func (n *NetworkNotifier) NotifyAgent(batchID string) (err error) {
+	if len(batchID) == 0 {
+		return streams.ErrEmptyMessage
+	}
+
+	for attempt := 0; attempt <= n.cfg.ReconnectAttempts; attempt++ {
+		if attempt > 0 {
+			n.cfg.Logger.Printf("reconnecting to agent <%s>, attempt <%d>", n.cfg.Address, attempt)
+			time.Sleep(n.cfg.ReconnectBackoff)
+		}
+		if err = n.connect(); err != nil {
+			continue
+		}
+
+		if err = n.notify(batchID); err == nil || errors.Is(err, ErrNotificationRejected) {
+			return
+		}
+	}
+	return
+}
+
+func (n *NetworkNotifier) notify(batchID string) (err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	conn := n.conn
+	if conn == nil {
+		// connection was closed by another notification while waiting for the lock
+		return net.ErrClosed
+	}
+	defer func() {
+		if err != nil && !errors.Is(err, ErrNotificationRejected) {
+			n.closeConn()
+		}
+	}()
+
+	if err = conn.SetDeadline(time.Now().Add(n.cfg.AckTimeout)); err != nil {
+		return
+	}
+	if err = WriteNotificationFrame(conn, []byte(batchID)); err != nil {
+		return
+	}
+
+	ack, err := ReadNotificationFrame(conn, DefaultNotificationMaxFrameSize)
+	if err != nil {
+		return
+	} else if len(ack) > 0 {
+		return newNotificationRejectedError(ack)
+	}
+	return nil
+}
+
+// Close closes the underlying connection.
+func (n *NetworkNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn = nil
+	return err
+}
@@ -0,0 +1,63 @@
+package egress
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The egress notification protocol is a minimal length-prefixed protocol used by systems (agent-less) to notify
+// an egress proxy agent through OSI Level 4 sockets (e.g. TCP, UNIX).
+//
+// Each frame is composed by a 4-byte big-endian unsigned integer (length of the payload) followed by the payload itself.
+//
+// A system sends a frame with a batch identifier as payload. Then, the agent replies with a frame acknowledging
+// the notification: an empty payload means the notification was accepted whereas a non-empty payload contains
+// the error message of a rejected notification.
+const (
+	notificationFrameHeaderSize = 4
+	// DefaultNotificationMaxFrameSize default maximum size (in bytes) of a notification frame payload.
+	DefaultNotificationMaxFrameSize = 1024
+)
+
+var (
+	ErrNotificationFrameTooLarge = errors.New("streams.egress: notification frame exceeds maximum size")
+	ErrNotificationRejected      = errors.New("streams.egress: notification rejected by agent")
+)
+
+// WriteNotificationFrame writes payload into w using the egress notification protocol framing.
+func WriteNotificationFrame(w io.Writer, payload []byte) error {
+	buf := make([]byte, notificationFrameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(buf, uint32(len(payload)))
+	copy(buf[notificationFrameHeaderSize:], payload)
+	_, err := w.Write(buf)
+	return err
+}
+
+// ReadNotificationFrame reads a frame payload from r using the egress notification protocol framing.
+// Returns ErrNotificationFrameTooLarge if payload length is greater than maxSize.
+func ReadNotificationFrame(r io.Reader, maxSize uint32) ([]byte, error) {
+	header := make([]byte, notificationFrameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > maxSize {
+		return nil, ErrNotificationFrameTooLarge
+	} else if size == 0 {
+		return nil, nil
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// newNotificationRejectedError allocates an error from a negative acknowledgement payload.
+func newNotificationRejectedError(payload []byte) error {
+	return fmt.Errorf("%w: %s", ErrNotificationRejected, string(payload))
+}
@@ -0,0 +1,188 @@
+package egress_test
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationFrame(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, egress.WriteNotificationFrame(buf, []byte("123")))
+	assert.Equal(t, []byte{0, 0, 0, 3, '1', '2', '3'}, buf.Bytes())
+
+	payload, err := egress.ReadNotificationFrame(buf, egress.DefaultNotificationMaxFrameSize)
+	require.NoError(t, err)
+	assert.Equal(t, "123", string(payload))
+
+	require.NoError(t, egress.WriteNotificationFrame(buf, []byte("123")))
+	_, err = egress.ReadNotificationFrame(buf, 2)
+	assert.ErrorIs(t, err, egress.ErrNotificationFrameTooLarge)
+}
+
+// fakeAgent is a minimal egress proxy agent acknowledging notifications. Connections are dropped after
+// dropAfter notifications if dropAfter > 0.
+type fakeAgent struct {
+	listener  net.Listener
+	dropAfter int
+
+	mu       sync.Mutex
+	received []string
+}
+
+func newFakeAgent(t *testing.T, dropAfter int) *fakeAgent {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	a := &fakeAgent{listener: l, dropAfter: dropAfter}
+	go a.serve()
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+	return a
+}
+
+func (a *fakeAgent) serve() {
+	for {
+		conn, err := a.listener.Accept()
+		if err != nil {
+			return
+		}
+		go a.serveConn(conn)
+	}
+}
+
+func (a *fakeAgent) serveConn(conn net.Conn) {
+	defer conn.Close()
+	for total := 1; ; total++ {
+		payload, err := egress.ReadNotificationFrame(conn, egress.DefaultNotificationMaxFrameSize)
+		if err != nil {
+			return
+		}
+		if a.dropAfter > 0 && total > a.dropAfter {
+			return
+		}
+
+		a.mu.Lock()
+		a.received = append(a.received, string(payload))
+		a.mu.Unlock()
+		var ack []byte
+		if string(payload) == "reject" {
+			ack = []byte("batch not found")
+		}
+		if err = egress.WriteNotificationFrame(conn, ack); err != nil {
+			return
+		}
+	}
+}
+
+func (a *fakeAgent) getReceived() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.received
+}
+
+func TestNetworkNotifier(t *testing.T) {
+	_, err := egress.NewNetworkNotifier(egress.NetworkNotifierConfig{
+		Address:     "127.0.0.1:1",
+		DialTimeout: time.Millisecond * 100,
+	})
+	assert.Error(t, err)
+
+	tests := []struct {
+		name         string
+		inDropAfter  int
+		inBatchIDs   []string
+		wantErr      error
+		wantReceived []string
+	}{
+		{
+			name:         "happy path",
+			inBatchIDs:   []string{"123", "456"},
+			wantReceived: []string{"123", "456"},
+		},
+		{
+			name:         "empty batch",
+			inBatchIDs:   []string{""},
+			wantErr:      streams.ErrEmptyMessage,
+			wantReceived: nil,
+		},
+		{
+			name:         "rejected",
+			inBatchIDs:   []string{"reject"},
+			wantErr:      egress.ErrNotificationRejected,
+			wantReceived: []string{"reject"},
+		},
+		{
+			name:         "reconnect",
+			inDropAfter:  1,
+			inBatchIDs:   []string{"123", "456", "789"},
+			wantReceived: []string{"123", "456", "789"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newFakeAgent(t, tt.inDropAfter)
+			notifier, err := egress.NewNetworkNotifier(egress.NetworkNotifierConfig{
+				Address:           agent.listener.Addr().String(),
+				ReconnectAttempts: 2,
+				ReconnectBackoff:  time.Millisecond,
+			})
+			require.NoError(t, err)
+			defer notifier.Close()
+
+			for _, batchID := range tt.inBatchIDs {
+				err = notifier.NotifyAgent(batchID)
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantReceived, agent.getReceived())
+		})
+	}
+}
+
+func TestNetworkNotifier_ConcurrentAgentDown(t *testing.T) {
+	agent := newFakeAgent(t, 1)
+	const (
+		totalCallers      = 8
+		reconnectAttempts = 3
+		reconnectBackoff  = time.Millisecond * 50
+	)
+	notifier, err := egress.NewNetworkNotifier(egress.NetworkNotifierConfig{
+		Address:           agent.listener.Addr().String(),
+		DialTimeout:       time.Millisecond * 100,
+		ReconnectAttempts: reconnectAttempts,
+		ReconnectBackoff:  reconnectBackoff,
+	})
+	require.NoError(t, err)
+	defer notifier.Close()
+	require.NoError(t, notifier.NotifyAgent("123"))
+	require.NoError(t, agent.listener.Close()) // agent drops the connection on the next notification
+
+	errs := make(chan error, totalCallers)
+	wg := sync.WaitGroup{}
+	wg.Add(totalCallers)
+	startTime := time.Now()
+	for i := 0; i < totalCallers; i++ {
+		go func() {
+			defer wg.Done()
+			errs <- notifier.NotifyAgent("456")
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(startTime)
+	close(errs)
+
+	for err = range errs {
+		assert.Error(t, err)
+	}
+	// reconnection backoffs must not be serialized between callers
+	assert.Less(t, elapsed, reconnectBackoff*reconnectAttempts*totalCallers/2)
+	assert.Equal(t, []string{"123"}, agent.getReceived())
+}
@@ -0,0 +1,158 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+)
+
+const egressResyncerName = "streams.proxy.egress.resyncer"
+
+// A ResyncerConfig is the configuration used by a Resyncer.
+type ResyncerConfig struct {
+	Storage   Storage     // A storage a Resyncer instance lists pending batches from.
+	Forwarder Forwarder   // A running forwarder a Resyncer instance schedules pending batches into.
+	Logger    *log.Logger // Logger to write information to.
+	// Batches inserted within this time duration are skipped as they might be forwarded by a listener already.
+	MinAge           time.Duration
+	PageSize         int           // Maximum count of batches listed per Storage.ListPending call.
+	RateLimit        int           // Maximum count of batches scheduled per second. Unlimited if <= 0.
+	ListTimeout      time.Duration // Maximum time duration to wait a Storage.ListPending call to finish.
+	ProgressInterval time.Duration // Time duration between each progress report.
+	// Function called on every progress report (optional). Progress is written to Logger regardless.
+	OnProgress func(progress ResyncProgress)
+}
+
+func NewResyncerDefaultConfig() ResyncerConfig {
+	return ResyncerConfig{
+		Storage:          nil,
+		Logger:           nil,
+		MinAge:           time.Minute * 2,
+		PageSize:         100,
+		RateLimit:        0,
+		ListTimeout:      time.Second * 30,
+		ProgressInterval: time.Second * 10,
+	}
+}
+
+// ResyncProgress is a snapshot of a resync process progress.
+type ResyncProgress struct {
+	StartTime        time.Time // Timestamp of the resync process start.
+	ListedBatches    uint64    // Total count of pending batches listed from the Storage.
+	ScheduledBatches uint64    // Total count of batches scheduled into the Forwarder.
+	FailedBatches    uint64    // Total count of batches the Forwarder failed to schedule.
+	Cursor           string    // Storage cursor of the next page, empty if there are no batches left.
+	IsDone           bool      // Indicates if every pending batch was listed.
+}
+
+// A Resyncer is an internal component used by an egress proxy agent to re-forward every pending batch (i.e.
+// neither forwarded nor parked) from a Storage. Resyncer recovers batches missed by listeners (e.g. inserted
+// while a WAL replication slot was lost or skipped by a Forwarder drain).
+//
+// Batches are scheduled through Forwarder.ForwardBatch, thus a batch might be forwarded more than once if a
+// listener schedules it concurrently. Set ResyncerConfig.MinAge accordingly.
+type Resyncer struct {
+	cfg ResyncerConfig
+}
+
+// NewResyncer allocates a Resyncer instance.
+func NewResyncer(cfg ResyncerConfig) Resyncer {
+	defCfg := NewResyncerDefaultConfig()
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(os.Stdout, egressResyncerName+": ", 0)
+	}
+	if cfg.PageSize <= 0 {
+		cfg.PageSize = defCfg.PageSize
+	}
+	if cfg.ListTimeout <= 0 {
+		cfg.ListTimeout = defCfg.ListTimeout
+	}
+	if cfg.ProgressInterval <= 0 {
+		cfg.ProgressInterval = defCfg.ProgressInterval
+	}
+	return Resyncer{
+		cfg: cfg,
+	}
+}
+
+// Run schedules every pending batch older than ResyncerConfig.MinAge into the Forwarder, blocking the I/O until
+// every batch was listed or ctx is done.
+//
+// Batches the Forwarder fails to schedule are counted as failed and skipped. Run stops if the Forwarder was shut down.
+func (r Resyncer) Run(ctx context.Context) (ResyncProgress, error) {
+	progress := ResyncProgress{
+		StartTime: time.Now(),
+	}
+	threshold := progress.StartTime.UTC().Add(-r.cfg.MinAge)
+	var limiter <-chan time.Time
+	if r.cfg.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(r.cfg.RateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	r.cfg.Logger.Printf("starting resync of batches inserted before <%s>", threshold.Format(time.RFC3339))
+	lastReport := time.Now()
+	for {
+		listCtx, cancel := context.WithTimeout(ctx, r.cfg.ListTimeout)
+		batches, cursor, err := r.cfg.Storage.ListPending(listCtx, threshold, progress.Cursor, r.cfg.PageSize)
+		cancel()
+		if err != nil {
+			return progress, err
+		}
+		progress.ListedBatches += uint64(len(batches))
+
+		for _, batch := range batches {
+			if err = r.wait(ctx, limiter); err != nil {
+				return progress, err
+			}
+			if err = r.cfg.Forwarder.ForwardBatch(batch); errors.Is(err, streams.ErrBusIsShutdown) {
+				return progress, err
+			} else if err != nil {
+				progress.FailedBatches++
+				r.cfg.Logger.Printf("failed to schedule batch_id <%s>, error %s", batch.BatchID, err.Error())
+				continue
+			}
+			progress.ScheduledBatches++
+
+			if time.Since(lastReport) >= r.cfg.ProgressInterval {
+				r.report(progress)
+				lastReport = time.Now()
+			}
+		}
+
+		progress.Cursor = cursor
+		if cursor == "" {
+			break
+		}
+	}
+	progress.IsDone = true
+	r.report(progress)
+	return progress, nil
+}
+
+// wait blocks the I/O until limiter ticks (if rate limiting is enabled) or ctx is done.
+func (r Resyncer) wait(ctx context.Context, limiter <-chan time.Time) error {
+	if limiter == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-limiter:
+		return nil
+	}
+}
+
+func (r Resyncer) report(progress ResyncProgress) {
+	r.cfg.Logger.Printf("resync progress: listed <%d>, scheduled <%d>, failed <%d> batches in %s",
+		progress.ListedBatches, progress.ScheduledBatches, progress.FailedBatches,
+		time.Since(progress.StartTime).Round(time.Millisecond))
+	if r.cfg.OnProgress != nil {
+		r.cfg.OnProgress(progress)
+	}
+}
@@ -0,0 +1,127 @@
+package egress_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resyncPage struct {
+	batches    []egress.Batch
+	nextCursor string
+}
+
+type pendingStorageSpy struct {
+	egress.NoopStorage
+	pages map[string]resyncPage
+}
+
+func (s pendingStorageSpy) ListPending(_ context.Context, _ time.Time, cursor string, _ int) ([]egress.Batch, string, error) {
+	if s.WantListPendingErr != nil {
+		return nil, "", s.WantListPendingErr
+	}
+	page := s.pages[cursor]
+	return page.batches, page.nextCursor, nil
+}
+
+func TestResyncer(t *testing.T) {
+	now := time.Now()
+	pages := map[string]resyncPage{
+		"": {
+			batches: []egress.Batch{
+				newTestBatch(t, "1", "", now),
+				newTestBatch(t, "2", "", now),
+			},
+			nextCursor: "2",
+		},
+		"2": {
+			batches: []egress.Batch{
+				newTestBatch(t, "3", "", now),
+			},
+		},
+	}
+	tests := []struct {
+		name            string
+		inListErr       error
+		inRateLimit     int
+		inShutdown      bool
+		wantErr         error
+		wantListed      uint64
+		wantScheduled   uint64
+		wantMinDuration time.Duration
+		wantDone        bool
+	}{
+		{
+			name:          "happy path",
+			wantListed:    3,
+			wantScheduled: 3,
+			wantDone:      true,
+		},
+		{
+			name:            "rate limited",
+			inRateLimit:     50,
+			wantListed:      3,
+			wantScheduled:   3,
+			wantMinDuration: time.Millisecond * 60,
+			wantDone:        true,
+		},
+		{
+			name:      "list failure",
+			inListErr: errors.New("generic error"),
+			wantErr:   errors.New("generic error"),
+		},
+		{
+			name:       "forwarder shut down",
+			inShutdown: true,
+			wantErr:    streams.ErrBusIsShutdown,
+			wantListed: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := pendingStorageSpy{
+				NoopStorage: egress.NoopStorage{
+					WantListPendingErr: tt.inListErr,
+				},
+				pages: pages,
+			}
+			fwdCfg := egress.NewForwarderDefaultConfig()
+			fwdCfg.Storage = storage
+			fwdCfg.Writer = streams.NoopWriter{}
+			fwd := egress.NewForwarder(fwdCfg)
+			go fwd.Start()
+			defer fwd.Shutdown()
+			if tt.inShutdown {
+				require.Eventually(t, func() bool {
+					return fwd.Stats().IsRunning
+				}, time.Second, time.Millisecond)
+				fwd.Shutdown()
+			}
+
+			reports := 0
+			cfg := egress.NewResyncerDefaultConfig()
+			cfg.Storage = storage
+			cfg.Forwarder = fwd
+			cfg.RateLimit = tt.inRateLimit
+			cfg.OnProgress = func(_ egress.ResyncProgress) {
+				reports++
+			}
+			progress, err := egress.NewResyncer(cfg).Run(context.Background())
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantListed, progress.ListedBatches)
+			assert.Equal(t, tt.wantScheduled, progress.ScheduledBatches)
+			assert.Equal(t, tt.wantDone, progress.IsDone)
+			assert.GreaterOrEqual(t, time.Since(progress.StartTime), tt.wantMinDuration)
+			if tt.wantDone {
+				assert.Equal(t, 1, reports)
+			}
+		})
+	}
+}
@@ -11,6 +11,15 @@ type Batch struct {
 	BatchID           string
 	TransportBatchRaw []byte
 	InsertTime        time.Time
+	WriteRetries      int       // Total count of failed forward attempts.
+	LastWriteError    string    // Error from the latest failed forward attempt.
+	IsParked          bool      // Indicates if the batch was parked (i.e. it will not be forwarded anymore).
+	PublishTime       time.Time // Timestamp of a successful forward process. Only available on retention mode.
+}
+
+// IsPublished indicates if the batch was already forwarded. Only available on retention mode.
+func (b Batch) IsPublished() bool {
+	return !b.PublishTime.IsZero()
 }
 
 // A Storage is a special kind of storage where traffic is ingested (queued) so an egress proxy agent (or similar artifacts)
@@ -18,19 +27,45 @@ type Batch struct {
 type Storage interface {
 	// GetBatch retrieves specified batch.
 	GetBatch(ctx context.Context, batchID string) (Batch, error)
-	// Commit Evicts specified batch.
+	// Commit Evicts specified batch. If retention mode is enabled (StorageConfig.RetainPublished),
+	// batch will be marked as published instead.
 	Commit(ctx context.Context, batchID string) error
+	// RecordFailure increments the attempt counter of the specified batch and keeps failure as its last error.
+	// Batch will be parked if attempts reached StorageConfig.MaxAttempts or if failure is unrecoverable
+	// (streams.ErrUnrecoverable).
+	//
+	// Returns true if batch got parked.
+	RecordFailure(ctx context.Context, batchID string, failure error) (bool, error)
+	// PurgePublished removes every published batch with a publish time older than threshold.
+	// Only available on retention mode.
+	//
+	// Returns the total count of removed batches.
+	PurgePublished(ctx context.Context, threshold time.Time) (int64, error)
+	// ListPending retrieves up to limit batches neither forwarded nor parked with an insert time older than threshold.
+	// Listing starts right after the batch pointed by cursor (from the beginning if empty).
+	//
+	// Returns the cursor of the next page, empty if there are no batches left.
+	ListPending(ctx context.Context, threshold time.Time, cursor string, limit int) ([]Batch, string, error)
 }
 
 // A StorageConfig is the main configuration of a Storage.
 type StorageConfig struct {
-	TableName string
+	TableName       string
+	MaxAttempts     int  // Maximum count of failed forward attempts before parking a batch. Parking is disabled if <= 0.
+	RetainPublished bool // Marks batches as published instead removing them. Use a Sweeper to purge published batches.
 }
 
 type NoopStorage struct {
-	WantGetBatch    Batch
-	WantGetBatchErr error
-	WantCommitErr   error
+	WantGetBatch          Batch
+	WantGetBatchErr       error
+	WantCommitErr         error
+	WantRecordFailure     bool
+	WantRecordFailureErr  error
+	WantPurgePublished    int64
+	WantPurgePublishedErr error
+	WantListPending       []Batch
+	WantListPendingCursor string
+	WantListPendingErr    error
 }
 
 var _ Storage = NoopStorage{}
@@ -42,3 +77,15 @@ func (n NoopStorage) GetBatch(_ context.Context, _ string) (Batch, error) {
 func (n NoopStorage) Commit(_ context.Context, _ string) error {
 	return n.WantCommitErr
 }
+
+func (n NoopStorage) RecordFailure(_ context.Context, _ string, _ error) (bool, error) {
+	return n.WantRecordFailure, n.WantRecordFailureErr
+}
+
+func (n NoopStorage) PurgePublished(_ context.Context, _ time.Time) (int64, error) {
+	return n.WantPurgePublished, n.WantPurgePublishedErr
+}
+
+func (n NoopStorage) ListPending(_ context.Context, _ time.Time, _ string, _ int) ([]Batch, string, error) {
+	return n.WantListPending, n.WantListPendingCursor, n.WantListPendingErr
+}
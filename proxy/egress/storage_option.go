@@ -17,3 +17,35 @@ func (e storageTable) Apply(config *StorageConfig) {
 func WithEgressTable(table string) StorageOption {
 	return storageTable{tableName: table}
 }
+
+type storageMaxAttempts struct {
+	maxAttempts int
+}
+
+var _ StorageOption = storageMaxAttempts{}
+
+func (e storageMaxAttempts) Apply(config *StorageConfig) {
+	config.MaxAttempts = e.maxAttempts
+}
+
+// WithMaxAttempts sets the maximum count of failed forward attempts before parking a batch.
+// Parking is disabled if n <= 0.
+func WithMaxAttempts(n int) StorageOption {
+	return storageMaxAttempts{maxAttempts: n}
+}
+
+type storageRetention struct {
+	retainPublished bool
+}
+
+var _ StorageOption = storageRetention{}
+
+func (e storageRetention) Apply(config *StorageConfig) {
+	config.RetainPublished = e.retainPublished
+}
+
+// WithPublishedRetention enables the retention mode, marking batches as published instead removing them from
+// the egress table. Use a Sweeper to purge published batches after a certain time.
+func WithPublishedRetention(enabled bool) StorageOption {
+	return storageRetention{retainPublished: enabled}
+}
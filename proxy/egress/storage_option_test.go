@@ -13,3 +13,17 @@ func TestWithEgressTable(t *testing.T) {
 	a.Apply(&cfg)
 	assert.Equal(t, "foo", cfg.TableName)
 }
+
+func TestWithMaxAttempts(t *testing.T) {
+	a := egress.WithMaxAttempts(5)
+	cfg := egress.StorageConfig{}
+	a.Apply(&cfg)
+	assert.Equal(t, 5, cfg.MaxAttempts)
+}
+
+func TestWithPublishedRetention(t *testing.T) {
+	a := egress.WithPublishedRetention(true)
+	cfg := egress.StorageConfig{}
+	a.Apply(&cfg)
+	assert.True(t, cfg.RetainPublished)
+}
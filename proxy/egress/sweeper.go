@@ -0,0 +1,100 @@
+package egress
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const egressSweeperName = "streams.proxy.egress.sweeper"
+
+// A SweeperConfig is the configuration used by a Sweeper.
+type SweeperConfig struct {
+	Storage      Storage       // A storage a Sweeper instance purges published batches from.
+	Logger       *log.Logger   // Logger to write information to.
+	RetentionTTL time.Duration // Total time a published batch is retained before being purged.
+	Interval     time.Duration // Time duration between each purge process.
+	PurgeTimeout time.Duration // Maximum time duration to wait a purge process to finish.
+}
+
+func NewSweeperDefaultConfig() SweeperConfig {
+	return SweeperConfig{
+		Storage:      nil,
+		Logger:       nil,
+		RetentionTTL: time.Hour * 24,
+		Interval:     time.Minute * 15,
+		PurgeTimeout: time.Second * 30,
+	}
+}
+
+// A Sweeper is an internal component used by an egress proxy agent to purge published batches from a Storage
+// running with retention mode enabled (StorageConfig.RetainPublished).
+type Sweeper struct {
+	cfg           SweeperConfig
+	baseCtx       context.Context
+	baseCtxCancel context.CancelFunc
+	inFlightProc  sync.WaitGroup
+}
+
+// NewSweeper allocates a Sweeper instance.
+func NewSweeper(cfg SweeperConfig) *Sweeper {
+	defCfg := NewSweeperDefaultConfig()
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(os.Stdout, egressSweeperName+": ", 0)
+	}
+	if cfg.RetentionTTL <= 0 {
+		cfg.RetentionTTL = defCfg.RetentionTTL
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defCfg.Interval
+	}
+	if cfg.PurgeTimeout <= 0 {
+		cfg.PurgeTimeout = defCfg.PurgeTimeout
+	}
+	baseCtx, cancel := context.WithCancel(context.Background())
+	return &Sweeper{
+		cfg:           cfg,
+		baseCtx:       baseCtx,
+		baseCtxCancel: cancel,
+	}
+}
+
+// Start initializes the Sweeper instance, blocking the I/O until Sweeper.Shutdown is called.
+func (s *Sweeper) Start() {
+	s.inFlightProc.Add(1)
+	defer s.inFlightProc.Done()
+	s.cfg.Logger.Printf("starting sweeper")
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.baseCtx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
+
+// Sweep purges every published batch older than SweeperConfig.RetentionTTL.
+func (s *Sweeper) Sweep() {
+	scopedCtx, cancel := context.WithTimeout(s.baseCtx, s.cfg.PurgeTimeout)
+	defer cancel()
+	threshold := time.Now().UTC().Add(-s.cfg.RetentionTTL)
+	total, err := s.cfg.Storage.PurgePublished(scopedCtx, threshold)
+	if err != nil {
+		s.cfg.Logger.Printf("failed to purge published batches, error %s", err.Error())
+		return
+	}
+	s.cfg.Logger.Printf("purged <%d> published batches", total)
+}
+
+// Shutdown gracefully shuts down the Sweeper instance.
+func (s *Sweeper) Shutdown() {
+	s.cfg.Logger.Print("shutting sweeper down")
+	s.baseCtxCancel()
+	s.inFlightProc.Wait()
+	s.cfg.Logger.Print("sweeper has been terminated")
+}
@@ -0,0 +1,68 @@
+package egress_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexandria-oss/streams/proxy/egress"
+	"github.com/stretchr/testify/assert"
+)
+
+type purgeStorageSpy struct {
+	egress.NoopStorage
+	thresholds chan time.Time
+}
+
+func (s purgeStorageSpy) PurgePublished(_ context.Context, threshold time.Time) (int64, error) {
+	select {
+	case s.thresholds <- threshold:
+	default:
+	}
+	return s.WantPurgePublished, s.WantPurgePublishedErr
+}
+
+func TestSweeper(t *testing.T) {
+	tests := []struct {
+		name     string
+		inErr    error
+		inTotal  int64
+		inTTL    time.Duration
+		inTicker time.Duration
+	}{
+		{
+			name:     "happy path",
+			inTotal:  3,
+			inTTL:    time.Hour,
+			inTicker: time.Millisecond,
+		},
+		{
+			name:     "storage failure",
+			inErr:    errors.New("generic error"),
+			inTTL:    time.Hour,
+			inTicker: time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := purgeStorageSpy{
+				NoopStorage: egress.NoopStorage{
+					WantPurgePublished:    tt.inTotal,
+					WantPurgePublishedErr: tt.inErr,
+				},
+				thresholds: make(chan time.Time, 1),
+			}
+			sweeper := egress.NewSweeper(egress.SweeperConfig{
+				Storage:      spy,
+				RetentionTTL: tt.inTTL,
+				Interval:     tt.inTicker,
+			})
+			go sweeper.Start()
+			threshold := <-spy.thresholds
+			sweeper.Shutdown()
+			assert.WithinDuration(t, time.Now().UTC().Add(-tt.inTTL), threshold, time.Second)
+		})
+	}
+}
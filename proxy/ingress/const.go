@@ -0,0 +1,5 @@
+package ingress
+
+const (
+	DefaultIngressTableName = "streams_ingress" // default ingress table name.
+)
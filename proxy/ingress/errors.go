@@ -0,0 +1,7 @@
+package ingress
+
+import "errors"
+
+var (
+	ErrMessageAlreadyProcessed = errors.New("streams.ingress: message has been already processed")
+)
@@ -0,0 +1,31 @@
+package ingress
+
+import (
+	"context"
+)
+
+// A Storage is a special kind of storage where incoming traffic (i.e. stream messages) is registered (aka. inbox),
+// so a system may process a message exactly once.
+//
+// Storage implementations SHOULD register messages within the same transaction as the message handler effects
+// (see persistence.SetTransactionContext). Thus, both the message registry and the effects are committed atomically.
+type Storage interface {
+	// Commit registers a message as processed by a worker (e.g. consumer group).
+	// Returns ErrMessageAlreadyProcessed if the message was previously registered.
+	Commit(ctx context.Context, workerID, messageID string) error
+}
+
+// A StorageConfig is the main configuration of a Storage.
+type StorageConfig struct {
+	TableName string
+}
+
+type NoopStorage struct {
+	WantCommitErr error
+}
+
+var _ Storage = NoopStorage{}
+
+func (n NoopStorage) Commit(_ context.Context, _, _ string) error {
+	return n.WantCommitErr
+}
@@ -0,0 +1,19 @@
+package ingress
+
+type StorageOption interface {
+	Apply(*StorageConfig)
+}
+
+type storageTable struct {
+	tableName string
+}
+
+var _ StorageOption = storageTable{}
+
+func (e storageTable) Apply(config *StorageConfig) {
+	config.TableName = e.tableName
+}
+
+func WithIngressTable(table string) StorageOption {
+	return storageTable{tableName: table}
+}
@@ -0,0 +1,15 @@
+package ingress_test
+
+import (
+	"testing"
+
+	"github.com/alexandria-oss/streams/proxy/ingress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithIngressTable(t *testing.T) {
+	a := ingress.WithIngressTable("foo")
+	cfg := ingress.StorageConfig{}
+	a.Apply(&cfg)
+	assert.Equal(t, "foo", cfg.TableName)
+}
@@ -0,0 +1,122 @@
+package streams
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// A RouteMatcher indicates if a Message should be written through a Route.
+type RouteMatcher func(msg Message) bool
+
+// MatchStreamGlob matches messages with a Message.StreamName satisfying the glob pattern
+// (e.g. org.alexandria.payments.*). Uses path.Match syntax.
+func MatchStreamGlob(pattern string) RouteMatcher {
+	return func(msg Message) bool {
+		ok, _ := path.Match(pattern, msg.StreamName)
+		return ok
+	}
+}
+
+// MatchStreamRegex matches messages with a Message.StreamName satisfying the regular expression.
+func MatchStreamRegex(expr *regexp.Regexp) RouteMatcher {
+	return func(msg Message) bool {
+		return expr.MatchString(msg.StreamName)
+	}
+}
+
+// MatchHeader matches messages with the header key set to value.
+func MatchHeader(key, value string) RouteMatcher {
+	return func(msg Message) bool {
+		val, ok := msg.Headers[key]
+		return ok && val == value
+	}
+}
+
+// A Route is a relationship between a set of messages (through a RouteMatcher) and a Writer.
+type Route struct {
+	Match  RouteMatcher
+	Writer Writer
+	// Writer to send a copy of the routed messages to. Useful for migrations between data-in-motion platforms.
+	// Leave nil if mirroring is NOT desired.
+	Mirror Writer
+}
+
+// A RoutingWriter is a Writer which splits message batches across several underlying Writer(s) using Route(s).
+// Routes are evaluated in order, the first matching Route gets the message.
+//
+// Each group of messages is written concurrently; errors are aggregated.
+type RoutingWriter struct {
+	routes   []Route
+	fallback Writer
+}
+
+var _ Writer = RoutingWriter{}
+
+// NewRoutingWriter allocates a RoutingWriter instance. Messages with no matching Route are written using fallback.
+// If fallback is nil, these messages will fail with ErrRouteNotFound.
+func NewRoutingWriter(fallback Writer, routes ...Route) RoutingWriter {
+	return RoutingWriter{
+		routes:   routes,
+		fallback: fallback,
+	}
+}
+
+func (w RoutingWriter) route(msg Message) int {
+	for i, r := range w.routes {
+		if r.Match(msg) {
+			return i
+		}
+	}
+	return len(w.routes) // fallback route
+}
+
+func (w RoutingWriter) Write(ctx context.Context, msgBatch []Message) error {
+	// using route indexes as writers might not be comparable (e.g. structs holding maps).
+	batchBuf := make([][]Message, len(w.routes)+1)
+	for _, msg := range msgBatch {
+		i := w.route(msg)
+		batchBuf[i] = append(batchBuf[i], msg)
+	}
+
+	errs := &multierror.Error{}
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	write := func(writer Writer, batch []Message) {
+		defer wg.Done()
+		if err := writer.Write(ctx, batch); err != nil {
+			mu.Lock() // multi error is not concurrent safe
+			errs = multierror.Append(errs, err)
+			mu.Unlock()
+		}
+	}
+	for i, batch := range batchBuf {
+		if len(batch) == 0 {
+			continue
+		}
+
+		if i == len(w.routes) {
+			if w.fallback == nil {
+				mu.Lock()
+				errs = multierror.Append(errs, ErrRouteNotFound)
+				mu.Unlock()
+				continue
+			}
+			wg.Add(1)
+			go write(w.fallback, batch)
+			continue
+		}
+
+		wg.Add(1)
+		go write(w.routes[i].Writer, batch)
+		if w.routes[i].Mirror != nil {
+			wg.Add(1)
+			go write(w.routes[i].Mirror, batch)
+		}
+	}
+	wg.Wait()
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,122 @@
+package streams_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/alexandria-oss/streams"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingWriter struct {
+	mu   sync.Mutex
+	msgs []string
+	err  error
+}
+
+var _ streams.Writer = &recordingWriter{}
+
+func (r *recordingWriter) Write(_ context.Context, msgBatch []streams.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgBatch {
+		r.msgs = append(r.msgs, msg.ID)
+	}
+	return r.err
+}
+
+func TestRoutingWriter(t *testing.T) {
+	msgBatch := []streams.Message{
+		{ID: "1", StreamName: "org.alexandria.payments.created"},
+		{ID: "2", StreamName: "org.alexandria.users.created"},
+		{ID: "3", StreamName: "internal.cache.flushed", Headers: map[string]string{"scope": "internal"}},
+		{ID: "4", StreamName: "org.alexandria.payments.refunded"},
+		{ID: "5", StreamName: "org.alexandria.books.created"},
+	}
+
+	tests := []struct {
+		name         string
+		withFallback bool
+		paymentsErr  error
+		wantPayments []string
+		wantUsers    []string
+		wantInternal []string
+		wantMirror   []string
+		wantFallback []string
+		wantErr      error
+	}{
+		{
+			name:         "happy path",
+			withFallback: true,
+			wantPayments: []string{"1", "4"},
+			wantUsers:    []string{"2"},
+			wantInternal: []string{"3"},
+			wantMirror:   []string{"1", "4"},
+			wantFallback: []string{"5"},
+		},
+		{
+			name:         "no fallback",
+			withFallback: false,
+			wantPayments: []string{"1", "4"},
+			wantUsers:    []string{"2"},
+			wantInternal: []string{"3"},
+			wantMirror:   []string{"1", "4"},
+			wantErr:      streams.ErrRouteNotFound,
+		},
+		{
+			name:         "writer failure",
+			withFallback: true,
+			paymentsErr:  streams.ErrUnrecoverable,
+			wantPayments: []string{"1", "4"},
+			wantUsers:    []string{"2"},
+			wantInternal: []string{"3"},
+			wantMirror:   []string{"1", "4"},
+			wantFallback: []string{"5"},
+			wantErr:      streams.ErrUnrecoverable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments := &recordingWriter{err: tt.paymentsErr}
+			users := &recordingWriter{}
+			internal := &recordingWriter{}
+			mirror := &recordingWriter{}
+			fallback := &recordingWriter{}
+			var fallbackWriter streams.Writer
+			if tt.withFallback {
+				fallbackWriter = fallback
+			}
+
+			w := streams.NewRoutingWriter(fallbackWriter,
+				streams.Route{
+					Match:  streams.MatchHeader("scope", "internal"),
+					Writer: internal,
+				},
+				streams.Route{
+					Match:  streams.MatchStreamGlob("org.alexandria.payments.*"),
+					Writer: payments,
+					Mirror: mirror,
+				},
+				streams.Route{
+					Match:  streams.MatchStreamRegex(regexp.MustCompile(`^org\.alexandria\.users\..+$`)),
+					Writer: users,
+				},
+			)
+			err := w.Write(context.TODO(), msgBatch)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPayments, payments.msgs)
+			assert.Equal(t, tt.wantUsers, users.msgs)
+			assert.Equal(t, tt.wantInternal, internal.msgs)
+			assert.Equal(t, tt.wantMirror, mirror.msgs)
+			assert.Equal(t, tt.wantFallback, fallback.msgs)
+		})
+	}
+}